@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/icon-project/goloop/btp"
 	"github.com/icon-project/goloop/chain/base"
@@ -15,6 +16,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/eventindex"
 	"github.com/icon-project/goloop/service/state"
 	"github.com/icon-project/goloop/service/transaction"
 
@@ -91,6 +93,32 @@ type Chain interface {
 	Genesis() []byte
 }
 
+// MetricRecorder records latencies of block processing.
+type MetricRecorder interface {
+	OnImport(d time.Duration)
+	OnExecute(d time.Duration)
+}
+
+// MetricRecorderProvider is implemented by the chain providing
+// MetricRecorder for the block manager.
+type MetricRecorderProvider interface {
+	BlockMetricRecorder() MetricRecorder
+}
+
+type nullMetricRecorder struct{}
+
+func (nullMetricRecorder) OnImport(d time.Duration)  {}
+func (nullMetricRecorder) OnExecute(d time.Duration) {}
+
+func metricRecorderOf(chain module.Chain) MetricRecorder {
+	if p, ok := chain.(MetricRecorderProvider); ok {
+		if r := p.BlockMetricRecorder(); r != nil {
+			return r
+		}
+	}
+	return nullMetricRecorder{}
+}
+
 type chainContext struct {
 	syncer  syncer
 	chain   Chain
@@ -130,6 +158,11 @@ type manager struct {
 	handlers       handlerList
 	activeHandlers handlerList
 	handlerContext handlerContext
+
+	// index for event logs of finalized blocks (nil if disabled)
+	eventIndex *eventindex.Index
+
+	mtr MetricRecorder
 }
 
 type handlerList []base.BlockHandler
@@ -193,6 +226,7 @@ type importTask struct {
 	block module.BlockData
 	csi   module.ConsensusInfo
 	flags int
+	start time.Time
 }
 
 type proposeTask struct {
@@ -317,6 +351,7 @@ func (m *manager) _import(
 		block: block,
 		csi:   csi,
 		flags: flags,
+		start: time.Now(),
 		task: task{
 			manager: m,
 			_cb:     cb,
@@ -393,6 +428,7 @@ func (it *importTask) _onValidate(err error) {
 		}
 		it.stop()
 		it.state = validatedOut
+		it.manager.mtr.OnImport(time.Since(it.start))
 		it.cb(it.manager.newCandidate(bn), err)
 	}
 }
@@ -429,6 +465,7 @@ func (it *importTask) _onExecute(err error) {
 			it._handleExecutionError(err)
 			return
 		}
+		it.manager.mtr.OnExecute(time.Since(it.start))
 		validated := it.flags&module.ImportByForce != 0
 		it.out, err = it.in.transit(it.block.NormalTransactions(), it.block, it.csi, it, validated)
 		if err != nil {
@@ -598,6 +635,7 @@ func NewManager(
 		cache:       newCache(ConfigCacheCap),
 		timestamper: timestamper,
 		handlers:    handlers,
+		mtr:         metricRecorderOf(chain),
 	}
 	m.activeHandlers = m.handlers.upTo(
 		m.sm.GetNextBlockVersion(nil),
@@ -609,6 +647,13 @@ func NewManager(
 	m.chainContext.trtr.Logger = chain.Logger().WithFields(log.Fields{
 		log.FieldKeyModule: "BM|TRANS",
 	})
+	if chain.EventIndex() {
+		idx, err := eventindex.New(chain.Database())
+		if err != nil {
+			return nil, err
+		}
+		m.eventIndex = idx
+	}
 	chainPropBucket, err := m.bucketFor(db.ChainProperty)
 	if err != nil {
 		return nil, err
@@ -694,6 +739,11 @@ func NewManager(
 		m.bntr.TraceNew(bn)
 	}
 	m.nmap[string(lastFinalized.ID())] = bn
+	if m.eventIndex != nil {
+		if err := m.indexEvents(lastFinalized); err != nil {
+			return nil, err
+		}
+	}
 	if err := m.initializePCM(); err != nil {
 		return nil, err
 	}
@@ -1029,6 +1079,11 @@ func (m *manager) finalize(bn *bnode, updatePCM bool) error {
 	if err = chainProp.Set(db.Raw(keyLastBlockHeight), block.Height()); err != nil {
 		return err
 	}
+	if m.eventIndex != nil {
+		if err = m.indexEvents(block); err != nil {
+			return err
+		}
+	}
 
 	if updatePCM {
 		nextPCM, err := m.nextPCM.Update(m.finalized.block)
@@ -1056,6 +1111,38 @@ func (m *manager) finalize(bn *bnode, updatePCM bool) error {
 	return nil
 }
 
+// indexEvents adds event logs of the finalized block to the event index.
+// Heights missed since the last indexed height (ex. the node stopped after
+// finalizing the block but before indexing it) are indexed first, so the
+// indexed range has no gaps.
+func (m *manager) indexEvents(blk module.Block) error {
+	r, err := m.eventIndex.Range()
+	if err != nil {
+		return err
+	}
+	if r != nil && r.Last+1 < blk.Height() {
+		m.log.Infof("BackfillEventIndex(from=%d,to=%d)", r.Last+1, blk.Height()-1)
+		for h := r.Last + 1; h < blk.Height(); h++ {
+			b, err := m.getBlockByHeight(h)
+			if err != nil {
+				return err
+			}
+			if err = m.addEventIndex(b); err != nil {
+				return err
+			}
+		}
+	}
+	return m.addEventIndex(blk)
+}
+
+func (m *manager) addEventIndex(blk module.Block) error {
+	rl, err := m.sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return err
+	}
+	return m.eventIndex.Add(blk.Height(), blk.LogsBloom(), rl)
+}
+
 func WriteTransactionLocators(
 	dbase db.Database,
 	height int64,
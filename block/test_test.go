@@ -42,6 +42,10 @@ func (c *testChain) MaxWaitTimeout() time.Duration {
 	return 0
 }
 
+func (c *testChain) EventIndex() bool {
+	return false
+}
+
 func (c *testChain) Database() db.Database {
 	return c.database
 }
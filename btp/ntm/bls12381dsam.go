@@ -0,0 +1,74 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"github.com/icon-project/goloop/common/crypto/bls"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	bls12381DSA = "bls/bls12-381"
+
+	// bls12381DSAKeyLen is the length of a DSA key, which is a compressed
+	// public key followed by its proof of possession.
+	bls12381DSAKeyLen = bls.PublicKeyLen + bls.SignatureLen
+)
+
+// NewBLS12381DSAKey returns a DSA key for the private key. The key is
+// composed of the public key and its proof of possession, which prevents
+// rogue key attacks on the aggregated signatures.
+func NewBLS12381DSAKey(sk *bls.PrivateKey) ([]byte, error) {
+	pop, err := bls.NewProofOfPossession(sk)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 0, bls12381DSAKeyLen)
+	key = append(key, sk.PublicKey().Bytes()...)
+	return append(key, pop.Bytes()...), nil
+}
+
+type bls12381DSAModule struct {
+}
+
+func (s bls12381DSAModule) Name() string {
+	return bls12381DSA
+}
+
+func (s bls12381DSAModule) Verify(pubKey []byte) error {
+	if len(pubKey) != bls12381DSAKeyLen {
+		return errors.Errorf("invalid key length len=%d", len(pubKey))
+	}
+	pk, err := bls.ParsePublicKey(pubKey[:bls.PublicKeyLen])
+	if err != nil {
+		return err
+	}
+	pop, err := bls.ParseSignature(pubKey[bls.PublicKeyLen:])
+	if err != nil {
+		return err
+	}
+	if !pop.VerifyProofOfPossession(pk) {
+		return errors.Errorf("invalid proof of possession key=%x", pubKey)
+	}
+	return nil
+}
+
+var bls12381DSAModuleInstance bls12381DSAModule
+
+func init() {
+	registerDSAModule(bls12381DSAModuleInstance)
+}
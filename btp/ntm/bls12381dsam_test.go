@@ -0,0 +1,45 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto/bls"
+)
+
+func TestBLS12381DSAModule_Verify(t *testing.T) {
+	assert := assert.New(t)
+
+	sk, pk := bls.GenerateKeyPair()
+	dsam := DSAModuleForName(bls12381DSA)
+	key, err := NewBLS12381DSAKey(sk)
+	assert.NoError(err)
+	assert.NoError(dsam.Verify(key))
+
+	assert.Error(dsam.Verify(key[:len(key)-1]))
+	assert.Error(dsam.Verify(pk.Bytes()))
+
+	// proof of possession of other key
+	sk2, _ := bls.GenerateKeyPair()
+	key2, err := NewBLS12381DSAKey(sk2)
+	assert.NoError(err)
+	forged := append(pk.Bytes(), key2[bls.PublicKeyLen:]...)
+	assert.Error(dsam.Verify(forged))
+}
@@ -0,0 +1,401 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"math/bits"
+
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto/bls"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// blsBitmap is a set of validator indexes. Index i is bit (i%8) of byte
+// (i/8).
+type blsBitmap []byte
+
+func newBLSBitmap(n int) blsBitmap {
+	return make(blsBitmap, (n+7)/8)
+}
+
+func (b blsBitmap) validFor(n int) bool {
+	if n < 0 || len(b) != (n+7)/8 {
+		return false
+	}
+	if n%8 != 0 && b[len(b)-1]>>(n%8) != 0 {
+		return false
+	}
+	return true
+}
+
+func (b blsBitmap) has(i int) bool {
+	return i >= 0 && i/8 < len(b) && b[i/8]&(1<<(i%8)) != 0
+}
+
+func (b blsBitmap) set(i int) {
+	b[i/8] |= 1 << (i % 8)
+}
+
+func (b blsBitmap) count() int {
+	c := 0
+	for _, v := range b {
+		c += bits.OnesCount8(v)
+	}
+	return c
+}
+
+func (b blsBitmap) disjoint(b2 blsBitmap) bool {
+	for i := range b {
+		if i < len(b2) && b[i]&b2[i] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b blsBitmap) or(b2 blsBitmap) blsBitmap {
+	res := make(blsBitmap, len(b))
+	for i := range b {
+		res[i] = b[i] | b2[i]
+	}
+	return res
+}
+
+// blsProofPart is a signature of a validator, or an aggregated signature
+// of validators in Bitmap. An aggregated part is returned by ProofPartAt of
+// a proof since signatures of validators cannot be separated from it.
+type blsProofPart struct {
+	Index     int
+	Signature []byte
+	Bitmap    blsBitmap
+}
+
+func (pp *blsProofPart) Bytes() []byte {
+	return codec.MustMarshalToBytes(pp)
+}
+
+func (pp *blsProofPart) bitmap(n int) blsBitmap {
+	if pp.Bitmap != nil {
+		if !pp.Bitmap.validFor(n) {
+			return nil
+		}
+		return pp.Bitmap
+	}
+	if pp.Index < 0 || pp.Index >= n {
+		return nil
+	}
+	bm := newBLSBitmap(n)
+	bm.set(pp.Index)
+	return bm
+}
+
+type blsProof struct {
+	NumValidators int
+	Bitmap        blsBitmap
+	Signature     []byte
+	sig           *bls.Signature
+	bytes         []byte
+}
+
+func newBLSProofFromBytes(bs []byte) (*blsProof, error) {
+	var p blsProof
+	_, err := codec.UnmarshalFromBytes(bs, &p)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Bitmap.validFor(p.NumValidators) {
+		return nil, errors.Errorf("invalid bitmap numValidators=%d bitmap=%x", p.NumValidators, p.Bitmap)
+	}
+	return &p, nil
+}
+
+func (p *blsProof) Bytes() []byte {
+	if p.bytes == nil {
+		p.bytes = codec.MustMarshalToBytes(p)
+	}
+	return p.bytes
+}
+
+func (p *blsProof) signature() (*bls.Signature, error) {
+	if p.sig == nil && p.Signature != nil {
+		sig, err := bls.ParseSignature(p.Signature)
+		if err != nil {
+			return nil, err
+		}
+		p.sig = sig
+	}
+	return p.sig, nil
+}
+
+// Add aggregates the signature of the proof part if its validators are not
+// in the proof yet. Otherwise, it keeps the one with more validators.
+func (p *blsProof) Add(pp module.BTPProofPart) {
+	bpp := pp.(*blsProofPart)
+	bm := bpp.bitmap(p.NumValidators)
+	if bm == nil {
+		return
+	}
+	sig, err := bls.ParseSignature(bpp.Signature)
+	if err != nil {
+		return
+	}
+	if p.Bitmap.disjoint(bm) {
+		if cur, err := p.signature(); err != nil {
+			return
+		} else if cur != nil {
+			if sig, err = bls.AggregateSignatures(cur, sig); err != nil {
+				return
+			}
+		}
+		bm = p.Bitmap.or(bm)
+	} else if bm.count() <= p.Bitmap.count() {
+		return
+	}
+	p.Bitmap = bm
+	p.Signature = sig.Bytes()
+	p.sig = sig
+	p.bytes = nil
+}
+
+func (p *blsProof) ValidatorCount() int {
+	return p.NumValidators
+}
+
+func (p *blsProof) ProofPartAt(i int) module.BTPProofPart {
+	if !p.Bitmap.has(i) || i >= p.NumValidators {
+		return nil
+	}
+	return &blsProofPart{
+		Index:     i,
+		Signature: p.Signature,
+		Bitmap:    p.Bitmap,
+	}
+}
+
+type blsProofContext struct {
+	Validators [][]byte
+	mod        *networkTypeModule
+	bytes      cache.ByteSlice
+	pubKeys    []*bls.PublicKey
+	keyToIndex map[string]int
+}
+
+func newBLSProofContext(
+	mod *networkTypeModule,
+	keys [][]byte,
+) (*blsProofContext, error) {
+	pc := &blsProofContext{
+		Validators: make([][]byte, 0, len(keys)),
+		mod:        mod,
+	}
+	for i, key := range keys {
+		var pk []byte
+		var err error
+		if key != nil {
+			pk, err = mod.AddressFromPubKey(key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to parse key index=%d key=%x", i, key)
+			}
+		}
+		pc.Validators = append(pc.Validators, pk)
+	}
+	return pc, nil
+}
+
+func newBLSProofContextFromBytes(
+	mod *networkTypeModule,
+	bytes []byte,
+) (*blsProofContext, error) {
+	pc := &blsProofContext{
+		mod: mod,
+	}
+	if bytes != nil {
+		_, err := codec.UnmarshalFromBytes(bytes, pc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pc, nil
+}
+
+func (pc *blsProofContext) indexOf(pubKey []byte) (int, bool) {
+	if pc.keyToIndex == nil {
+		pc.keyToIndex = make(map[string]int, len(pc.Validators))
+		for i, pk := range pc.Validators {
+			if pk != nil {
+				pc.keyToIndex[string(pk)] = i
+			}
+		}
+	}
+	idx, ok := pc.keyToIndex[string(pubKey)]
+	return idx, ok
+}
+
+func (pc *blsProofContext) pubKeyAt(i int) (*bls.PublicKey, error) {
+	if pc.pubKeys == nil {
+		pc.pubKeys = make([]*bls.PublicKey, len(pc.Validators))
+	}
+	if pc.pubKeys[i] == nil {
+		if pc.Validators[i] == nil {
+			return nil, errors.Errorf("no public key for validator index=%d", i)
+		}
+		pk, err := bls.ParsePublicKey(pc.Validators[i])
+		if err != nil {
+			return nil, err
+		}
+		pc.pubKeys[i] = pk
+	}
+	return pc.pubKeys[i], nil
+}
+
+func (pc *blsProofContext) NetworkTypeModule() module.NetworkTypeModule {
+	return pc.mod
+}
+
+func (pc *blsProofContext) Bytes() []byte {
+	return pc.bytes.Get(func() []byte {
+		if pc.Validators == nil {
+			return nil
+		}
+		return codec.MustMarshalToBytes(pc)
+	})
+}
+
+// verifyAggregated verifies the aggregated signature of the validators in
+// the bitmap and returns the number of the validators.
+func (pc *blsProofContext) verifyAggregated(dHash []byte, bm blsBitmap, sigBytes []byte) (int, error) {
+	if !bm.validFor(len(pc.Validators)) {
+		return 0, errors.Errorf("invalid bitmap numValidators=%d bitmap=%x", len(pc.Validators), bm)
+	}
+	pubKeys := make([]*bls.PublicKey, 0, bm.count())
+	for i := range pc.Validators {
+		if bm.has(i) {
+			pk, err := pc.pubKeyAt(i)
+			if err != nil {
+				return 0, err
+			}
+			pubKeys = append(pubKeys, pk)
+		}
+	}
+	if len(pubKeys) == 0 {
+		return 0, errors.Errorf("no validator in bitmap")
+	}
+	sig, err := bls.ParseSignature(sigBytes)
+	if err != nil {
+		return 0, err
+	}
+	if !sig.FastAggregateVerify(dHash, pubKeys) {
+		return 0, errors.Errorf("invalid signature bitmap=%x", bm)
+	}
+	return len(pubKeys), nil
+}
+
+// VerifyPart returns validator index and error
+func (pc *blsProofContext) VerifyPart(dHash []byte, pp module.BTPProofPart) (int, error) {
+	bpp := pp.(*blsProofPart)
+	if bpp.Index < 0 || bpp.Index >= len(pc.Validators) {
+		return -1, errors.Errorf("invalid proof part index=%d numValidators=%d", bpp.Index, len(pc.Validators))
+	}
+	if bpp.Bitmap != nil {
+		if !bpp.Bitmap.has(bpp.Index) {
+			return -1, errors.Errorf("invalid proof part. index is not in bitmap index=%d bitmap=%x", bpp.Index, bpp.Bitmap)
+		}
+		if _, err := pc.verifyAggregated(dHash, bpp.Bitmap, bpp.Signature); err != nil {
+			return -1, err
+		}
+		return bpp.Index, nil
+	}
+	pk, err := pc.pubKeyAt(bpp.Index)
+	if err != nil {
+		return -1, err
+	}
+	sig, err := bls.ParseSignature(bpp.Signature)
+	if err != nil {
+		return -1, err
+	}
+	if !sig.Verify(dHash, pk) {
+		return -1, errors.Errorf("invalid proof part. signature mismatch index=%d pc.validators[%d]=%x", bpp.Index, bpp.Index, pc.Validators[bpp.Index])
+	}
+	return bpp.Index, nil
+}
+
+func (pc *blsProofContext) NewProofPartFromBytes(ppBytes []byte) (module.BTPProofPart, error) {
+	var pp blsProofPart
+	_, err := codec.UnmarshalFromBytes(ppBytes, &pp)
+	if err != nil {
+		return nil, err
+	}
+	return &pp, err
+}
+
+func (pc *blsProofContext) Verify(dHash []byte, p module.BTPProof) error {
+	bp := p.(*blsProof)
+	if bp.NumValidators != len(pc.Validators) {
+		return errors.Errorf("validator count mismatch numValidators=%d proof=%d", len(pc.Validators), bp.NumValidators)
+	}
+	valid, err := pc.verifyAggregated(dHash, bp.Bitmap, bp.Signature)
+	if err != nil {
+		return err
+	}
+	if valid <= 2*len(pc.Validators)/3 {
+		return errors.Errorf("not enough proof parts numValidator=%d numProofParts=%d", len(pc.Validators), valid)
+	}
+	return nil
+}
+
+func (pc *blsProofContext) NewProofFromBytes(proofBytes []byte) (module.BTPProof, error) {
+	return newBLSProofFromBytes(proofBytes)
+}
+
+func (pc *blsProofContext) NewProofPart(
+	dHash []byte,
+	wp module.WalletProvider,
+) (module.BTPProofPart, error) {
+	w := wp.WalletFor(bls12381DSA)
+	if w == nil {
+		return nil, errors.Errorf("no wallet for uid=%s dsa=%s", pc.mod.UID(), bls12381DSA)
+	}
+	pk, err := pc.mod.AddressFromPubKey(w.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	idx, ok := pc.indexOf(pk)
+	if !ok {
+		return nil, errors.Errorf("not validator pubKey=%x", pk)
+	}
+	sig, err := w.Sign(dHash)
+	if err != nil {
+		return nil, err
+	}
+	return &blsProofPart{
+		Index:     idx,
+		Signature: sig,
+	}, nil
+}
+
+func (pc *blsProofContext) DSA() string {
+	return bls12381DSA
+}
+
+func (pc *blsProofContext) NewProof() module.BTPProof {
+	return &blsProof{
+		NumValidators: len(pc.Validators),
+		Bitmap:        newBLSBitmap(len(pc.Validators)),
+	}
+}
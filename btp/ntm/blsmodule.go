@@ -0,0 +1,94 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"github.com/icon-project/goloop/common/crypto/bls"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// BLS module makes a proof with one aggregated BLS12-381 signature and
+// a bitmap of the signed validators. It uses keccak256 for hash so that
+// EVM compatible chains can verify the proof cheaply.
+
+const (
+	blsUID = "bls"
+
+	blsBytesByHash = "b" + db.BytesByHash
+	blsListByRoot  = "b" + db.ListByMerkleRootBase
+)
+
+var blsModuleInstance *networkTypeModule
+
+type blsModuleCore struct{}
+
+func (m *blsModuleCore) UID() string {
+	return blsUID
+}
+
+func (m *blsModuleCore) AppendHash(out []byte, data []byte) []byte {
+	return appendKeccak256(out, data)
+}
+
+func (m *blsModuleCore) DSAModule() module.DSAModule {
+	return bls12381DSAModuleInstance
+}
+
+func (m *blsModuleCore) NewProofContextFromBytes(bs []byte) (proofContextCore, error) {
+	return newBLSProofContextFromBytes(blsModuleInstance, bs)
+}
+
+func (m *blsModuleCore) NewProofContext(keys [][]byte) (proofContextCore, error) {
+	return newBLSProofContext(blsModuleInstance, keys)
+}
+
+// AddressFromPubKey returns the compressed public key. A validator is
+// identified by its public key since it's required for verification.
+func (m *blsModuleCore) AddressFromPubKey(pubKey []byte) ([]byte, error) {
+	pk, err := bls.ParsePublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return pk.Bytes(), nil
+}
+
+func (m *blsModuleCore) BytesByHashBucket() db.BucketID {
+	return blsBytesByHash
+}
+
+func (m *blsModuleCore) ListByMerkleRootBucket() db.BucketID {
+	return blsListByRoot
+}
+
+func (m *blsModuleCore) NewProofFromBytes(bs []byte) (module.BTPProof, error) {
+	return newBLSProofFromBytes(bs)
+}
+
+// NetworkTypeKeyFromDSAKey returns the public key in the DSA key dropping
+// the proof of possession, which is verified on registration.
+func (m *blsModuleCore) NetworkTypeKeyFromDSAKey(key []byte) ([]byte, error) {
+	if len(key) != bls12381DSAKeyLen {
+		return nil, errors.Errorf("invalid DSA key length len=%d", len(key))
+	}
+	return key[:bls.PublicKeyLen:bls.PublicKeyLen], nil
+}
+
+func init() {
+	blsModuleInstance = register(blsUID, &blsModuleCore{})
+}
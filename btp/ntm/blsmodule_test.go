@@ -0,0 +1,236 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto/bls"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func newBLSWalletProvider() (*walletProvider, module.BaseWallet) {
+	w := wallet.NewBLS()
+	wp := walletProvider{
+		wallets: map[string]module.BaseWallet{
+			bls12381DSA: w,
+		},
+	}
+	return &wp, w
+}
+
+func newBLSTestSetup(t *testing.T, count int) *testSetup {
+	s := &testSetup{
+		assert:  assert.New(t),
+		count:   count,
+		wallets: make([]*walletProvider, 0, count),
+		pubKeys: make([][]byte, 0, count),
+		addrs:   make([][]byte, 0, count),
+	}
+	for i := 0; i < count; i++ {
+		wp, w := newBLSWalletProvider()
+		s.wallets = append(s.wallets, wp)
+		s.pubKeys = append(s.pubKeys, w.PublicKey())
+		s.addrs = append(s.addrs, w.PublicKey())
+	}
+	var err error
+	s.pc, err = blsModuleInstance.NewProofContext(s.pubKeys)
+	assert.NoError(t, err)
+	return s
+}
+
+func TestBLSModule_Basics(t *testing.T) {
+	assert := assert.New(t)
+	mod := ForUID(blsUID)
+	assert.EqualValues(blsUID, mod.UID())
+	assert.EqualValues(bls12381DSA, mod.DSA())
+
+	sk, pk := bls.GenerateKeyPair()
+	key, err := NewBLS12381DSAKey(sk)
+	assert.NoError(err)
+	ntKey, err := mod.NetworkTypeKeyFromDSAKey(key)
+	assert.NoError(err)
+	assert.EqualValues(pk.Bytes(), ntKey)
+	_, err = mod.NetworkTypeKeyFromDSAKey(pk.Bytes())
+	assert.Error(err)
+
+	addr, err := mod.AddressFromPubKey(ntKey)
+	assert.NoError(err)
+	assert.EqualValues(pk.Bytes(), addr)
+
+	ctx, err := mod.NewProofContext([][]byte{ntKey, nil})
+	assert.NoError(err)
+	assert.EqualValues(blsUID, ctx.UID())
+	assert.EqualValues(bls12381DSA, ctx.DSA())
+	assert.EqualValues(mod.Hash(ctx.Bytes()), ctx.Hash())
+	ctx2, err := mod.NewProofContextFromBytes(ctx.Bytes())
+	assert.NoError(err)
+	assert.EqualValues(ctx.Bytes(), ctx2.Bytes())
+
+	_, err = mod.NewProofContext([][]byte{key})
+	assert.Error(err)
+}
+
+func TestBLSProofContext_NewProofPart(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	msgHash := keccak256([]byte("abc"))
+	for i := 0; i < s.count; i++ {
+		pp, err := s.pc.NewProofPart(msgHash, s.wallets[i])
+		s.assert.NoError(err)
+		idx, err := s.pc.VerifyPart(msgHash, pp)
+		s.assert.NoError(err)
+		s.assert.Equal(i, idx)
+
+		pp2, err := s.pc.NewProofPartFromBytes(pp.Bytes())
+		s.assert.NoError(err)
+		_, err = s.pc.VerifyPart(msgHash, pp2)
+		s.assert.NoError(err)
+		_, err = s.pc.VerifyPart(keccak256([]byte("abcd")), pp2)
+		s.assert.Error(err)
+	}
+
+	wp, _ := newBLSWalletProvider()
+	_, err := s.pc.NewProofPart(msgHash, wp)
+	s.assert.Error(err)
+	wp2, _ := newSecp256k1WalletProvider()
+	_, err = s.pc.NewProofPart(msgHash, wp2)
+	s.assert.Error(err)
+}
+
+func TestBLSProofContext_Verify(t *testing.T) {
+	msgHash := keccak256([]byte("abc"))
+	testCase := []struct {
+		ok      bool
+		ppCount int
+		pkCount int
+	}{
+		{false, 0, 1},
+		{true, 1, 1},
+		{false, 1, 2},
+		{true, 2, 2},
+		{false, 2, 3},
+		{true, 3, 3},
+		{false, 2, 4},
+		{true, 3, 4},
+		{false, 4, 6},
+		{true, 5, 6},
+		{false, 6, 10},
+		{true, 7, 10},
+	}
+	for _, c := range testCase {
+		s := newBLSTestSetup(t, c.pkCount)
+		p := s.newProofOfLen(c.ppCount, msgHash)
+		p2, err := s.pc.NewProofFromBytes(p.Bytes())
+		s.assert.NoError(err)
+		for _, pf := range []module.BTPProof{p, p2} {
+			err = s.pc.Verify(msgHash, pf)
+			if c.ok {
+				s.assert.NoError(err, "Verify exp=%v ppCount=%d pkCount=%d", c.ok, c.ppCount, c.pkCount)
+			} else {
+				s.assert.Error(err, "Verify exp=%v ppCount=%d pkCount=%d", c.ok, c.ppCount, c.pkCount)
+			}
+		}
+		s.assert.Equal(c.pkCount, p2.ValidatorCount())
+	}
+}
+
+func TestBLSProofContext_Verify_Fail(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	s2 := newBLSTestSetup(t, 4)
+	msgHash := keccak256([]byte("abc"))
+
+	p := s.newProofOfLen(2, msgHash)
+	pp, err := s2.pc.NewProofPart(msgHash, s2.wallets[2])
+	s.assert.NoError(err)
+	p.Add(pp)
+	s.assert.Error(s.pc.Verify(msgHash, p))
+
+	p = s.newProofOfLen(2, msgHash)
+	pp, err = s.pc.NewProofPart(msgHash, s.wallets[0])
+	s.assert.NoError(err)
+	p.Add(pp)
+	s.assert.Error(s.pc.Verify(msgHash, p))
+
+	p = s.newProofOfLen(3, msgHash)
+	s.assert.Error(s.pc.Verify(keccak256([]byte("abcd")), p))
+	s.assert.Error(s2.pc.Verify(msgHash, p))
+	pc3, err := blsModuleInstance.NewProofContext(s.pubKeys[:3])
+	s.assert.NoError(err)
+	s.assert.Error(pc3.Verify(msgHash, p))
+}
+
+func TestBLSProof_ProofPartAt(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	msgHash := keccak256([]byte("abc"))
+	p := s.newProofOfLen(3, msgHash)
+	p, err := s.pc.NewProofFromBytes(p.Bytes())
+	s.assert.NoError(err)
+
+	// rebuild the proof from the parts as consensus does with the votes
+	p2 := s.pc.NewProof()
+	for i := 0; i < p.ValidatorCount(); i++ {
+		pp := p.ProofPartAt(i)
+		if i == 3 {
+			s.assert.Nil(pp)
+			continue
+		}
+		pp, err = s.pc.NewProofPartFromBytes(pp.Bytes())
+		s.assert.NoError(err)
+		idx, err := s.pc.VerifyPart(msgHash, pp)
+		s.assert.NoError(err)
+		s.assert.Equal(i, idx)
+		p2.Add(pp)
+	}
+	s.assert.EqualValues(p.Bytes(), p2.Bytes())
+
+	// an aggregated part merges with the parts of the other validators
+	pp, err := s.pc.NewProofPart(msgHash, s.wallets[3])
+	s.assert.NoError(err)
+	p2.Add(pp)
+	s.assert.NoError(s.pc.Verify(msgHash, p2))
+	s.assert.Equal(4, p2.(*blsProof).Bitmap.count())
+
+	// an aggregated part for the wrong validator
+	bpp := p.ProofPartAt(0).(*blsProofPart)
+	bpp.Index = 3
+	_, err = s.pc.VerifyPart(msgHash, bpp)
+	s.assert.Error(err)
+}
+
+func TestBLSProof_codec(t *testing.T) {
+	s := newBLSTestSetup(t, 100)
+	msgHash := keccak256([]byte("abc"))
+	p := s.newProofOfLen(67, msgHash)
+	bp := p.(*blsProof)
+	pBytes := codec.MustMarshalToBytes(bp)
+	s.assert.EqualValues(pBytes, p.Bytes())
+	var bp2 blsProof
+	codec.MustUnmarshalFromBytes(pBytes, &bp2)
+	s.assert.NoError(s.pc.Verify(msgHash, &bp2))
+
+	// one signature and a bitmap regardless of the number of signatures
+	s.assert.Less(len(pBytes), bls.SignatureLen+100/8+16)
+
+	pcBytes := s.pc.Bytes()
+	pc2, err := blsModuleInstance.NewProofContextFromBytes(pcBytes)
+	s.assert.NoError(err)
+	s.assert.NoError(pc2.Verify(msgHash, p))
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+func TestEd25519DSAModule_Verify(t *testing.T) {
+	assert := assert.New(t)
+
+	w := wallet.NewEd25519()
+	dsam := DSAModuleForName(ed25519DSA)
+	assert.NoError(dsam.Verify(w.PublicKey()))
+
+	pkBytes := w.PublicKey()
+	assert.Error(dsam.Verify(pkBytes[:len(pkBytes)-1]))
+	assert.Error(dsam.Verify(wallet.New().PublicKey()))
+}
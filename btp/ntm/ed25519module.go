@@ -0,0 +1,88 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"crypto/sha256"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+)
+
+// Ed25519 module makes a proof with ed25519 signatures of validators. It
+// uses sha256 for hash, and a validator is identified by its public key
+// since ed25519 signatures are not recoverable.
+
+const (
+	ed25519UID = "ed25519"
+
+	ed25519BytesByHash = "d" + db.BytesByHash
+	ed25519ListByRoot  = "d" + db.ListByMerkleRootBase
+)
+
+var ed25519ModuleInstance *networkTypeModule
+
+type ed25519ModuleCore struct{}
+
+func (m *ed25519ModuleCore) UID() string {
+	return ed25519UID
+}
+
+func (m *ed25519ModuleCore) AppendHash(out []byte, data []byte) []byte {
+	h := sha256.New()
+	h.Write(data)
+	return h.Sum(out)
+}
+
+func (m *ed25519ModuleCore) DSAModule() module.DSAModule {
+	return ed25519DSAModuleInstance
+}
+
+func (m *ed25519ModuleCore) NewProofContextFromBytes(bs []byte) (proofContextCore, error) {
+	return newEd25519ProofContextFromBytes(ed25519ModuleInstance, bs)
+}
+
+func (m *ed25519ModuleCore) NewProofContext(keys [][]byte) (proofContextCore, error) {
+	return newEd25519ProofContext(ed25519ModuleInstance, keys)
+}
+
+func (m *ed25519ModuleCore) AddressFromPubKey(pubKey []byte) ([]byte, error) {
+	if err := ed25519DSAModuleInstance.Verify(pubKey); err != nil {
+		return nil, err
+	}
+	return pubKey, nil
+}
+
+func (m *ed25519ModuleCore) BytesByHashBucket() db.BucketID {
+	return ed25519BytesByHash
+}
+
+func (m *ed25519ModuleCore) ListByMerkleRootBucket() db.BucketID {
+	return ed25519ListByRoot
+}
+
+func (m *ed25519ModuleCore) NewProofFromBytes(bs []byte) (module.BTPProof, error) {
+	return newEd25519ProofFromBytes(bs)
+}
+
+func (m *ed25519ModuleCore) NetworkTypeKeyFromDSAKey(key []byte) ([]byte, error) {
+	return key, nil
+}
+
+func init() {
+	ed25519ModuleInstance = register(ed25519UID, &ed25519ModuleCore{})
+}
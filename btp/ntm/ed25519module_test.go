@@ -0,0 +1,169 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func newEd25519WalletProvider() (*walletProvider, module.BaseWallet) {
+	w := wallet.NewEd25519()
+	wp := walletProvider{
+		wallets: map[string]module.BaseWallet{
+			ed25519DSA: w,
+		},
+	}
+	return &wp, w
+}
+
+func newEd25519TestSetup(t *testing.T, count int) *testSetup {
+	s := &testSetup{
+		assert:  assert.New(t),
+		count:   count,
+		wallets: make([]*walletProvider, 0, count),
+		pubKeys: make([][]byte, 0, count),
+		addrs:   make([][]byte, 0, count),
+	}
+	for i := 0; i < count; i++ {
+		wp, w := newEd25519WalletProvider()
+		s.wallets = append(s.wallets, wp)
+		s.pubKeys = append(s.pubKeys, w.PublicKey())
+		s.addrs = append(s.addrs, w.PublicKey())
+	}
+	var err error
+	s.pc, err = ed25519ModuleInstance.NewProofContext(s.pubKeys)
+	assert.NoError(t, err)
+	return s
+}
+
+func sha256Sum(data []byte) []byte {
+	h := sha256.Sum256(data)
+	return h[:]
+}
+
+func TestEd25519Module_Basics(t *testing.T) {
+	assert := assert.New(t)
+	mod := ForUID(ed25519UID)
+	assert.EqualValues(ed25519UID, mod.UID())
+	assert.EqualValues(ed25519DSA, mod.DSA())
+	assert.EqualValues(sha256Sum([]byte("abc")), mod.Hash([]byte("abc")))
+
+	w := wallet.NewEd25519()
+	addr, err := mod.AddressFromPubKey(w.PublicKey())
+	assert.NoError(err)
+	assert.EqualValues(w.PublicKey(), addr)
+	_, err = mod.AddressFromPubKey(wallet.New().PublicKey())
+	assert.Error(err)
+
+	s := newEd25519TestSetup(t, 4)
+	assert.EqualValues(ed25519UID, s.pc.UID())
+	assert.EqualValues(ed25519DSA, s.pc.DSA())
+	assert.EqualValues(mod.Hash(s.pc.Bytes()), s.pc.Hash())
+	pc2, err := mod.NewProofContextFromBytes(s.pc.Bytes())
+	assert.NoError(err)
+	assert.EqualValues(s.pc.Bytes(), pc2.Bytes())
+}
+
+func TestEd25519ProofContext_NewProofPart(t *testing.T) {
+	s := newEd25519TestSetup(t, 4)
+	msgHash := sha256Sum([]byte("abc"))
+	for i := 0; i < s.count; i++ {
+		pp, err := s.pc.NewProofPart(msgHash, s.wallets[i])
+		s.assert.NoError(err)
+		pp2, err := s.pc.NewProofPartFromBytes(pp.Bytes())
+		s.assert.NoError(err)
+		idx, err := s.pc.VerifyPart(msgHash, pp2)
+		s.assert.NoError(err)
+		s.assert.Equal(i, idx)
+		_, err = s.pc.VerifyPart(sha256Sum([]byte("abcd")), pp2)
+		s.assert.Error(err)
+	}
+
+	wp, _ := newEd25519WalletProvider()
+	_, err := s.pc.NewProofPart(msgHash, wp)
+	s.assert.Error(err)
+	wp2, _ := newSecp256k1WalletProvider()
+	_, err = s.pc.NewProofPart(msgHash, wp2)
+	s.assert.Error(err)
+}
+
+func TestEd25519ProofContext_Verify(t *testing.T) {
+	msgHash := sha256Sum([]byte("abc"))
+	testCase := []struct {
+		ok      bool
+		ppCount int
+		pkCount int
+	}{
+		{false, 0, 1},
+		{true, 1, 1},
+		{false, 1, 2},
+		{true, 2, 2},
+		{false, 2, 3},
+		{true, 3, 3},
+		{false, 2, 4},
+		{true, 3, 4},
+		{false, 4, 6},
+		{true, 5, 6},
+	}
+	for _, c := range testCase {
+		s := newEd25519TestSetup(t, c.pkCount)
+		p := s.newProofOfLen(c.ppCount, msgHash)
+		p2, err := s.pc.NewProofFromBytes(p.Bytes())
+		s.assert.NoError(err)
+		for _, pf := range []module.BTPProof{p, p2} {
+			err = s.pc.Verify(msgHash, pf)
+			if c.ok {
+				s.assert.NoError(err, "Verify exp=%v ppCount=%d pkCount=%d", c.ok, c.ppCount, c.pkCount)
+			} else {
+				s.assert.Error(err, "Verify exp=%v ppCount=%d pkCount=%d", c.ok, c.ppCount, c.pkCount)
+			}
+		}
+	}
+}
+
+func TestEd25519ProofContext_Verify_FailInvalidPK(t *testing.T) {
+	s := newEd25519TestSetup(t, 4)
+	s2 := newEd25519TestSetup(t, 4)
+	msgHash := sha256Sum([]byte("abc"))
+	p := s.newProofOfLen(2, msgHash)
+	pp, err := s2.pc.NewProofPart(msgHash, s2.wallets[2])
+	s.assert.NoError(err)
+	p.Add(pp)
+	s.assert.Error(s.pc.Verify(msgHash, p))
+}
+
+func TestEd25519Proof_codec(t *testing.T) {
+	s := newEd25519TestSetup(t, 4)
+	msgHash := sha256Sum([]byte("abc"))
+	p := s.newProofOfLen(3, msgHash)
+	ep := p.(*ed25519Proof)
+	pBytes := codec.MustMarshalToBytes(ep)
+	s.assert.EqualValues(pBytes, p.Bytes())
+	var ep2 ed25519Proof
+	codec.MustUnmarshalFromBytes(pBytes, &ep2)
+	s.assert.NoError(s.pc.Verify(msgHash, &ep2))
+	s.assert.Nil(ep2.ProofPartAt(3))
+	_, err := s.pc.VerifyPart(msgHash, ep2.ProofPartAt(1))
+	s.assert.NoError(err)
+}
@@ -0,0 +1,270 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package archive implements the portable archive of blocks.
+//
+// An archive is a gzip compressed stream of items encoded with codec.BC.
+// It starts with Header, then StateEntry items follow if it has FlagState,
+// then Block items for each height from Header.From to Header.To, and it
+// ends with Trailer. Each item except Header is prefixed with its type.
+// Refer doc/archive_format.md for details.
+package archive
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	Magic   = "goloop-archive"
+	Version = 1
+)
+
+// Flags of the archive.
+const (
+	// FlagReceipts is set if Block items have receipts.
+	FlagReceipts = 1 << iota
+	// FlagState is set if the archive has the state of the block at
+	// Header.From-1, so it can be imported to the empty chain.
+	FlagState
+)
+
+// Types of items following Header.
+const (
+	TypeState   = 1
+	TypeBlock   = 2
+	TypeTrailer = 3
+)
+
+type Header struct {
+	Magic   string
+	Version int
+	Codec   string
+	CID     int64
+	NID     int64
+	From    int64
+	To      int64
+	Flags   int
+}
+
+func (h *Header) Has(flags int) bool {
+	return (h.Flags & flags) == flags
+}
+
+// StateEntry is an entry of the database for the state.
+type StateEntry struct {
+	Bucket string
+	Key    []byte
+	Value  []byte
+}
+
+// Block has a block and the votes for the block. Block has the header and
+// the body including transactions as BlockData.Marshal writes. Votes is
+// the bytes of commit votes for the block, which are from the next block.
+// Receipts are available only if the archive has FlagReceipts.
+type Block struct {
+	Height         int64
+	Block          []byte
+	Votes          []byte
+	PatchReceipts  [][]byte
+	NormalReceipts [][]byte
+}
+
+// Trailer is the last item of the archive for detecting truncation.
+type Trailer struct {
+	States int64
+	Blocks int64
+}
+
+type Writer struct {
+	header  Header
+	gw      *gzip.Writer
+	enc     codec.EncodeAndCloser
+	height  int64
+	trailer Trailer
+}
+
+// NewWriter writes the header to w and returns the writer for following
+// items. Close shall be called to complete the archive.
+func NewWriter(w io.Writer, h *Header) (*Writer, error) {
+	if h.From < 1 || h.From > h.To {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d)", h.From, h.To)
+	}
+	gw := gzip.NewWriter(w)
+	aw := &Writer{
+		header: *h,
+		gw:     gw,
+		enc:    codec.BC.NewEncoder(gw),
+		height: h.From,
+	}
+	aw.header.Magic = Magic
+	aw.header.Version = Version
+	aw.header.Codec = codec.BC.Name()
+	if err := aw.enc.Encode(&aw.header); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+func (w *Writer) WriteState(e *StateEntry) error {
+	if !w.header.Has(FlagState) {
+		return errors.InvalidStateError.New("NoStateFlag")
+	}
+	if w.trailer.Blocks > 0 {
+		return errors.InvalidStateError.New("StateAfterBlock")
+	}
+	w.trailer.States += 1
+	return w.enc.EncodeMulti(TypeState, e)
+}
+
+func (w *Writer) WriteBlock(b *Block) error {
+	if b.Height != w.height || b.Height > w.header.To {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(height=%d,exp=%d)", b.Height, w.height)
+	}
+	if !w.header.Has(FlagReceipts) && (b.PatchReceipts != nil || b.NormalReceipts != nil) {
+		return errors.IllegalArgumentError.New("NoReceiptsFlag")
+	}
+	w.height += 1
+	w.trailer.Blocks += 1
+	return w.enc.EncodeMulti(TypeBlock, b)
+}
+
+// Close writes the trailer after all blocks are written. It doesn't
+// close the underlying writer.
+func (w *Writer) Close() error {
+	if w.height != w.header.To+1 {
+		return errors.InvalidStateError.Errorf(
+			"MissingBlocks(next=%d,to=%d)", w.height, w.header.To)
+	}
+	if err := w.enc.EncodeMulti(TypeTrailer, &w.trailer); err != nil {
+		return err
+	}
+	if err := w.enc.Close(); err != nil {
+		return err
+	}
+	return w.gw.Close()
+}
+
+type Reader struct {
+	header  Header
+	gr      *gzip.Reader
+	dec     codec.DecodeAndCloser
+	height  int64
+	trailer Trailer
+	done    bool
+}
+
+// NewReader reads and verifies the header from r.
+func NewReader(r io.Reader) (*Reader, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidArchive")
+	}
+	ar := &Reader{
+		gr:  gr,
+		dec: codec.BC.NewDecoder(gr),
+	}
+	if err := ar.dec.Decode(&ar.header); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidArchiveHeader")
+	}
+	if ar.header.Magic != Magic {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidArchiveMagic(magic=%q)", ar.header.Magic)
+	}
+	if ar.header.Version != Version {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedArchiveVersion(version=%d)", ar.header.Version)
+	}
+	if ar.header.Codec != codec.BC.Name() {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedArchiveCodec(codec=%s)", ar.header.Codec)
+	}
+	if ar.header.From < 1 || ar.header.From > ar.header.To {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d)", ar.header.From, ar.header.To)
+	}
+	ar.height = ar.header.From
+	return ar, nil
+}
+
+func (r *Reader) Header() *Header {
+	return &r.header
+}
+
+// Next returns the next item, which is *StateEntry or *Block. It returns
+// io.EOF after the trailer is verified.
+func (r *Reader) Next() (interface{}, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	var typ int
+	if err := r.dec.Decode(&typ); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, errors.CriticalFormatError.Wrap(err, "InvalidArchiveItem")
+	}
+	switch typ {
+	case TypeState:
+		if !r.header.Has(FlagState) || r.trailer.Blocks > 0 {
+			return nil, errors.CriticalFormatError.New("UnexpectedStateEntry")
+		}
+		e := new(StateEntry)
+		if err := r.dec.Decode(e); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidStateEntry")
+		}
+		r.trailer.States += 1
+		return e, nil
+	case TypeBlock:
+		b := new(Block)
+		if err := r.dec.Decode(b); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidBlock")
+		}
+		if b.Height != r.height || b.Height > r.header.To {
+			return nil, errors.CriticalFormatError.Errorf(
+				"UnexpectedBlock(height=%d,exp=%d)", b.Height, r.height)
+		}
+		r.height += 1
+		r.trailer.Blocks += 1
+		return b, nil
+	case TypeTrailer:
+		t := new(Trailer)
+		if err := r.dec.Decode(t); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidTrailer")
+		}
+		if *t != r.trailer || r.height != r.header.To+1 {
+			return nil, errors.CriticalFormatError.Errorf(
+				"InvalidTrailer(states=%d,blocks=%d,read=%d/%d)",
+				t.States, t.Blocks, r.trailer.States, r.trailer.Blocks)
+		}
+		r.done = true
+		return nil, io.EOF
+	default:
+		return nil, errors.CriticalFormatError.Errorf("UnknownItemType(type=%d)", typ)
+	}
+}
+
+func (r *Reader) Close() error {
+	if err := r.dec.Close(); err != nil {
+		return err
+	}
+	return r.gr.Close()
+}
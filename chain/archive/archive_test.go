@@ -0,0 +1,97 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package archive
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestArchive(t *testing.T, flags int) []byte {
+	buf := bytes.NewBuffer(nil)
+	w, err := NewWriter(buf, &Header{CID: 1, NID: 2, From: 10, To: 12, Flags: flags})
+	assert.NoError(t, err)
+	if (flags & FlagState) != 0 {
+		assert.NoError(t, w.WriteState(&StateEntry{Bucket: "S", Key: []byte{1}, Value: []byte{2}}))
+	}
+	for h := int64(10); h <= 12; h++ {
+		b := &Block{Height: h, Block: []byte{byte(h)}, Votes: []byte{byte(h + 1)}}
+		if (flags & FlagReceipts) != 0 {
+			b.NormalReceipts = [][]byte{{byte(h)}}
+		}
+		assert.NoError(t, w.WriteBlock(b))
+	}
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestArchive_Basic(t *testing.T) {
+	bs := writeTestArchive(t, FlagState|FlagReceipts)
+
+	r, err := NewReader(bytes.NewReader(bs))
+	assert.NoError(t, err)
+	h := r.Header()
+	assert.EqualValues(t, 1, h.CID)
+	assert.EqualValues(t, 2, h.NID)
+	assert.True(t, h.Has(FlagState|FlagReceipts))
+
+	item, err := r.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, &StateEntry{Bucket: "S", Key: []byte{1}, Value: []byte{2}}, item)
+	for height := int64(10); height <= 12; height++ {
+		item, err = r.Next()
+		assert.NoError(t, err)
+		b, ok := item.(*Block)
+		assert.True(t, ok)
+		assert.Equal(t, height, b.Height)
+		assert.Equal(t, []byte{byte(height + 1)}, b.Votes)
+		assert.Equal(t, [][]byte{{byte(height)}}, b.NormalReceipts)
+	}
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+	assert.NoError(t, r.Close())
+}
+
+func TestArchive_Invalid(t *testing.T) {
+	_, err := NewWriter(ioutil.Discard, &Header{From: 0, To: 1})
+	assert.Error(t, err)
+
+	w, err := NewWriter(ioutil.Discard, &Header{From: 1, To: 2})
+	assert.NoError(t, err)
+	assert.Error(t, w.WriteState(&StateEntry{}))
+	assert.Error(t, w.WriteBlock(&Block{Height: 2}))
+	assert.NoError(t, w.WriteBlock(&Block{Height: 1}))
+	assert.Error(t, w.Close())
+
+	_, err = NewReader(bytes.NewReader([]byte("invalid")))
+	assert.Error(t, err)
+
+	// truncated archive
+	bs := writeTestArchive(t, 0)
+	r, err := NewReader(bytes.NewReader(bs[:len(bs)-20]))
+	if err == nil {
+		for err == nil {
+			_, err = r.Next()
+		}
+	}
+	assert.Error(t, err)
+	assert.NotEqual(t, io.EOF, err)
+}
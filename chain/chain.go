@@ -79,6 +79,10 @@ type singleChain struct {
 
 	dbLock   sync.RWMutex
 	database db.Database
+	pdb      *pruningDB
+	journal  *dbJournal
+	jstop    chan struct{}
+	bkLock   sync.Mutex
 	vld      module.CommitVoteSetDecoder
 	pd       module.PatchDecoder
 	sm       module.ServiceManager
@@ -96,6 +100,7 @@ type singleChain struct {
 	logger log.Logger
 
 	regulator *regulator
+	sp        *statePruner
 
 	state      State
 	lastErr    error
@@ -179,6 +184,10 @@ func (c *singleChain) MetricContext() context.Context {
 	return c.metricCtx
 }
 
+func (c *singleChain) BlockMetricRecorder() block.MetricRecorder {
+	return metric.NewBlockMetric(c.metricCtx)
+}
+
 func (c *singleChain) ConcurrencyLevel() int {
 	if c.cfg.ConcurrencyLevel > 1 {
 		return c.cfg.ConcurrencyLevel
@@ -246,10 +255,38 @@ func (c *singleChain) NephewsLimit() int {
 	return ConfigDefaultNephewLimit
 }
 
+func (c *singleChain) TxPoolSenderLimit() int {
+	if c.cfg.TxPoolSenderLimit > 0 {
+		return c.cfg.TxPoolSenderLimit
+	} else if c.cfg.TxPoolSenderLimit < 0 {
+		return 0
+	}
+	return ConfigDefaultTxPoolSenderLimit
+}
+
+func (c *singleChain) FastSyncWindow() int {
+	return c.cfg.FastSyncWindow
+}
+
 func (c *singleChain) ValidateTxOnSend() bool {
 	return c.cfg.ValidateTxOnSend
 }
 
+func (c *singleChain) EventIndex() bool {
+	return c.cfg.EventIndex
+}
+
+func (c *singleChain) pruneInterval() int64 {
+	if c.cfg.PruneInterval > 0 {
+		return c.cfg.PruneInterval
+	}
+	return ConfigDefaultPruneInterval
+}
+
+func (c *singleChain) pruner() *statePruner {
+	return c.sp
+}
+
 func (c *singleChain) State() (string, int64, error) {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
@@ -409,16 +446,47 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 		return errors.Wrapf(err, "UnknownCacheStrategy(%s)", c.cfg.NodeCache)
 	}
 	cacheDir := path.Join(chainDir, DefaultCacheDir)
+	journal, err := openJournal(chainDir, c.logger)
+	if err != nil {
+		_ = cdb.Close()
+		return err
+	}
+	if journal != nil {
+		cdb = db.WithJournal(cdb, journal)
+	}
+	c.journal = journal
+	cdb = db.WithMonitor(cdb, metric.NewDatabaseMetric(c.metricCtx))
+	if c.cfg.PruneKeepBlocks > 0 {
+		c.pdb = newPruningDB(cdb)
+		cdb = c.pdb
+	}
 	c.database = cache.AttachManager(cdb, cacheDir, mLevel, fLevel, stores)
 	return nil
 }
 
+// ensurePruningDatabase opens the database again if the database isn't
+// prepared for the pruner as configured.
+func (c *singleChain) ensurePruningDatabase() {
+	c.dbLock.Lock()
+	prepared := c.pdb != nil
+	c.dbLock.Unlock()
+	if prepared != (c.cfg.PruneKeepBlocks > 0) {
+		c.releaseDatabase()
+		c.ensureDatabase()
+	}
+}
+
 func (c *singleChain) releaseDatabase() {
 	c.dbLock.Lock()
 	defer c.dbLock.Unlock()
 	if c.database != nil {
 		c.database.Close()
 		c.database = nil
+		c.pdb = nil
+	}
+	if c.journal != nil {
+		c.journal.Close()
+		c.journal = nil
 	}
 }
 
@@ -445,6 +513,7 @@ func (c *singleChain) _init() error {
 		c.plt = plt
 	}
 
+	c.metricCtx = metric.GetMetricContextByChain(c.CID(), c.NID())
 	if err := c.prepareDatabase(chainDir); err != nil {
 		return err
 	}
@@ -454,7 +523,6 @@ func (c *singleChain) _init() error {
 		c.vld = consensus.NewCommitVoteSetFromBytes
 	}
 	c.pd = consensus.DecodePatch
-	c.metricCtx = metric.GetMetricContextByCID(c.CID())
 	return nil
 }
 
@@ -482,7 +550,27 @@ func (c *singleChain) prepareManagers() error {
 	return nil
 }
 
+// startJournalSync makes checkpoints of the journal on block commits.
+func (c *singleChain) startJournalSync() {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+	if c.journal == nil {
+		return
+	}
+	c.jstop = make(chan struct{})
+	go c.journal.syncOnBlocks(c.bm, c.logger, c.jstop)
+}
+
+func (c *singleChain) stopJournalSync() {
+	if c.jstop != nil {
+		close(c.jstop)
+		c.jstop = nil
+	}
+}
+
 func (c *singleChain) releaseManagers() {
+	c.stopJournalSync()
+	c.sp.Stop()
 	if c.cs != nil {
 		c.cs.Term()
 		c.cs = nil
@@ -501,7 +589,7 @@ func (c *singleChain) releaseManagers() {
 	}
 }
 
-func (c *singleChain) _runTask(task chainTask, wait bool) error {
+func (c *singleChain) _startTask(task chainTask) error {
 	if err := c._setStartingTask(task); err != nil {
 		return err
 	}
@@ -516,6 +604,13 @@ func (c *singleChain) _runTask(task chainTask, wait bool) error {
 		c.logger.Infof("TERMINATING %s", task.String())
 		task.Stop()
 	}
+	return nil
+}
+
+func (c *singleChain) _runTask(task chainTask, wait bool) error {
+	if err := c._startTask(task); err != nil {
+		return err
+	}
 	if wait {
 		return c._waitResultOf(task)
 	} else {
@@ -595,6 +690,20 @@ func (c *singleChain) Backup(file string, extra []string) error {
 	return c._runTask(task, false)
 }
 
+func (c *singleChain) BackupIncremental(file string, extra []string) error {
+	return c.backupIncremental(file, extra)
+}
+
+func (c *singleChain) Export(file string, from, to int64, flags int) error {
+	task := newTaskExport(c, file, from, to, flags)
+	return c._runTask(task, false)
+}
+
+func (c *singleChain) ImportArchive(file string) error {
+	task := newTaskImportArchive(c, file)
+	return c._runTask(task, false)
+}
+
 type TaskFactory func(c *singleChain, params json.RawMessage) (chainTask, error)
 
 var taskFactories = map[string]TaskFactory{}
@@ -657,16 +766,71 @@ func (c *singleChain) Verify() error {
 	return errors.UnsupportedError.New("UnsupportedFeatureVerify")
 }
 
-func (c *singleChain) Reset(gs string, height int64, blockHash []byte) error {
+func (c *singleChain) genesisFile(gs string) string {
 	if len(gs) == 0 {
 		chainDir := c.cfg.AbsBaseDir()
 		const chainGenesisZipFileName = "genesis.zip"
 		gs = path.Join(chainDir, chainGenesisZipFileName)
 	}
-	task := newTaskReset(c, gs, height, blockHash)
+	return gs
+}
+
+func (c *singleChain) Reset(gs string, height int64, blockHash []byte) error {
+	task := newTaskReset(c, c.genesisFile(gs), height, blockHash)
 	return c._runTask(task, false)
 }
 
+func (c *singleChain) Bootstrap(gs string, height int64, blockHash []byte) error {
+	if height <= 1 {
+		return errors.IllegalArgumentError.Errorf("InvalidTrustedHeight(height=%d)", height)
+	}
+	if err := c.setTrustedBlock(&TrustedBlock{Height: height, BlockHash: blockHash}); err != nil {
+		return err
+	}
+	if c.lastBlockHeight() >= height {
+		// it's already reset to the block before restart.
+		return c._startBootstrapped()
+	}
+	task := newTaskReset(c, c.genesisFile(gs), height, blockHash)
+	if err := c._startTask(task); err != nil {
+		return err
+	}
+	go func() {
+		if err := c._waitResultOf(task); err != nil {
+			c.logger.Errorf("fail to bootstrap height=%d hash=%#x err=%+v",
+				height, blockHash, err)
+			return
+		}
+		if err := c._startBootstrapped(); err != nil {
+			c.logger.Errorf("fail to start after bootstrap err=%+v", err)
+		}
+	}()
+	return nil
+}
+
+// _startBootstrapped starts the chain bootstrapped, then it removes
+// the trusted block from the configuration.
+func (c *singleChain) _startBootstrapped() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	if err := c.setTrustedBlock(nil); err != nil {
+		c.logger.Warnf("fail to remove trusted block from configuration err=%+v", err)
+	}
+	return nil
+}
+
+// setTrustedBlock updates the trusted block of the configuration and saves
+// it. It's also called by the goroutine waiting for the bootstrap, so it
+// holds the lock while it updates and saves the configuration.
+func (c *singleChain) setTrustedBlock(tb *TrustedBlock) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.cfg.TrustedBlock = tb
+	return c.cfg.Save()
+}
+
 func (c *singleChain) Logger() log.Logger {
 	return c.logger
 }
@@ -693,6 +857,10 @@ func (c *singleChain) WalletFor(dsa string) module.BaseWallet {
 	case "ecdsa/secp256k1":
 		return c.wallet
 	}
+	// wallets for other DSAs are provided by the node wallet if configured.
+	if wp, ok := c.wallet.(module.WalletProvider); ok {
+		return wp.WalletFor(dsa)
+	}
 	return nil
 }
 
@@ -724,7 +892,8 @@ func NewChain(
 		pm:        pm,
 		logger:    chainLogger,
 		regulator: NewRegulator(chainLogger),
-		metricCtx: metric.GetMetricContextByCID(cid),
+		metricCtx: metric.GetMetricContextByChain(cid, cfg.NID),
 	}
+	c.sp = newStatePruner(c)
 	return c
 }
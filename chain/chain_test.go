@@ -0,0 +1,156 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/network"
+	"github.com/icon-project/goloop/server"
+)
+
+var (
+	testServerOnce sync.Once
+	testServer     *server.Manager
+)
+
+func newTestChain(t *testing.T, w module.Wallet, cfgFile string) *singleChain {
+	logger := log.New()
+	nt := network.NewTransport("127.0.0.1:7100", w, logger)
+	// metric views are registered on making a server, so it's shared.
+	testServerOnce.Do(func() {
+		testServer = server.NewManager(&server.Config{}, wallet.New(), logger)
+	})
+	srv := testServer
+
+	cfg := new(Config)
+	bs, err := ioutil.ReadFile(cfgFile)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(bs, cfg))
+	cfg.FilePath = cfgFile
+	cfg.GenesisStorage = gs.NewFromTx(cfg.Genesis)
+
+	c := NewChain(w, nt, srv, nil, logger, cfg)
+	assert.NoError(t, c.Init())
+	return c
+}
+
+func waitChainState(t *testing.T, c *singleChain, states ...State) {
+	for i := 0; i < 200; i++ {
+		c.mtx.RLock()
+		state := c.state
+		c.mtx.RUnlock()
+		for _, s := range states {
+			if state == s {
+				return
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("chain state isn't one of %v", states)
+}
+
+func TestChain_BootstrapKeepsTrustedBlock(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := path.Join(dir, "config.json")
+	genesis := `{"accounts":[],"message":"test","nid":"0x3"}`
+	cfg := &Config{
+		NID:      3,
+		DBType:   string(db.MapDBBackend),
+		Genesis:  json.RawMessage(genesis),
+		BaseDir:  "chain",
+		FilePath: cfgFile,
+	}
+	assert.NoError(t, cfg.Save())
+
+	w := wallet.New()
+	c := newTestChain(t, w, cfgFile)
+	hash := crypto.SHA3Sum256([]byte("block"))
+	assert.Error(t, c.Bootstrap("", 1, hash))
+
+	// it waits for the block from peers, which are not available.
+	assert.NoError(t, c.Bootstrap("", 10, hash))
+	waitChainState(t, c, Started)
+	assert.NoError(t, c.Stop())
+	waitChainState(t, c, Stopped, Failed)
+	assert.NoError(t, c.Term())
+
+	// bootstrapping could be resumed with the configuration
+	c = newTestChain(t, w, cfgFile)
+	defer c.Term()
+	tb := c.cfg.TrustedBlock
+	if assert.NotNil(t, tb) {
+		assert.EqualValues(t, 10, tb.Height)
+		assert.Equal(t, hash, tb.BlockHash.Bytes())
+	}
+}
+
+func TestChain_WalletFor(t *testing.T) {
+	dir := t.TempDir()
+	cfgFile := path.Join(dir, "config.json")
+	genesis := `{"accounts":[],"message":"test","nid":"0x3"}`
+	cfg := &Config{
+		NID:      3,
+		DBType:   string(db.MapDBBackend),
+		Genesis:  json.RawMessage(genesis),
+		BaseDir:  "chain",
+		FilePath: cfgFile,
+	}
+	assert.NoError(t, cfg.Save())
+
+	secret, err := wallet.NewDSASecret(wallet.DSABLS12381)
+	assert.NoError(t, err)
+	bw, err := wallet.NewDSAWalletFromSecret(wallet.DSABLS12381, secret)
+	assert.NoError(t, err)
+
+	w := wallet.New()
+	c := newTestChain(t, wallet.WithDSAWallets(w, map[string]module.BaseWallet{
+		wallet.DSABLS12381: bw,
+	}), cfgFile)
+	defer c.Term()
+
+	assert.Equal(t, w.PublicKey(), c.WalletFor(wallet.DSASecp256k1).PublicKey())
+	assert.Equal(t, bw.PublicKey(), c.WalletFor(wallet.DSABLS12381).PublicKey())
+	assert.Nil(t, c.WalletFor("eddsa/ed25519"))
+}
+
+func TestChain_TxPoolSenderLimit(t *testing.T) {
+	for _, tc := range []struct {
+		config int
+		limit  int
+	}{
+		{0, ConfigDefaultTxPoolSenderLimit},
+		{10, 10},
+		{-1, 0},
+	} {
+		c := &singleChain{cfg: Config{TxPoolSenderLimit: tc.config}}
+		assert.Equal(t, tc.limit, c.TxPoolSenderLimit(), tc.config)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
@@ -20,6 +21,9 @@ const (
 	ConfigDefaultTxTimeout        = 5000 * time.Millisecond
 	ConfigDefaultChildrenLimit    = 10
 	ConfigDefaultNephewLimit      = 10
+	ConfigDefaultPruneInterval    = 10000
+
+	ConfigDefaultTxPoolSenderLimit = 1000
 )
 
 const (
@@ -37,17 +41,26 @@ type Config struct {
 	Platform string `json:"platform,omitempty"`
 
 	// static
-	SeedAddr         string `json:"seed_addr"`
-	Role             uint   `json:"role"`
-	ConcurrencyLevel int    `json:"concurrency_level,omitempty"`
-	NormalTxPoolSize int    `json:"normal_tx_pool,omitempty"`
-	PatchTxPoolSize  int    `json:"patch_tx_pool,omitempty"`
-	MaxBlockTxBytes  int    `json:"max_block_tx_bytes,omitempty"`
-	NodeCache        string `json:"node_cache,omitempty"`
-	AutoStart        bool   `json:"auto_start,omitempty"`
-	ChildrenLimit    *int   `json:"children_limit,omitempty"`
-	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
-	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	SeedAddr          string `json:"seed_addr"`
+	Role              uint   `json:"role"`
+	ConcurrencyLevel  int    `json:"concurrency_level,omitempty"`
+	NormalTxPoolSize  int    `json:"normal_tx_pool,omitempty"`
+	PatchTxPoolSize   int    `json:"patch_tx_pool,omitempty"`
+	TxPoolSenderLimit int    `json:"tx_pool_sender_limit,omitempty"`
+	MaxBlockTxBytes   int    `json:"max_block_tx_bytes,omitempty"`
+	NodeCache         string `json:"node_cache,omitempty"`
+	AutoStart         bool   `json:"auto_start,omitempty"`
+	ChildrenLimit     *int   `json:"children_limit,omitempty"`
+	NephewsLimit      *int   `json:"nephews_limit,omitempty"`
+	ValidateTxOnSend  bool   `json:"validate_tx_on_send,omitempty"`
+	EventIndex        bool   `json:"event_index,omitempty"`
+	PruneKeepBlocks   int64  `json:"prune_keep_blocks,omitempty"`
+	PruneInterval     int64  `json:"prune_interval,omitempty"`
+	FastSyncWindow    int    `json:"fast_sync_window,omitempty"`
+
+	// TrustedBlock is kept until the chain is bootstrapped and started,
+	// so that bootstrapping could be resumed after restart.
+	TrustedBlock *TrustedBlock `json:"trusted_block,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -66,6 +79,12 @@ type Config struct {
 	NIDForP2P bool `json:"-"`
 }
 
+// TrustedBlock is the block for bootstrapping the chain.
+type TrustedBlock struct {
+	Height    int64           `json:"height"`
+	BlockHash common.HexBytes `json:"block_hash"`
+}
+
 func (c *Config) ResolveAbsolute(targetPath string) string {
 	if filepath.IsAbs(targetPath) {
 		return targetPath
@@ -0,0 +1,387 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultJournalDir     = "journal"
+	journalBaseFile       = "base.json"
+	journalChangesFile    = "changes"
+	journalCheckpointFile = "checkpoint.json"
+
+	journalBufferSize = 1024 * 1024
+)
+
+// JournalBase is the backup the journal is based on. Changes in the journal
+// are made after the backup.
+type JournalBase struct {
+	Name   string    `json:"name"`
+	Height int64     `json:"height"`
+	Time   time.Time `json:"time"`
+}
+
+// journalCheckpoint is the position of the changes which are written
+// to the disk. Clean is true only if the journal is closed properly.
+type journalCheckpoint struct {
+	Height int64 `json:"height"`
+	Offset int64 `json:"offset"`
+	Clean  bool  `json:"clean"`
+}
+
+// dbJournal records changes of the database after the base backup in
+// the order of them, so any prefix of the changes is a consistent state of
+// the database. Changes are buffered, and they are written to the disk on
+// checkpoints at block commits. It's available only after a backup is made.
+type dbJournal struct {
+	dir string
+
+	lock   sync.Mutex
+	fd     *os.File
+	bw     *bufio.Writer
+	size   int64
+	synced int64
+	height int64
+}
+
+func (j *dbJournal) write(change *dbChange) error {
+	bs, err := codec.BC.MarshalToBytes(change)
+	if err != nil {
+		return err
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.fd == nil {
+		return errors.InvalidStateError.New("JournalClosed")
+	}
+	if _, err := j.bw.Write(bs); err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToWriteJournal")
+	}
+	j.size += int64(len(bs))
+	return nil
+}
+
+func (j *dbJournal) OnSet(id db.BucketID, key, value []byte) error {
+	return j.write(&dbChange{Bucket: string(id), Key: key, Value: value})
+}
+
+func (j *dbJournal) OnDelete(id db.BucketID, key []byte) error {
+	return j.write(&dbChange{Bucket: string(id), Key: key, Deleted: true})
+}
+
+func (j *dbJournal) syncInLock(clean bool) error {
+	if err := j.bw.Flush(); err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToFlushJournal")
+	}
+	if err := j.fd.Sync(); err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToSyncJournal")
+	}
+	if err := writeJSONFile(path.Join(j.dir, journalCheckpointFile), &journalCheckpoint{
+		Height: j.height,
+		Offset: j.size,
+		Clean:  clean,
+	}); err != nil {
+		return err
+	}
+	j.synced = j.size
+	return nil
+}
+
+// Checkpoint writes buffered changes to the disk. It's called after
+// the block of the height is committed.
+func (j *dbJournal) Checkpoint(height int64) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.fd == nil {
+		return errors.InvalidStateError.New("JournalClosed")
+	}
+	j.height = height
+	return j.syncInLock(false)
+}
+
+// Snapshot returns the height and the offset of the last checkpoint.
+// Changes before the offset are written to the disk, and they include all
+// changes of the blocks up to the height.
+func (j *dbJournal) Snapshot() (int64, int64, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.fd == nil {
+		return 0, 0, errors.InvalidStateError.New("JournalClosed")
+	}
+	return j.height, j.synced, nil
+}
+
+// Rebase makes the journal based on the backup including the changes
+// before the offset. Changes after the offset are kept.
+func (j *dbJournal) Rebase(offset int64, base *JournalBase) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.fd == nil {
+		return errors.InvalidStateError.New("JournalClosed")
+	}
+	if offset > j.synced {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidOffset(offset=%d,synced=%d)", offset, j.synced)
+	}
+	if err := j.syncInLock(false); err != nil {
+		return err
+	}
+
+	// Extra changes before the offset are harmless, so the base is
+	// written first.
+	if err := writeJSONFile(path.Join(j.dir, journalBaseFile), base); err != nil {
+		return err
+	}
+
+	changes := path.Join(j.dir, journalChangesFile)
+	tmp := changes + TempSuffix
+	size, err := copyFileFrom(changes, offset, tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := j.fd.Close(); err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToCloseJournal")
+	}
+	j.fd = nil
+	if err := os.Rename(tmp, changes); err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToReplaceJournal")
+	}
+	fd, err := os.OpenFile(changes, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToOpenJournal")
+	}
+	j.fd = fd
+	j.bw.Reset(fd)
+	j.size = size
+	j.synced = 0
+	return j.syncInLock(false)
+}
+
+func (j *dbJournal) Close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.fd == nil {
+		return nil
+	}
+	err := j.syncInLock(true)
+	if err2 := j.fd.Close(); err == nil {
+		err = err2
+	}
+	j.fd = nil
+	return err
+}
+
+// syncOnBlocks makes checkpoints of the journal whenever a block is
+// committed until stop is closed.
+func (j *dbJournal) syncOnBlocks(bm module.BlockManager, logger log.Logger, stop <-chan struct{}) {
+	blk, err := bm.GetLastBlock()
+	if err != nil {
+		logger.Warnf("Fail to get last block for journal err=%+v", err)
+		return
+	}
+	height := blk.Height()
+	for {
+		bch, err := bm.WaitForBlock(height + 1)
+		if err != nil {
+			logger.Warnf("Fail to wait block for journal err=%+v", err)
+			return
+		}
+		select {
+		case blk, ok := <-bch:
+			if !ok {
+				return
+			}
+			height = blk.Height()
+			if err := j.Checkpoint(height); err != nil {
+				logger.Warnf("Fail to make checkpoint of journal height=%d err=%+v",
+					height, err)
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// openJournal opens the journal of the chain. It returns nil if there is
+// no base backup. Changes after the last checkpoint are lost if the journal
+// isn't closed properly, so the journal is removed in that case, and
+// a new full backup is required for incremental backups.
+func openJournal(chainDir string, logger log.Logger) (*dbJournal, error) {
+	if base, err := readJournalBase(chainDir); err != nil || base == nil {
+		return nil, err
+	}
+	dir := path.Join(chainDir, DefaultJournalDir)
+	cp := new(journalCheckpoint)
+	if err := readJSONFile(path.Join(dir, journalCheckpointFile), cp); err != nil || !cp.Clean {
+		logger.Warnf("Remove the journal not closed properly err=%v", err)
+		return nil, removeJournal(chainDir)
+	}
+	changes := path.Join(dir, journalChangesFile)
+	if err := os.Truncate(changes, cp.Offset); err != nil {
+		return nil, errors.CriticalIOError.Wrap(err, "FailToTruncateJournal")
+	}
+	fd, err := os.OpenFile(changes, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.CriticalIOError.Wrap(err, "FailToOpenJournal")
+	}
+	j := &dbJournal{
+		dir:    dir,
+		fd:     fd,
+		bw:     bufio.NewWriterSize(fd, journalBufferSize),
+		size:   cp.Offset,
+		synced: cp.Offset,
+		height: cp.Height,
+	}
+	// mark it's open, so that it could be detected on failure.
+	if err := j.syncInLock(false); err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// readJournalBase returns the base of the journal. It returns nil if there
+// is no journal.
+func readJournalBase(chainDir string) (*JournalBase, error) {
+	base := new(JournalBase)
+	err := readJSONFile(path.Join(chainDir, DefaultJournalDir, journalBaseFile), base)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return base, nil
+}
+
+// resetJournal clears the journal and makes it based on the backup.
+// It's used while the database is released.
+func resetJournal(chainDir string, base *JournalBase) error {
+	dir := path.Join(chainDir, DefaultJournalDir)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path.Join(dir, journalChangesFile), nil, 0644); err != nil {
+		return err
+	}
+	if err := writeJSONFile(path.Join(dir, journalCheckpointFile), &journalCheckpoint{
+		Height: base.Height,
+		Clean:  true,
+	}); err != nil {
+		return err
+	}
+	return writeJSONFile(path.Join(dir, journalBaseFile), base)
+}
+
+// removeJournal removes the journal of the chain. It should be called
+// whenever the database is replaced, so that incremental backups are not
+// made on a backup of the other database.
+func removeJournal(chainDir string) error {
+	return os.RemoveAll(path.Join(chainDir, DefaultJournalDir))
+}
+
+// copyJournalChanges copies the changes in the journal before the offset.
+func copyJournalChanges(chainDir string, offset int64, w io.Writer) error {
+	fd, err := os.Open(path.Join(chainDir, DefaultJournalDir, journalChangesFile))
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	if n, err := io.Copy(w, io.LimitReader(fd, offset)); err != nil {
+		return err
+	} else if n != offset {
+		return errors.CriticalFormatError.Errorf(
+			"InvalidJournal(size=%d,expected=%d)", n, offset)
+	}
+	return nil
+}
+
+// copyFileFrom copies the file after the offset to the new file, and
+// it returns the size of the new file.
+func copyFileFrom(src string, offset int64, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	if _, err := in.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if err2 := out.Close(); err == nil {
+		err = err2
+	}
+	return n, err
+}
+
+func readJSONFile(p string, v interface{}) error {
+	bs, err := ioutil.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return errors.CriticalIOError.Wrapf(err, "FailToRead(%s)", path.Base(p))
+	}
+	if err := json.Unmarshal(bs, v); err != nil {
+		return errors.CriticalFormatError.Wrapf(err, "InvalidFormat(%s)", path.Base(p))
+	}
+	return nil
+}
+
+// writeJSONFile writes the file atomically.
+func writeJSONFile(p string, v interface{}) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp := p + TempSuffix
+	if err := ioutil.WriteFile(tmp, bs, 0644); err != nil {
+		return errors.CriticalIOError.Wrapf(err, "FailToWrite(%s)", path.Base(p))
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		return errors.CriticalIOError.Wrapf(err, "FailToWrite(%s)", path.Base(p))
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+)
+
+func applyJournal(t *testing.T, chainDir string, offset int64) db.Database {
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, copyJournalChanges(chainDir, offset, buf))
+	database := db.NewMapDB()
+	assert.NoError(t, ApplyDBChanges(database, buf))
+	return database
+}
+
+func assertValue(t *testing.T, database db.Database, id db.BucketID, key, value []byte) {
+	bk, err := database.GetBucket(id)
+	assert.NoError(t, err)
+	v, err := bk.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, value, v)
+}
+
+func TestJournal_Basic(t *testing.T) {
+	chainDir := t.TempDir()
+	logger := log.New()
+
+	j, err := openJournal(chainDir, logger)
+	assert.NoError(t, err)
+	assert.Nil(t, j, "no journal without base")
+
+	base := &JournalBase{Name: "base.zip", Height: 10, Time: time.Now()}
+	assert.NoError(t, resetJournal(chainDir, base))
+
+	base2, err := readJournalBase(chainDir)
+	assert.NoError(t, err)
+	assert.Equal(t, base.Name, base2.Name)
+	assert.Equal(t, base.Height, base2.Height)
+
+	j, err = openJournal(chainDir, logger)
+	assert.NoError(t, err)
+	assert.NotNil(t, j)
+
+	database := db.WithJournal(db.NewMapDB(), j)
+	bk, err := database.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("k1"), []byte("v1")))
+	assert.NoError(t, bk.Set([]byte("k2"), []byte("v2")))
+	assert.NoError(t, bk.Delete([]byte("k1")))
+	bk2, err := database.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bk2.Set([]byte("k1"), []byte("v3")))
+	assert.NoError(t, j.Checkpoint(11))
+
+	height, offset, err := j.Snapshot()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 11, height)
+
+	// changes after the checkpoint are not in the snapshot
+	assert.NoError(t, bk2.Set([]byte("k1"), []byte("v4")))
+	height2, offset2, err := j.Snapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, height, height2)
+	assert.Equal(t, offset, offset2)
+
+	database2 := applyJournal(t, chainDir, offset)
+	assertValue(t, database2, db.BytesByHash, []byte("k1"), nil)
+	assertValue(t, database2, db.BytesByHash, []byte("k2"), []byte("v2"))
+	assertValue(t, database2, db.MerkleTrie, []byte("k1"), []byte("v3"))
+
+	// changes are kept on close
+	assert.NoError(t, j.Close())
+	j, err = openJournal(chainDir, logger)
+	assert.NoError(t, err)
+	assert.NotNil(t, j)
+	height, offset, err = j.Snapshot()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 11, height)
+	assert.NoError(t, j.Close())
+
+	database2 = applyJournal(t, chainDir, offset)
+	assertValue(t, database2, db.MerkleTrie, []byte("k1"), []byte("v4"))
+
+	assert.NoError(t, resetJournal(chainDir, base))
+	j, err = openJournal(chainDir, logger)
+	assert.NoError(t, err)
+	_, offset, err = j.Snapshot()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, offset)
+	assert.NoError(t, j.Close())
+}
+
+func TestJournal_NotClosed(t *testing.T) {
+	chainDir := t.TempDir()
+	logger := log.New()
+
+	base := &JournalBase{Name: "base.zip", Height: 10, Time: time.Now()}
+	assert.NoError(t, resetJournal(chainDir, base))
+	j, err := openJournal(chainDir, logger)
+	assert.NoError(t, err)
+
+	database := db.WithJournal(db.NewMapDB(), j)
+	bk, err := database.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("k1"), []byte("v1")))
+	assert.NoError(t, j.Checkpoint(11))
+
+	// changes after the checkpoint would be lost on failure
+	assert.NoError(t, bk.Set([]byte("k2"), []byte("v2")))
+	assert.NoError(t, j.fd.Close())
+
+	j, err = openJournal(chainDir, logger)
+	assert.NoError(t, err)
+	assert.Nil(t, j)
+	base2, err := readJournalBase(chainDir)
+	assert.NoError(t, err)
+	assert.Nil(t, base2, "journal should be invalidated")
+}
+
+func TestJournal_Rebase(t *testing.T) {
+	chainDir := t.TempDir()
+	logger := log.New()
+
+	base := &JournalBase{Name: "base.zip", Height: 10, Time: time.Now()}
+	assert.NoError(t, resetJournal(chainDir, base))
+	j, err := openJournal(chainDir, logger)
+	assert.NoError(t, err)
+
+	database := db.WithJournal(db.NewMapDB(), j)
+	bk, err := database.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("k1"), []byte("v1")))
+	assert.NoError(t, j.Checkpoint(11))
+	height, offset, err := j.Snapshot()
+	assert.NoError(t, err)
+
+	assert.NoError(t, bk.Set([]byte("k2"), []byte("v2")))
+	assert.NoError(t, j.Checkpoint(12))
+
+	base2 := &JournalBase{Name: "base2.zip", Height: height, Time: time.Now()}
+	assert.NoError(t, j.Rebase(offset, base2))
+	assert.NoError(t, bk.Set([]byte("k3"), []byte("v3")))
+	assert.NoError(t, j.Checkpoint(13))
+
+	height, offset, err = j.Snapshot()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 13, height)
+	assert.NoError(t, j.Close())
+
+	base3, err := readJournalBase(chainDir)
+	assert.NoError(t, err)
+	assert.Equal(t, base2.Name, base3.Name)
+	assert.Equal(t, base2.Height, base3.Height)
+
+	database2 := applyJournal(t, chainDir, offset)
+	assertValue(t, database2, db.BytesByHash, []byte("k1"), nil)
+	assertValue(t, database2, db.BytesByHash, []byte("k2"), []byte("v2"))
+	assertValue(t, database2, db.BytesByHash, []byte("k3"), []byte("v3"))
+}
+
+func TestApplyDBChanges(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	enc := codec.BC.NewEncoder(buf)
+	assert.NoError(t, enc.Encode(&dbChange{
+		Bucket: string(db.BytesByHash),
+		Key:    []byte("k1"),
+		Value:  []byte("v1"),
+	}))
+	assert.NoError(t, enc.Encode(&dbChange{
+		Bucket:  string(db.BytesByHash),
+		Key:     []byte("k2"),
+		Deleted: true,
+	}))
+	assert.NoError(t, enc.Close())
+
+	database := db.NewMapDB()
+	bk, err := database.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("k2"), []byte("v2")))
+
+	assert.NoError(t, ApplyDBChanges(database, buf))
+
+	v, err := bk.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), v)
+	has, err := bk.Has([]byte("k2"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
@@ -0,0 +1,704 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	keyPrunedHeight = "prunedHeight"
+
+	// Bloom filters for reachable nodes and objects. False positives only
+	// leave some garbage, so sizes are chosen for the number of nodes of
+	// the world state of the main network. They are allocated only while
+	// the pruner is working on a cycle.
+	prunerMarkBloomBytes  = 256 * 1024 * 1024
+	prunerWriteBloomBytes = 16 * 1024 * 1024
+
+	// prunerMaxMarkFill is the ratio of set bits in the marks to mark
+	// the world states again from the scratch. The marks are kept across
+	// cycles, so they also have marks of pruned world states.
+	prunerMaxMarkFill = 0.5
+
+	// prunerWriteLeadBlocks is the number of blocks before a cycle to start
+	// recording writes. It covers blocks executed but not finalized at the
+	// beginning of the cycle.
+	prunerWriteLeadBlocks = 5
+
+	prunerMarksFile = "pruner.marks"
+
+	prunerMinKeepBlocks = 10
+	prunerMinInterval   = 100
+)
+
+const (
+	prunerIdle     = "idle"
+	prunerMarking  = "marking"
+	prunerSweeping = "sweeping"
+)
+
+// hashBloom is a bloom filter for node hashes. Node hashes are already
+// uniformly distributed, so it uses parts of them as indexes.
+type hashBloom struct {
+	bits []uint64
+}
+
+func newHashBloom(size int) *hashBloom {
+	return &hashBloom{
+		bits: make([]uint64, size/8),
+	}
+}
+
+func (b *hashBloom) indexes(h []byte) [4]uint64 {
+	if len(h) < 32 {
+		h = crypto.SHA3Sum256(h)
+	}
+	var idx [4]uint64
+	nbits := uint64(len(b.bits)) * 64
+	for i := range idx {
+		idx[i] = binary.BigEndian.Uint64(h[i*8:]) % nbits
+	}
+	return idx
+}
+
+func (b *hashBloom) Add(h []byte) {
+	for _, i := range b.indexes(h) {
+		p := &b.bits[i/64]
+		mask := uint64(1) << (i % 64)
+		for {
+			old := atomic.LoadUint64(p)
+			if old&mask != 0 || atomic.CompareAndSwapUint64(p, old, old|mask) {
+				break
+			}
+		}
+	}
+}
+
+func (b *hashBloom) Has(h []byte) bool {
+	for _, i := range b.indexes(h) {
+		if atomic.LoadUint64(&b.bits[i/64])&(uint64(1)<<(i%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// fill returns the ratio of set bits.
+func (b *hashBloom) fill() float64 {
+	var cnt int
+	for _, w := range b.bits {
+		cnt += bits.OnesCount64(w)
+	}
+	return float64(cnt) / float64(len(b.bits)*64)
+}
+
+type prunerMarksHeader struct {
+	Height    int64
+	StateHash []byte
+	Words     int
+}
+
+// prunerMarks is the marks of the world states until the height. It's
+// stored in the file between cycles, so the next cycle only marks world
+// states of new blocks.
+type prunerMarks struct {
+	height    int64
+	stateHash []byte
+	bloom     *hashBloom
+}
+
+func (m *prunerMarks) save(fp string) error {
+	tmp := fp + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	header := codec.BC.MustMarshalToBytes(&prunerMarksHeader{
+		Height:    m.height,
+		StateHash: m.stateHash,
+		Words:     len(m.bloom.bits),
+	})
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(header)))
+	_, _ = w.Write(buf[:4])
+	_, _ = w.Write(header)
+	for _, word := range m.bloom.bits {
+		binary.BigEndian.PutUint64(buf[:], word)
+		_, _ = w.Write(buf[:])
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fp)
+}
+
+func loadPrunerMarks(fp string, size int) (*prunerMarks, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:4]); err != nil {
+		return nil, err
+	}
+	header := make([]byte, binary.BigEndian.Uint32(buf[:4]))
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	var h prunerMarksHeader
+	if _, err := codec.BC.UnmarshalFromBytes(header, &h); err != nil {
+		return nil, err
+	}
+	if h.Words != size/8 {
+		return nil, nil
+	}
+	m := &prunerMarks{
+		height:    h.Height,
+		stateHash: h.StateHash,
+		bloom:     newHashBloom(size),
+	}
+	for i := range m.bloom.bits {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		m.bloom.bits[i] = binary.BigEndian.Uint64(buf[:])
+	}
+	return m, nil
+}
+
+// pruningDB records hashes of nodes and objects written to MerkleTrie and
+// BytesByHash buckets while the pruner is working on a cycle, so that
+// the ones written during marking and sweeping are never removed.
+type pruningDB struct {
+	db.Database
+
+	lock   sync.RWMutex
+	writes *hashBloom
+	marks  *hashBloom
+}
+
+func newPruningDB(database db.Database) *pruningDB {
+	return &pruningDB{
+		Database: database,
+	}
+}
+
+func (d *pruningDB) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil || (id != db.MerkleTrie && id != db.BytesByHash) {
+		return bk, err
+	}
+	return &pruningBucket{Bucket: bk, db: d}, nil
+}
+
+func (d *pruningDB) startRecording(size int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.writes == nil {
+		d.writes = newHashBloom(size)
+	}
+}
+
+func (d *pruningDB) stopRecording() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.writes = nil
+	d.marks = nil
+}
+
+func (d *pruningDB) isRecording() bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.writes != nil
+}
+
+// beginSweep sets marks for deleteUnreachable.
+func (d *pruningDB) beginSweep(marks *hashBloom) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.writes == nil {
+		return errors.InvalidStateError.New("NotRecording")
+	}
+	d.marks = marks
+	return nil
+}
+
+func (d *pruningDB) endSweep() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.marks = nil
+}
+
+// deleteUnreachable deletes the node or the object if it's not marked and
+// not written during the cycle. It returns true if it's deleted.
+func (d *pruningDB) deleteUnreachable(bk db.Bucket, h []byte) (bool, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.marks == nil || d.writes == nil {
+		return false, errors.InvalidStateError.New("NotInSweep")
+	}
+	if d.marks.Has(h) || d.writes.Has(h) {
+		return false, nil
+	}
+	if pb, ok := bk.(*pruningBucket); ok {
+		bk = pb.Bucket
+	}
+	return true, bk.Delete(h)
+}
+
+type pruningBucket struct {
+	db.Bucket
+	db *pruningDB
+}
+
+func (b *pruningBucket) Set(key []byte, value []byte) error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+	if b.db.writes != nil {
+		b.db.writes.Add(key)
+	}
+	return b.Bucket.Set(key, value)
+}
+
+// statePruner removes MPT nodes and objects of the world states older than
+// the last keepBlocks blocks while the chain is running. Each cycle marks
+// nodes and objects of the world states to keep, then visits the ones of
+// the old world states which were removed by the next block and deletes
+// them if they're not marked. Marks are kept in the file between cycles, so
+// each cycle only marks the changes of the world states of new blocks.
+//
+// MerkleTrie and BytesByHash buckets are shared with other data (blocks,
+// transactions, receipts, extension states and so on) which are not walked
+// by the pruner. Keys of storages are hashes in all execution environments
+// and system SCOREs, so nodes of storages can't be same as the nodes of
+// other tries whose keys are indexes or whose values are encoded objects.
+// Codes, API information and object graphs have their own formats, which
+// are different from the ones of other data in BytesByHash bucket.
+type statePruner struct {
+	chain *singleChain
+	log   log.Logger
+
+	lock      sync.Mutex
+	stop      chan struct{}
+	done      chan struct{}
+	pdb       *pruningDB
+	keep      int64
+	every     int64
+	phase     string
+	pruned    int64
+	target    int64
+	current   int64
+	lastErr   error
+	marksFile string
+
+	markBytes  int
+	writeBytes int
+
+	marked  uint64
+	deleted uint64
+}
+
+func newStatePruner(c *singleChain) *statePruner {
+	return &statePruner{
+		chain: c,
+		log: c.logger.WithFields(log.Fields{
+			log.FieldKeyModule: "PRUNER",
+		}),
+		phase:      prunerIdle,
+		marksFile:  path.Join(c.cfg.AbsBaseDir(), prunerMarksFile),
+		markBytes:  prunerMarkBloomBytes,
+		writeBytes: prunerWriteBloomBytes,
+	}
+}
+
+func (p *statePruner) Start(pdb *pruningDB, keep, every int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stop != nil || keep <= 0 || pdb == nil {
+		return
+	}
+	if keep < prunerMinKeepBlocks {
+		keep = prunerMinKeepBlocks
+	}
+	if every < prunerMinInterval {
+		every = prunerMinInterval
+	}
+	p.pdb = pdb
+	p.keep = keep
+	p.every = every
+	p.phase = prunerIdle
+	p.lastErr = nil
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	// blocks aren't executed yet, so writes are recorded from now for
+	// the first cycle.
+	pdb.startRecording(p.writeBytes)
+	go p.run(p.chain.bm, p.chain.database, p.stop, p.done)
+}
+
+func (p *statePruner) Stop() {
+	p.lock.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.lock.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (p *statePruner) setPhase(phase string, target int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.phase = phase
+	p.target = target
+	atomic.StoreInt64(&p.current, 0)
+}
+
+func (p *statePruner) setPruned(height int64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pruned = height
+}
+
+func (p *statePruner) setError(err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.lastErr = err
+}
+
+func (p *statePruner) loadPrunedHeight(bm module.BlockManager, dbase db.Database) (int64, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get([]byte(keyPrunedHeight))
+	if err != nil {
+		return 0, err
+	}
+	if bs != nil {
+		var height int64
+		if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+			return 0, err
+		}
+		return height, nil
+	}
+	if blk, _, err := bm.GetGenesisData(); err == nil {
+		return blk.Height(), nil
+	}
+	return 0, nil
+}
+
+func (p *statePruner) storePrunedHeight(dbase db.Database, height int64) error {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	if err := bk.Set([]byte(keyPrunedHeight), codec.BC.MustMarshalToBytes(height)); err != nil {
+		return err
+	}
+	p.setPruned(height)
+	return nil
+}
+
+// waitForBlock waits for the block of the height. It returns false if
+// the pruner is stopped.
+func (p *statePruner) waitForBlock(bm module.BlockManager, stop chan struct{}, height int64) (bool, error) {
+	bch, err := bm.WaitForBlock(height)
+	if err != nil {
+		return false, err
+	}
+	select {
+	case _, ok := <-bch:
+		return ok, nil
+	case <-stop:
+		return false, nil
+	}
+}
+
+func (p *statePruner) run(bm module.BlockManager, dbase db.Database, stop, done chan struct{}) {
+	defer close(done)
+	defer p.pdb.stopRecording()
+
+	pruned, err := p.loadPrunedHeight(bm, dbase)
+	if err != nil {
+		p.log.Errorf("FailToLoadPrunedHeight(err=%+v)", err)
+		p.setError(err)
+		return
+	}
+	p.setPruned(pruned)
+	p.log.Infof("Start pruner keep=%d interval=%d pruned=%d", p.keep, p.every, pruned)
+
+	for {
+		blk, err := bm.GetLastBlock()
+		if err != nil {
+			p.setError(err)
+			return
+		}
+		next := pruned + p.keep + p.every - 1
+		if target := blk.Height() - p.keep + 1; target-pruned >= p.every {
+			err = p.prune(dbase, stop, pruned, target, blk.Height(), func(height int64) ([]byte, error) {
+				return stateHashOf(bm, height)
+			})
+			if err == nil {
+				pruned = target
+				continue
+			}
+			if errors.InterruptedError.Equals(err) {
+				return
+			}
+			p.log.Warnf("FailToPrune(from=%d,to=%d,err=%+v)", pruned, target, err)
+			p.setError(err)
+			next = blk.Height() + p.every
+		}
+		if lead := next - prunerWriteLeadBlocks; lead > blk.Height() {
+			p.pdb.stopRecording()
+			if ok, err := p.waitForBlock(bm, stop, lead); err != nil {
+				p.setError(err)
+				return
+			} else if !ok {
+				return
+			}
+			p.pdb.startRecording(p.writeBytes)
+		}
+		if ok, err := p.waitForBlock(bm, stop, next); err != nil {
+			p.setError(err)
+			return
+		} else if !ok {
+			return
+		}
+	}
+}
+
+func stateHashOf(bm module.BlockManager, height int64) ([]byte, error) {
+	blk, err := bm.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return service.StateHashFromResult(blk.Result())
+}
+
+// loadMarks returns the marks stored by the previous cycle if they're
+// usable for the world states from the height.
+func (p *statePruner) loadMarks(from int64, hashOf func(height int64) ([]byte, error)) *prunerMarks {
+	if p.marksFile == "" {
+		return nil
+	}
+	m, err := loadPrunerMarks(p.marksFile, p.markBytes)
+	if err != nil {
+		p.log.Warnf("FailToLoadMarks(err=%+v)", err)
+		return nil
+	}
+	if m == nil || m.height < from {
+		return nil
+	}
+	if sh, err := hashOf(m.height); err != nil || !bytes.Equal(sh, m.stateHash) {
+		return nil
+	}
+	if fill := m.bloom.fill(); fill > prunerMaxMarkFill {
+		p.log.Infof("Mark world states again fill=%.3f", fill)
+		return nil
+	}
+	return m
+}
+
+func (p *statePruner) saveMarks(m *prunerMarks) {
+	if p.marksFile == "" {
+		return
+	}
+	if err := m.save(p.marksFile); err != nil {
+		p.log.Warnf("FailToSaveMarks(err=%+v)", err)
+		_ = os.Remove(p.marksFile)
+	}
+}
+
+// prune removes world states of blocks in [from,to) keeping world states of
+// blocks in [to,last]. hashOf returns the hash of the world state of the block.
+func (p *statePruner) prune(dbase db.Database, stop chan struct{}, from, to, last int64,
+	hashOf func(height int64) ([]byte, error),
+) error {
+	if !p.pdb.isRecording() {
+		return errors.InvalidStateError.New("NotRecording")
+	}
+	defer p.setPhase(prunerIdle, 0)
+
+	interrupted := func() bool {
+		select {
+		case <-stop:
+			return true
+		default:
+			return false
+		}
+	}
+
+	marks := p.loadMarks(from, hashOf)
+	if marks == nil {
+		marks = &prunerMarks{
+			height: to - 1,
+			bloom:  newHashBloom(p.markBytes),
+		}
+		atomic.StoreUint64(&p.marked, 0)
+	} else if marks.height > last {
+		return errors.InvalidStateError.Errorf(
+			"InvalidMarkedHeight(marked=%d,last=%d)", marks.height, last)
+	}
+	p.log.Infof("Mark world states from=%d to=%d", marks.height+1, last)
+	p.setPhase(prunerMarking, last)
+	mark := func(h []byte) error {
+		marks.bloom.Add(h)
+		atomic.AddUint64(&p.marked, 1)
+		return nil
+	}
+	for height := marks.height + 1; height <= last; height++ {
+		if interrupted() {
+			return errors.ErrInterrupted
+		}
+		sh, err := hashOf(height)
+		if err != nil {
+			return err
+		}
+		if err = state.WalkDiff(dbase, sh, marks.stateHash, mark, mark); err != nil {
+			return err
+		}
+		marks.height, marks.stateHash = height, sh
+		atomic.StoreInt64(&p.current, height)
+	}
+	p.saveMarks(marks)
+
+	p.log.Infof("Sweep world states from=%d to=%d", from, to-1)
+	p.setPhase(prunerSweeping, to-1)
+	if err := p.pdb.beginSweep(marks.bloom); err != nil {
+		return err
+	}
+	defer p.pdb.endSweep()
+	nodes, err := dbase.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	objects, err := dbase.GetBucket(db.BytesByHash)
+	if err != nil {
+		return err
+	}
+	next, err := hashOf(from)
+	if err != nil {
+		return err
+	}
+	var count int
+	sweep := func(bk db.Bucket) func(h []byte) error {
+		return func(h []byte) error {
+			if count += 1; count%1000 == 0 && interrupted() {
+				return errors.ErrInterrupted
+			}
+			deleted, err := p.pdb.deleteUnreachable(bk, h)
+			if deleted {
+				atomic.AddUint64(&p.deleted, 1)
+			}
+			return err
+		}
+	}
+	for height := from; height < to; height++ {
+		if interrupted() {
+			return errors.ErrInterrupted
+		}
+		sh := next
+		next, err = hashOf(height + 1)
+		if err != nil {
+			return err
+		}
+		err = state.WalkDiffPartial(dbase, sh, next, sweep(nodes), sweep(objects))
+		if err != nil {
+			return err
+		}
+		if err := p.storePrunedHeight(dbase, height+1); err != nil {
+			return err
+		}
+		atomic.StoreInt64(&p.current, height)
+	}
+	p.log.Infof("Pruned world states from=%d to=%d deleted=%d",
+		from, to-1, atomic.LoadUint64(&p.deleted))
+	return nil
+}
+
+func (p *statePruner) inspect() map[string]interface{} {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.stop == nil {
+		return nil
+	}
+	m := make(map[string]interface{})
+	m["keepBlocks"] = p.keep
+	m["interval"] = p.every
+	m["phase"] = p.phase
+	m["prunedHeight"] = p.pruned
+	if p.phase != prunerIdle {
+		m["target"] = p.target
+		m["current"] = atomic.LoadInt64(&p.current)
+	}
+	m["marked"] = atomic.LoadUint64(&p.marked)
+	m["deleted"] = atomic.LoadUint64(&p.deleted)
+	if p.lastErr != nil {
+		m["lastError"] = p.lastErr.Error()
+	}
+	return m
+}
+
+type prunerHolder interface {
+	pruner() *statePruner
+}
+
+// InspectPruner returns the status of the state pruner of the chain.
+// It returns nil if the pruner isn't running.
+func InspectPruner(c module.Chain, informal bool) map[string]interface{} {
+	if h, ok := c.(prunerHolder); ok {
+		if p := h.pruner(); p != nil {
+			return p.inspect()
+		}
+	}
+	return nil
+}
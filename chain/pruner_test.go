@@ -0,0 +1,226 @@
+package chain
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type testStates struct {
+	t      *testing.T
+	pdb    *pruningDB
+	ws     state.WorldState
+	hashes [][]byte
+}
+
+func (s *testStates) addBlocks(n int) {
+	owner := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	for i := 0; i < n; i++ {
+		height := len(s.hashes)
+		for j := 0; j < 5; j++ {
+			addr := common.MustNewAddressFromString(fmt.Sprintf("hx%040x", (height*5+j)%40+10))
+			// balances return to the old values periodically
+			s.ws.GetAccountState(addr.ID()).SetBalance(big.NewInt(int64(height % 7)))
+		}
+		as := s.ws.GetAccountState(score.ID())
+		if !as.IsContract() {
+			as.InitContractAccount(owner)
+		}
+		for j := 0; j < 3; j++ {
+			key := []byte(fmt.Sprintf("key%d", (height*3+j)%50))
+			_, err := as.SetValue(key, []byte(fmt.Sprintf("value%d", height%5)))
+			assert.NoError(s.t, err)
+		}
+		if height%10 == 0 {
+			code := []byte(fmt.Sprintf("code%d", height))
+			_, err := as.DeployContract(code, state.JavaEE, state.CTAppJava, nil, code)
+			assert.NoError(s.t, err)
+		}
+		wss := s.ws.GetSnapshot()
+		assert.NoError(s.t, wss.Flush())
+		s.hashes = append(s.hashes, wss.StateHash())
+	}
+}
+
+func (s *testStates) hashOf(height int64) ([]byte, error) {
+	return s.hashes[height], nil
+}
+
+func (s *testStates) nodesOf(height int64) map[string]bool {
+	nodes := make(map[string]bool)
+	onNode := func(h []byte) error {
+		nodes[string(h)] = true
+		return nil
+	}
+	err := state.WalkDiff(s.pdb, s.hashes[height], nil, onNode, onNode)
+	assert.NoError(s.t, err)
+	return nodes
+}
+
+func (s *testStates) has(id db.BucketID, h []byte) bool {
+	bk, err := s.pdb.GetBucket(id)
+	assert.NoError(s.t, err)
+	ok, err := bk.Has(h)
+	assert.NoError(s.t, err)
+	return ok
+}
+
+func newTestStates(t *testing.T) *testStates {
+	pdb := newPruningDB(db.NewMapDB())
+	return &testStates{
+		t:   t,
+		pdb: pdb,
+		ws:  state.NewWorldState(pdb, nil, nil, nil, nil),
+	}
+}
+
+func newTestPruner(pdb *pruningDB, marksFile string) *statePruner {
+	return &statePruner{
+		log:        log.New(),
+		pdb:        pdb,
+		marksFile:  marksFile,
+		markBytes:  1024 * 1024,
+		writeBytes: 1024 * 1024,
+	}
+}
+
+func TestStatePruner_Prune(t *testing.T) {
+	s := newTestStates(t)
+	s.addBlocks(30)
+
+	p := newTestPruner(s.pdb, "")
+	s.pdb.startRecording(p.writeBytes)
+
+	kept := make(map[int64]map[string]bool)
+	for height := int64(20); height < 30; height++ {
+		kept[height] = s.nodesOf(height)
+	}
+	err := p.prune(s.pdb, nil, 0, 20, 29, s.hashOf)
+	assert.NoError(t, err)
+	assert.NotZero(t, p.deleted)
+	for height := int64(20); height < 30; height++ {
+		assert.Equal(t, kept[height], s.nodesOf(height))
+	}
+	err = state.WalkDiff(s.pdb, s.hashes[0], nil, func(h []byte) error {
+		return nil
+	}, nil)
+	assert.Error(t, err)
+
+	// nodes written during the cycle are kept
+	s.addBlocks(20)
+	deleted := p.deleted
+	for height := int64(40); height < 50; height++ {
+		kept[height] = s.nodesOf(height)
+	}
+	err = p.prune(s.pdb, nil, 20, 40, 49, s.hashOf)
+	assert.NoError(t, err)
+	assert.NotEqual(t, deleted, p.deleted)
+	for height := int64(40); height < 50; height++ {
+		assert.Equal(t, kept[height], s.nodesOf(height))
+	}
+
+	// it fails without recording writes
+	s.pdb.stopRecording()
+	err = p.prune(s.pdb, nil, 40, 45, 49, s.hashOf)
+	assert.Error(t, err)
+}
+
+func TestStatePruner_PruneStoragesAndObjects(t *testing.T) {
+	s := newTestStates(t)
+	s.addBlocks(30)
+
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	wss := state.NewWorldSnapshot(s.pdb, s.hashes[0], nil, nil, nil)
+	storage := state.StorageHashOf(wss.GetAccountSnapshot(score.ID()))
+	assert.NotNil(t, storage)
+	assert.True(t, s.has(db.MerkleTrie, storage))
+	oldCode := crypto.SHA3Sum256([]byte("code0"))
+	keptCode := crypto.SHA3Sum256([]byte("code20"))
+	assert.True(t, s.has(db.BytesByHash, oldCode))
+
+	p := newTestPruner(s.pdb, "")
+	s.pdb.startRecording(p.writeBytes)
+	err := p.prune(s.pdb, nil, 0, 20, 29, s.hashOf)
+	assert.NoError(t, err)
+
+	assert.False(t, s.has(db.MerkleTrie, storage))
+	assert.False(t, s.has(db.BytesByHash, oldCode))
+	assert.True(t, s.has(db.BytesByHash, keptCode))
+}
+
+func TestStatePruner_IncrementalMarks(t *testing.T) {
+	s := newTestStates(t)
+	s.addBlocks(30)
+
+	marksFile := path.Join(t.TempDir(), prunerMarksFile)
+	p := newTestPruner(s.pdb, marksFile)
+	s.pdb.startRecording(p.writeBytes)
+	err := p.prune(s.pdb, nil, 0, 10, 29, s.hashOf)
+	assert.NoError(t, err)
+	_, err = os.Stat(marksFile)
+	assert.NoError(t, err)
+	full := p.marked
+
+	// the next cycle only marks changes of new world states
+	s.addBlocks(10)
+	kept := make(map[int64]map[string]bool)
+	for height := int64(20); height < 40; height++ {
+		kept[height] = s.nodesOf(height)
+	}
+	err = p.prune(s.pdb, nil, 10, 20, 39, s.hashOf)
+	assert.NoError(t, err)
+	assert.Less(t, p.marked-full, full/2)
+	for height := int64(20); height < 40; height++ {
+		assert.Equal(t, kept[height], s.nodesOf(height))
+	}
+
+	// marks for other world states are ignored
+	m, err := loadPrunerMarks(marksFile, p.markBytes)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 39, m.height)
+	s.hashes[39] = s.hashes[38]
+	assert.Nil(t, p.loadMarks(20, s.hashOf))
+	s.pdb.stopRecording()
+}
+
+func TestPruningDB_Recording(t *testing.T) {
+	pdb := newPruningDB(db.NewMapDB())
+	bk, err := pdb.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	key := crypto.SHA3Sum256([]byte("value"))
+	assert.NoError(t, bk.Set(key, []byte("value")))
+
+	// blooms are allocated only while recording
+	assert.False(t, pdb.isRecording())
+	_, err = pdb.deleteUnreachable(bk, key)
+	assert.Error(t, err)
+
+	pdb.startRecording(1024)
+	assert.NoError(t, pdb.beginSweep(newHashBloom(1024)))
+	assert.NoError(t, bk.Set(key, []byte("value")))
+	deleted, err := pdb.deleteUnreachable(bk, key)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	pdb.endSweep()
+	pdb.stopRecording()
+	assert.Nil(t, pdb.writes)
+	assert.Nil(t, pdb.marks)
+
+	pdb.startRecording(1024)
+	assert.NoError(t, pdb.beginSweep(newHashBloom(1024)))
+	deleted, err = pdb.deleteUnreachable(bk, key)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+	pdb.stopRecording()
+}
@@ -27,20 +27,45 @@ import (
 	"path"
 	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 )
 
-const TemporalBackupFile = ".backup"
+const (
+	TemporalBackupFile = ".backup"
 
+	// IncrementalDBFile is the entry of the incremental backup for changes
+	// of the database.
+	IncrementalDBFile = "db.inc"
+)
+
+// BackupInfo is stored in the comment of the backup file. Incremental
+// backup has the name and the height of the base backup, and it can be
+// restored only on top of the base.
 type BackupInfo struct {
-	NID     common.HexInt32 `json:"nid"`
-	CID     common.HexInt32 `json:"cid"`
-	Channel string          `json:"channel"`
-	Height  int64           `json:"height"`
-	Codec   string          `json:"codec"`
+	NID        common.HexInt32 `json:"nid"`
+	CID        common.HexInt32 `json:"cid"`
+	Channel    string          `json:"channel"`
+	Height     int64           `json:"height"`
+	Codec      string          `json:"codec"`
+	Base       string          `json:"base,omitempty"`
+	BaseHeight int64           `json:"baseHeight,omitempty"`
+}
+
+func (info *BackupInfo) IsIncremental() bool {
+	return len(info.Base) > 0
+}
+
+// dbChange is an entry of IncrementalDBFile.
+type dbChange struct {
+	Bucket  string
+	Key     []byte
+	Value   []byte
+	Deleted bool
 }
 
 var backupStates = map[State]string{
@@ -54,6 +79,8 @@ type taskBackup struct {
 	chain   *singleChain
 	file    string
 	extra   []string
+	height  int64
+	start   time.Time
 	fd      io.WriteCloser
 	zw      *zip.Writer
 	current int32
@@ -94,6 +121,7 @@ func (t *taskBackup) Start() (ret error) {
 		t.chain.releaseDatabase()
 		return nil
 	}
+	chainDir := t.chain.cfg.AbsBaseDir()
 	tmp, err := ioutil.TempFile(path.Dir(t.file), TemporalBackupFile)
 	if err != nil {
 		return errors.Wrap(err, "Fail to make temporal file")
@@ -111,13 +139,16 @@ func (t *taskBackup) Start() (ret error) {
 	t.fd = tmp
 	t.zw = zip.NewWriter(tmp)
 
-	if err := writeBackupInfo(t.zw, &BackupInfo{
+	t.height = t.chain.lastBlockHeight()
+	t.start = time.Now()
+	info := &BackupInfo{
 		NID:     common.HexInt32{Value: int32(t.chain.NID())},
 		CID:     common.HexInt32{Value: int32(t.chain.CID())},
 		Channel: t.chain.Channel(),
-		Height:  t.chain.lastBlockHeight(),
+		Height:  t.height,
 		Codec:   codec.BC.Name(),
-	}); err != nil {
+	}
+	if err := writeBackupInfo(t.zw, info); err != nil {
 		return err
 	}
 
@@ -130,13 +161,31 @@ func (t *taskBackup) Start() (ret error) {
 		}
 		if err != nil {
 			os.Remove(tmp.Name())
+		} else {
+			t._resetJournal(chainDir)
 		}
+		t.chain.ensureDatabase()
 		t.result.SetValue(err)
 	}()
 	return nil
 }
 
-func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
+// _resetJournal makes the journal based on the new backup, so following
+// incremental backup would include changes after the backup.
+func (t *taskBackup) _resetJournal(chainDir string) {
+	if err := resetJournal(chainDir, &JournalBase{
+		Name:   path.Base(t.file),
+		Height: t.height,
+		Time:   t.start,
+	}); err != nil {
+		t.chain.logger.Warnf("Fail to reset journal err=%+v", err)
+		removeJournal(chainDir)
+	}
+}
+
+// zipWrite writes the file or the files in the directory. If since is not
+// zero, it skips the files modified before since.
+func zipWrite(writer *zip.Writer, p, n string, since time.Time, on func(int64) error) error {
 	p2 := path.Join(p, n)
 	st, err := os.Stat(p2)
 	if errors.Is(err, fs.ErrNotExist) {
@@ -145,6 +194,9 @@ func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
 		return errors.Wrap(err, "writeToZip: FAIL on os.State")
 	}
 	if st.Mode().IsRegular() {
+		if !since.IsZero() && st.ModTime().Before(since) {
+			return nil
+		}
 		fd, err := os.Open(p2)
 		defer fd.Close()
 		if err != nil {
@@ -184,14 +236,14 @@ func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
 		return fis[i].Name() < fis[j].Name()
 	})
 	for _, fi := range fis {
-		if err := zipWrite(writer, p, path.Join(n, fi.Name()), on); err != nil {
+		if err := zipWrite(writer, p, path.Join(n, fi.Name()), since, on); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func countFiles(p string) (int, error) {
+func countFiles(p string, since time.Time) (int, error) {
 	st, err := os.Stat(p)
 	if errors.Is(err, fs.ErrNotExist) {
 		return 0, nil
@@ -199,6 +251,9 @@ func countFiles(p string) (int, error) {
 		return 0, err
 	}
 	if !st.IsDir() {
+		if !since.IsZero() && st.ModTime().Before(since) {
+			return 0, nil
+		}
 		return 1, nil
 	}
 	fis, err := ioutil.ReadDir(p)
@@ -208,22 +263,24 @@ func countFiles(p string) (int, error) {
 	cnt := 0
 	for _, fi := range fis {
 		if fi.IsDir() {
-			if c, err := countFiles(path.Join(p, fi.Name())); err != nil {
+			if c, err := countFiles(path.Join(p, fi.Name()), since); err != nil {
 				return 0, err
 			} else {
 				cnt += c
 			}
 		} else if fi.Mode().IsRegular() {
-			cnt += 1
+			if since.IsZero() || !fi.ModTime().Before(since) {
+				cnt += 1
+			}
 		}
 	}
 	return cnt, nil
 }
 
-func (t *taskBackup) _countFiles(chainDir string, names []string) (int, error) {
+func (t *taskBackup) _countFiles(chainDir string, names []string, since time.Time) (int, error) {
 	count := 0
 	for _, name := range names {
-		if cnt, err := countFiles(path.Join(chainDir, name)); err != nil {
+		if cnt, err := countFiles(path.Join(chainDir, name), since); err != nil {
 			return 0, err
 		} else {
 			count += cnt
@@ -245,7 +302,6 @@ func (t *taskBackup) OnWrite(int64) error {
 }
 
 func (t *taskBackup) _backup() error {
-	defer t.chain.ensureDatabase()
 	defer t.fd.Close()
 	defer t.zw.Close()
 
@@ -254,14 +310,14 @@ func (t *taskBackup) _backup() error {
 	}, t.extra...)
 
 	chainDir := t.chain.cfg.AbsBaseDir()
-	if cnt, err := t._countFiles(chainDir, names); err != nil {
+	if cnt, err := t._countFiles(chainDir, names, time.Time{}); err != nil {
 		return err
 	} else {
 		t.total = int32(cnt)
 	}
 
 	for _, name := range names {
-		if err := zipWrite(t.zw, chainDir, name, t.OnWrite); err != nil {
+		if err := zipWrite(t.zw, chainDir, name, time.Time{}, t.OnWrite); err != nil {
 			return err
 		}
 	}
@@ -291,6 +347,105 @@ func newTaskBackup(chain *singleChain, file string, extra []string) chainTask {
 	}
 }
 
+// backupIncremental writes changes of the database recorded in the journal
+// up to the last checkpoint and contracts added after the base backup.
+// WAL and extra files are written entirely. It runs while the chain is
+// running, and the journal is rebased on the new backup after it.
+func (c *singleChain) backupIncremental(file string, extra []string) (ret error) {
+	if !c.bkLock.TryLock() {
+		return errors.InvalidStateError.New("BackupInProgress")
+	}
+	defer c.bkLock.Unlock()
+
+	c.dbLock.RLock()
+	j := c.journal
+	c.dbLock.RUnlock()
+	if j == nil {
+		return errors.InvalidStateError.New("NoBaseBackup")
+	}
+	chainDir := c.cfg.AbsBaseDir()
+	base, err := readJournalBase(chainDir)
+	if err != nil {
+		return err
+	}
+	if base == nil {
+		return errors.InvalidStateError.New("NoBaseBackup")
+	}
+	height, offset, err := j.Snapshot()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+
+	tmp, err := ioutil.TempFile(path.Dir(file), TemporalBackupFile)
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal file")
+	}
+	defer func() {
+		if ret != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	zw := zip.NewWriter(tmp)
+	err = writeIncrementalBackup(zw, chainDir, &BackupInfo{
+		NID:        common.HexInt32{Value: int32(c.NID())},
+		CID:        common.HexInt32{Value: int32(c.CID())},
+		Channel:    c.Channel(),
+		Height:     height,
+		Codec:      codec.BC.Name(),
+		Base:       base.Name,
+		BaseHeight: base.Height,
+	}, offset, start, base.Time, extra)
+	if err2 := zw.Close(); err == nil {
+		err = err2
+	}
+	if err2 := tmp.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+	if err := j.Rebase(offset, &JournalBase{
+		Name:   path.Base(file),
+		Height: height,
+		Time:   start,
+	}); err != nil {
+		c.logger.Warnf("Fail to rebase journal err=%+v", err)
+	}
+	return nil
+}
+
+func writeIncrementalBackup(zw *zip.Writer, chainDir string, info *BackupInfo, offset int64, start, since time.Time, extra []string) error {
+	if err := writeBackupInfo(zw, info); err != nil {
+		return err
+	}
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     IncrementalDBFile,
+		Method:   zip.Deflate,
+		Modified: start,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "writeToZip: fail to create entry %s", IncrementalDBFile)
+	}
+	if err := copyJournalChanges(chainDir, offset, w); err != nil {
+		return err
+	}
+	onWrite := func(int64) error { return nil }
+	for _, name := range append([]string{DefaultWALDir}, extra...) {
+		if err := zipWrite(zw, chainDir, name, time.Time{}, onWrite); err != nil {
+			return err
+		}
+	}
+	return zipWrite(zw, chainDir, DefaultContractDir, since, onWrite)
+}
+
 func writeBackupInfo(zw *zip.Writer, info *BackupInfo) error {
 	bs, err := json.Marshal(info)
 	if err != nil {
@@ -324,3 +479,38 @@ func ReadBackupInfo(zr *zip.Reader) (*BackupInfo, error) {
 	}
 	return info, nil
 }
+
+// ApplyDBChanges applies changes in IncrementalDBFile of the incremental
+// backup to the database.
+func ApplyDBChanges(database db.Database, r io.Reader) error {
+	dec := codec.BC.NewDecoder(r)
+	defer dec.Close()
+
+	buckets := make(map[string]db.Bucket)
+	for {
+		change := new(dbChange)
+		if err := dec.Decode(change); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.CriticalFormatError.Wrap(err, "InvalidDBChanges")
+		}
+		bk, ok := buckets[change.Bucket]
+		if !ok {
+			var err error
+			if bk, err = database.GetBucket(db.BucketID(change.Bucket)); err != nil {
+				return err
+			}
+			buckets[change.Bucket] = bk
+		}
+		if change.Deleted {
+			if err := bk.Delete(change.Key); err != nil {
+				return err
+			}
+		} else {
+			if err := bk.Set(change.Key, change.Value); err != nil {
+				return err
+			}
+		}
+	}
+}
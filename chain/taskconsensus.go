@@ -45,6 +45,7 @@ func (t *taskConsensus) DetailOf(s State) string {
 }
 
 func (t *taskConsensus) Start() error {
+	t.chain.ensurePruningDatabase()
 	if err := t.chain.prepareManagers(); err != nil {
 		t.result.SetValue(err)
 		return err
@@ -59,6 +60,8 @@ func (t *taskConsensus) Start() error {
 
 func (t *taskConsensus) _start(c *singleChain) error {
 	c.sm.Start()
+	c.sp.Start(c.pdb, c.cfg.PruneKeepBlocks, c.pruneInterval())
+	c.startJournalSync()
 	if err := c.cs.Start(); err != nil {
 		return err
 	}
@@ -0,0 +1,305 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/chain/archive"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const TemporalExportFile = ".export"
+
+var exportStates = map[State]string{
+	Starting: "export starting",
+	Stopping: "export stopping",
+	Failed:   "export failed",
+	Finished: "export done",
+}
+
+type taskExport struct {
+	chain   *singleChain
+	file    string
+	from    int64
+	to      int64
+	flags   int
+	current int64
+	stop    int32
+	result  resultStore
+}
+
+func (t *taskExport) String() string {
+	return fmt.Sprintf("Export(file=%s,from=%d,to=%d,flags=%#x)",
+		path.Base(t.file), t.from, t.to, t.flags)
+}
+
+func (t *taskExport) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("export %d/%d", atomic.LoadInt64(&t.current), t.to)
+	default:
+		if st, ok := exportStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskExport) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		return err
+	}
+	blk, err := t.chain.bm.GetLastBlock()
+	if err != nil {
+		t.chain.releaseManagers()
+		return err
+	}
+	// votes for the block are in the next block.
+	if t.from < 1 || t.from > t.to || t.to >= blk.Height() {
+		t.chain.releaseManagers()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d,last=%d)", t.from, t.to, blk.Height())
+	}
+	go func() {
+		err := t._export()
+		t.chain.releaseManagers()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskExport) _interrupted() bool {
+	return atomic.LoadInt32(&t.stop) != 0
+}
+
+func (t *taskExport) _export() (rerr error) {
+	tmp, err := ioutil.TempFile(path.Dir(t.file), TemporalExportFile)
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal file")
+	}
+	defer func() {
+		tmp.Close()
+		if rerr != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err := tmp.Chmod(0644); err != nil {
+		return err
+	}
+
+	w, err := archive.NewWriter(tmp, &archive.Header{
+		CID:   int64(t.chain.CID()),
+		NID:   int64(t.chain.NID()),
+		From:  t.from,
+		To:    t.to,
+		Flags: t.flags,
+	})
+	if err != nil {
+		return err
+	}
+	if (t.flags & archive.FlagState) != 0 {
+		t.chain.logger.Infof("Export state height=%d", t.from-1)
+		sdb := newArchiveStateDB(w)
+		if err := t.chain.bm.ExportBlocks(t.from-1, t.from-1, sdb, t.OnExport); err != nil {
+			return err
+		}
+	}
+
+	var next module.Block
+	for h := t.from; h <= t.to; h++ {
+		if t._interrupted() {
+			return errors.ErrInterrupted
+		}
+		blk := next
+		if blk == nil {
+			if blk, err = t.chain.bm.GetBlockByHeight(h); err != nil {
+				return err
+			}
+		}
+		if next, err = t.chain.bm.GetBlockByHeight(h + 1); err != nil {
+			return err
+		}
+		b, err := t._blockOf(blk, next)
+		if err != nil {
+			return err
+		}
+		if err := w.WriteBlock(b); err != nil {
+			return err
+		}
+		atomic.StoreInt64(&t.current, h)
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), t.file)
+}
+
+func (t *taskExport) _blockOf(blk, next module.Block) (*archive.Block, error) {
+	bs, err := module.BlockDataToBytes(blk)
+	if err != nil {
+		return nil, err
+	}
+	b := &archive.Block{
+		Height: blk.Height(),
+		Block:  bs,
+		Votes:  next.Votes().Bytes(),
+	}
+	if (t.flags & archive.FlagReceipts) != 0 {
+		// receipts of the transactions are in the result of the next block.
+		if b.PatchReceipts, err = t._receiptsOf(next.Result(), module.TransactionGroupPatch); err != nil {
+			return nil, err
+		}
+		if b.NormalReceipts, err = t._receiptsOf(next.Result(), module.TransactionGroupNormal); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (t *taskExport) _receiptsOf(result []byte, g module.TransactionGroup) ([][]byte, error) {
+	rl, err := t.chain.sm.ReceiptListFromResult(result, g)
+	if err != nil {
+		return nil, err
+	}
+	receipts := [][]byte{}
+	for itr := rl.Iterator(); itr.Has(); t.chain.logger.Must(itr.Next()) {
+		r, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		receipts = append(receipts, r.Bytes())
+	}
+	return receipts, nil
+}
+
+func (t *taskExport) OnExport(height int64, resolved, unresolved int) error {
+	if t._interrupted() {
+		return errors.ErrInterrupted
+	}
+	return nil
+}
+
+func (t *taskExport) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskExport) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskExport(chain *singleChain, file string, from, to int64, flags int) chainTask {
+	return &taskExport{
+		chain: chain,
+		file:  file,
+		from:  from,
+		to:    to,
+		flags: flags,
+	}
+}
+
+// archiveStateDB writes entries to the archive. It remembers recently
+// written keys of the buckets with hasher in a fixed size cache, so that
+// the same entry is usually written only once. Duplicated entries are
+// possible, and they are ignored on import.
+type archiveStateDB struct {
+	w       *archive.Writer
+	buckets map[db.BucketID]*archiveStateBucket
+}
+
+const archiveKeyCacheSize = 1 << 16
+
+type archiveStateBucket struct {
+	w    *archive.Writer
+	id   db.BucketID
+	keys [][]byte
+}
+
+func (b *archiveStateBucket) slotOf(key []byte) int {
+	var idx int
+	for i := 0; i < len(key) && i < 4; i++ {
+		idx = (idx << 8) | int(key[i])
+	}
+	return idx % len(b.keys)
+}
+
+func (b *archiveStateBucket) Get(key []byte) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *archiveStateBucket) Has(key []byte) (bool, error) {
+	if b.keys == nil {
+		return false, nil
+	}
+	return bytes.Equal(b.keys[b.slotOf(key)], key), nil
+}
+
+func (b *archiveStateBucket) Set(key []byte, value []byte) error {
+	if b.keys != nil {
+		slot := b.slotOf(key)
+		if bytes.Equal(b.keys[slot], key) {
+			return nil
+		}
+		b.keys[slot] = append([]byte(nil), key...)
+	}
+	return b.w.WriteState(&archive.StateEntry{
+		Bucket: string(b.id),
+		Key:    key,
+		Value:  value,
+	})
+}
+
+func (b *archiveStateBucket) Delete(key []byte) error {
+	return errors.UnsupportedError.New("DeleteOnArchive")
+}
+
+func (d *archiveStateDB) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, ok := d.buckets[id]
+	if !ok {
+		bk = &archiveStateBucket{
+			w:  d.w,
+			id: id,
+		}
+		if id.Hasher() != nil {
+			bk.keys = make([][]byte, archiveKeyCacheSize)
+		}
+		d.buckets[id] = bk
+	}
+	return bk, nil
+}
+
+func (d *archiveStateDB) Close() error {
+	return nil
+}
+
+func newArchiveStateDB(w *archive.Writer) db.Database {
+	return &archiveStateDB{
+		w:       w,
+		buckets: make(map[db.BucketID]*archiveStateBucket),
+	}
+}
@@ -0,0 +1,145 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain/archive"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestArchiveStateDB_Basic(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	w, err := archive.NewWriter(buf, &archive.Header{
+		From: 10, To: 10, Flags: archive.FlagState,
+	})
+	assert.NoError(t, err)
+
+	value := []byte("node")
+	key := crypto.SHA3Sum256(value)
+	sdb := newArchiveStateDB(w)
+	bk, err := sdb.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set(key, value))
+	// the same node is written only once
+	assert.NoError(t, bk.Set(key, value))
+	has, err := bk.Has(key)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	// the last one is effective for buckets without hasher
+	pk, err := sdb.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, pk.Set([]byte("last"), []byte{8}))
+	assert.NoError(t, pk.Set([]byte("last"), []byte{9}))
+
+	assert.NoError(t, w.WriteBlock(&archive.Block{Height: 10}))
+	assert.NoError(t, w.Close())
+
+	r, err := archive.NewReader(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	dbase := db.NewMapDB()
+	si := &stateImporter{database: dbase, buckets: make(map[string]db.Bucket)}
+	states := 0
+	for {
+		item, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if e, ok := item.(*archive.StateEntry); ok {
+			assert.NoError(t, si.apply(e))
+			states += 1
+		}
+	}
+	assert.Equal(t, 3, states)
+
+	bk, err = dbase.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	v, err := bk.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, value, v)
+	pk, err = dbase.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	v, err = pk.Get([]byte("last"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{9}, v)
+
+	// modified entry is rejected
+	err = si.apply(&archive.StateEntry{
+		Bucket: string(db.MerkleTrie),
+		Key:    key,
+		Value:  []byte("modified"),
+	})
+	assert.Error(t, err)
+}
+
+func newValidatorChain(t *testing.T, w module.Wallet, dir string) *singleChain {
+	genesis := fmt.Sprintf(`{
+		"accounts": [
+			{"name": "treasury", "address": "hx1000000000000000000000000000000000000000", "balance": "0x0"},
+			{"name": "god", "address": "hx0000000000000000000000000000000000000000", "balance": "0x0"}
+		],
+		"message": "",
+		"nid": "0x3",
+		"chain": {"validatorList": ["%s"]}
+	}`, w.Address())
+	cfgFile := path.Join(dir, "config.json")
+	cfg := &Config{
+		NID:      3,
+		DBType:   string(db.GoLevelDBBackend),
+		Genesis:  json.RawMessage(genesis),
+		BaseDir:  "chain",
+		FilePath: cfgFile,
+	}
+	assert.NoError(t, cfg.Save())
+	return newTestChain(t, w, cfgFile)
+}
+
+func TestChain_ExportAndImportArchive(t *testing.T) {
+	w := wallet.New()
+	c1 := newValidatorChain(t, w, t.TempDir())
+	defer c1.Term()
+
+	assert.NoError(t, c1.Start())
+	for i := 0; i < 300 && c1.lastBlockHeight() < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.NoError(t, c1.Stop())
+	waitChainState(t, c1, Stopped)
+	if !assert.True(t, c1.lastBlockHeight() >= 4) {
+		return
+	}
+
+	file := path.Join(t.TempDir(), "blocks.arc")
+	assert.NoError(t, c1.Export(file, 2, 3, archive.FlagState|archive.FlagReceipts))
+	waitChainState(t, c1, Finished, Failed)
+	assert.NoError(t, c1.lastErr)
+
+	// import blocks with the state to the empty chain
+	c2 := newValidatorChain(t, w, t.TempDir())
+	defer c2.Term()
+	assert.NoError(t, c2.ImportArchive(file))
+	waitChainState(t, c2, Finished, Failed)
+	assert.NoError(t, c2.lastErr)
+	assert.EqualValues(t, 3, c2.lastBlockHeight())
+
+	hashOf := func(c *singleChain, height int64) []byte {
+		assert.NoError(t, c.prepareManagers())
+		defer c.releaseManagers()
+		blk, err := c.bm.GetBlockByHeight(height)
+		assert.NoError(t, err)
+		return blk.ID()
+	}
+	assert.Equal(t, hashOf(c1, 3), hashOf(c2, 3))
+}
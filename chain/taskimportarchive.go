@@ -0,0 +1,242 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/chain/archive"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+var importArchiveStates = map[State]string{
+	Starting: "import archive starting",
+	Stopping: "import archive stopping",
+	Failed:   "import archive failed",
+	Finished: "import archive done",
+}
+
+// taskImportArchive imports blocks in the archive. Each block is verified
+// with its commit votes, then it's imported by executing transactions of
+// the previous block as the consensus does.
+type taskImportArchive struct {
+	chain   *singleChain
+	file    string
+	fd      *os.File
+	reader  *archive.Reader
+	current int64
+	stop    int32
+	result  resultStore
+}
+
+func (t *taskImportArchive) String() string {
+	return fmt.Sprintf("ImportArchive(file=%s)", path.Base(t.file))
+}
+
+func (t *taskImportArchive) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("import archive %d/%d",
+			atomic.LoadInt64(&t.current), t.reader.Header().To)
+	default:
+		if st, ok := importArchiveStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskImportArchive) Start() (ret error) {
+	fd, err := os.Open(t.file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if ret != nil {
+			fd.Close()
+		}
+	}()
+	r, err := archive.NewReader(fd)
+	if err != nil {
+		return err
+	}
+	h := r.Header()
+	if h.CID != int64(t.chain.CID()) || h.NID != int64(t.chain.NID()) {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidChain(cid=%#x,nid=%#x)", h.CID, h.NID)
+	}
+	last := t.chain.lastBlockHeight()
+	if last < h.From-1 && !(last == 0 && h.Has(archive.FlagState)) {
+		return errors.InvalidStateError.Errorf(
+			"NoBaseBlock(from=%d,last=%d)", h.From, last)
+	}
+	t.fd = fd
+	t.reader = r
+	atomic.StoreInt64(&t.current, last)
+	go func() {
+		err := t._import(last)
+		t.reader.Close()
+		t.fd.Close()
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+func (t *taskImportArchive) _interrupted() bool {
+	return atomic.LoadInt32(&t.stop) != 0
+}
+
+func (t *taskImportArchive) _import(last int64) error {
+	var states *stateImporter
+	// state is required only for the empty chain.
+	if last < t.reader.Header().From-1 {
+		t.chain.logger.Infof("Import state height=%d", t.reader.Header().From-1)
+		states = &stateImporter{
+			database: t.chain.Database(),
+			buckets:  make(map[string]db.Bucket),
+		}
+	}
+	managers := false
+	defer func() {
+		if managers {
+			t.chain.releaseManagers()
+		}
+	}()
+	for {
+		if t._interrupted() {
+			return errors.ErrInterrupted
+		}
+		item, err := t.reader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		switch o := item.(type) {
+		case *archive.StateEntry:
+			if states != nil {
+				if err := states.apply(o); err != nil {
+					return err
+				}
+			}
+		case *archive.Block:
+			if !managers {
+				if err := t.chain.prepareManagers(); err != nil {
+					return err
+				}
+				managers = true
+			}
+			if err := t._importBlock(o); err != nil {
+				return errors.Wrapf(err, "fail to import block height=%d", o.Height)
+			}
+			atomic.StoreInt64(&t.current, o.Height)
+		}
+	}
+}
+
+func (t *taskImportArchive) _importBlock(b *archive.Block) error {
+	bm := t.chain.bm
+	blk, err := bm.NewBlockDataFromReader(bytes.NewReader(b.Block))
+	if err != nil {
+		return err
+	}
+	if blk.Height() != b.Height {
+		return errors.InvalidStateError.Errorf(
+			"InvalidBlockHeight(height=%d,exp=%d)", blk.Height(), b.Height)
+	}
+	if ob, err := bm.GetBlockByHeight(b.Height); err == nil {
+		if !bytes.Equal(ob.ID(), blk.ID()) {
+			return errors.InvalidStateError.Errorf(
+				"DifferentBlock(id=%#x,exp=%#x)", blk.ID(), ob.ID())
+		}
+		return nil
+	} else if !errors.NotFoundError.Equals(err) {
+		return err
+	}
+
+	prev, err := bm.GetBlockByHeight(b.Height - 1)
+	if err != nil {
+		return err
+	}
+	votes := t.chain.CommitVoteSetDecoder()(b.Votes)
+	if votes == nil {
+		return errors.InvalidStateError.New("InvalidVotes")
+	}
+	if _, err := votes.VerifyBlock(blk, prev.NextValidators()); err != nil {
+		return err
+	}
+
+	ch := make(chan error, 1)
+	_, err = bm.ImportBlock(blk, 0, func(bc module.BlockCandidate, err error) {
+		if err == nil {
+			err = bm.Finalize(bc)
+			bc.Dispose()
+		}
+		ch <- err
+	})
+	if err != nil {
+		return err
+	}
+	return <-ch
+}
+
+func (t *taskImportArchive) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskImportArchive) Wait() error {
+	return t.result.Wait()
+}
+
+func newTaskImportArchive(chain *singleChain, file string) chainTask {
+	return &taskImportArchive{
+		chain: chain,
+		file:  file,
+	}
+}
+
+type stateImporter struct {
+	database db.Database
+	buckets  map[string]db.Bucket
+}
+
+func (s *stateImporter) apply(e *archive.StateEntry) error {
+	bk, ok := s.buckets[e.Bucket]
+	if !ok {
+		var err error
+		if bk, err = s.database.GetBucket(db.BucketID(e.Bucket)); err != nil {
+			return err
+		}
+		s.buckets[e.Bucket] = bk
+	}
+	// entries of the buckets with hasher can be verified by their keys.
+	if hasher := db.BucketID(e.Bucket).Hasher(); hasher != nil {
+		if !bytes.Equal(hasher.Hash(e.Value), e.Key) {
+			return errors.InvalidStateError.Errorf(
+				"InvalidStateEntry(bucket=%s,key=%#x)", e.Bucket, e.Key)
+		}
+	}
+	return bk.Set(e.Key, e.Value)
+}
@@ -234,6 +234,9 @@ func (t *taskImportICON) prepareConsensus() error {
 	if err := os.RemoveAll(dbDir); err != nil {
 		return errors.Wrapf(err, "FailToRemoveDB(%s)", dbDir)
 	}
+	if err := removeJournal(chainDir); err != nil {
+		return errors.Wrapf(err, "FailToRemoveJournal(%s)", chainDir)
+	}
 	if err := os.Rename(tmpDir, dbDir); err != nil {
 		return errors.Wrapf(err, "FailToRenameDB(%s->%s)", tmpDir, dbDir)
 	}
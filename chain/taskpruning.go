@@ -266,14 +266,20 @@ func (t *taskPruning) _prune(gsfile, dbtype string, height int64) (rerr error) {
 	}
 
 	c.logger.Infof("Reopen DB %s", chainDir)
+	c.mtx.Lock()
 	c.cfg.DBType = dbtype
 	c.cfg.GenesisStorage = g
 	c.cfg.Genesis = g.Genesis()
-
-	if err := c.cfg.Save(); err != nil {
+	err = c.cfg.Save()
+	c.mtx.Unlock()
+	if err != nil {
 		return errors.UnknownError.Wrap(err, "fail to store configuration")
 	}
 
+	// changes in the journal are not applicable to the new database.
+	if err := removeJournal(chainDir); err != nil {
+		c.logger.Warnf("Fail to remove journal err=%+v", err)
+	}
 	return nil
 }
 
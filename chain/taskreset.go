@@ -117,6 +117,9 @@ func (t *taskReset) _cleanUp() error {
 	if err := os.RemoveAll(DBDir); err != nil {
 		return err
 	}
+	if err := removeJournal(chainDir); err != nil {
+		return err
+	}
 	CacheDir := path.Join(chainDir, DefaultCacheDir)
 	if err := os.RemoveAll(CacheDir); err != nil {
 		return err
@@ -382,6 +385,9 @@ func (t *taskReset) _syncBlocksWithDB(height int64, blockHash []byte, votes modu
 	if ret = rb.Rename(dbDirNew, dbDir); ret != nil {
 		return
 	}
+	if ret = rb.Delete(path.Join(chainDir, DefaultJournalDir)); ret != nil {
+		return
+	}
 	t.chain.ensureDatabase()
 	rb.Append(func(revert bool) {
 		if revert {
@@ -430,6 +436,9 @@ func (t *taskReset) _syncBlocksWithNetwork(height int64, blockHash []byte) (rblk
 	if ret = rb.Delete(dbDir); ret != nil {
 		return
 	}
+	if ret = rb.Delete(path.Join(chainDir, DefaultJournalDir)); ret != nil {
+		return
+	}
 	c.ensureDatabase()
 	rb.Append(func(revert bool) {
 		if revert {
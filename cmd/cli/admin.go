@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -49,6 +50,25 @@ func ReadParam(param string) ([]byte, error) {
 	}
 }
 
+func parseTrustedBlock(s string) (*node.ChainResetParam, error) {
+	ps := strings.SplitN(s, ":", 2)
+	if len(ps) != 2 {
+		return nil, errors.Errorf("invalid trusted block %q, it should be HEIGHT:HASH", s)
+	}
+	height, err := strconv.ParseInt(ps[0], 0, 64)
+	if err != nil || height <= 1 {
+		return nil, errors.Errorf("invalid height of trusted block %q", ps[0])
+	}
+	blockHash, err := hex.DecodeString(strings.TrimPrefix(ps[1], "0x"))
+	if err != nil {
+		return nil, errors.Errorf("invalid hash of trusted block %q", ps[1])
+	}
+	return &node.ChainResetParam{
+		Height:    height,
+		BlockHash: blockHash,
+	}, nil
+}
+
 func AdminPersistentPreRunE(vc *viper.Viper, adminClient *node.UnixDomainSockHttpClient) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		nodeSock := vc.GetString("node_sock")
@@ -146,6 +166,18 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				param.NephewsLimit = &nephewsLimit
 			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.EventIndex, _ = fs.GetBool("event_index")
+			param.PruneKeepBlocks, _ = fs.GetInt64("prune_keep_blocks")
+			param.PruneInterval, _ = fs.GetInt64("prune_interval")
+			param.TxPoolSenderLimit, _ = fs.GetInt("tx_pool_sender_limit")
+			param.FastSyncWindow, _ = fs.GetInt("fast_sync_window")
+			if trustedBlock, _ := fs.GetString("trusted_block"); len(trustedBlock) > 0 {
+				tb, err := parseTrustedBlock(trustedBlock)
+				if err != nil {
+					return err
+				}
+				param.TrustedBlock = tb
+			}
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -203,6 +235,12 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.Bool("event_index", false, "Index event logs of finalized blocks for icx_getLogs")
+	joinFlags.Int64("prune_keep_blocks", 0, "Number of recent blocks to keep world states while running (0: disable online pruning)")
+	joinFlags.Int64("prune_interval", 0, "Number of blocks between online pruning (0: uses system default value)")
+	joinFlags.Int("tx_pool_sender_limit", 0, "Maximum number of transactions from a sender in normal transaction pool (0: uses system default value, -1: no limit)")
+	joinFlags.Int("fast_sync_window", 0, "Number of blocks fetched ahead in fast sync from multiple peers at the same time (0: uses system default value)")
+	joinFlags.String("trusted_block", "", "Trusted block as HEIGHT:HASH to sync world state at the height from peers instead of blocks from genesis")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -359,6 +397,62 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	importFlags.Int64("height", 0, "Block Height")
 	MarkAnnotationRequired(importFlags, "db_path", "height")
 
+	exportCmd := &cobra.Command{
+		Use:   "export CID FILE",
+		Short: "Start to export blocks to the archive file",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.ChainExportParam{}
+			param.From, _ = fs.GetInt64("from")
+			param.To, _ = fs.GetInt64("to")
+			param.Receipts, _ = fs.GetBool("receipts")
+			param.State, _ = fs.GetBool("state")
+			file, err := filepath.Abs(args[1])
+			if err != nil {
+				return err
+			}
+			param.File = file
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/export"
+			if _, err = adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(exportCmd)
+	exportFlags := exportCmd.Flags()
+	exportFlags.Int64("from", 0, "Height of the first block")
+	exportFlags.Int64("to", 0, "Height of the last block")
+	exportFlags.Bool("receipts", false, "Include receipts of transactions")
+	exportFlags.Bool("state", false, "Include the state before the first block")
+	MarkAnnotationRequired(exportFlags, "from", "to")
+
+	importArchiveCmd := &cobra.Command{
+		Use:   "import-archive CID FILE",
+		Short: "Start to import blocks from the archive file",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := filepath.Abs(args[1])
+			if err != nil {
+				return err
+			}
+			param := &node.ChainImportArchiveParam{File: file}
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/import-archive"
+			if _, err = adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(importArchiveCmd)
+
 	pruneCmd := &cobra.Command{
 		Use:   "prune CID",
 		Short: "Start to prune the database based on the height",
@@ -385,6 +479,62 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	pruneFlags.Int64("height", 0, "Block Height")
 	MarkAnnotationRequired(pruneFlags, "height")
 
+	banCmd := &cobra.Command{
+		Use:   "ban CID ADDRESS",
+		Short: "Ban the peer",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			param := &node.BanPeerParam{ID: args[1]}
+			param.Duration, _ = fs.GetString("duration")
+			param.Reason, _ = fs.GetString("reason")
+
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/ban"
+			_, err := adminClient.PostWithJson(reqUrl, param, &v)
+			if err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(banCmd)
+	banFlags := banCmd.Flags()
+	banFlags.String("duration", "", "Duration of the ban, e.g. 30m, 24h (default:1h)")
+	banFlags.String("reason", "", "Reason of the ban")
+
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "unban CID ADDRESS",
+		Short: "Unban the peer",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/ban/" + args[1]
+			if _, err := adminClient.Delete(reqUrl, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}, &cobra.Command{
+		Use:   "bans CID",
+		Short: "List banned peers",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			l := make([]*node.BannedPeerView, 0)
+			reqUrl := node.UrlChain + "/" + args[0] + "/ban"
+			resp, err := adminClient.Get(reqUrl, &l)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, l); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	})
+
 	backupCmd := &cobra.Command{
 		Use:   "backup CID",
 		Short: "Start to backup the channel",
@@ -392,8 +542,10 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fs := cmd.Flags()
 			manual, _ := fs.GetBool("manual")
+			incremental, _ := fs.GetBool("incremental")
 			param := &node.ChainBackupParam{
-				Manual: manual,
+				Manual:      manual,
+				Incremental: incremental,
 			}
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/backup"
@@ -408,6 +560,7 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	rootCmd.AddCommand(backupCmd)
 	backupFlags := backupCmd.Flags()
 	backupFlags.Bool("manual", false, "Manual backup mode (just release database)")
+	backupFlags.Bool("incremental", false, "Incremental backup mode (only changes after the previous backup)")
 
 	genesisCmd := &cobra.Command{
 		Use:   "genesis CID FILE",
@@ -573,6 +726,43 @@ func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comma
 	NewBackupCmd(rootCmd, &adminClient)
 	NewRestoreCmd(rootCmd, &adminClient)
 
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Query audit records of administrative operations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			params := &url.Values{}
+			if limit, _ := fs.GetInt("limit"); limit > 0 {
+				params.Add("limit", strconv.Itoa(limit))
+			}
+			if user, _ := fs.GetString("user"); user != "" {
+				params.Add("user", user)
+			}
+			if since, _ := fs.GetString("since"); since != "" {
+				params.Add("since", since)
+			}
+			if verify, _ := fs.GetBool("verify"); verify {
+				params.Add("verify", "true")
+			}
+			var v []*node.AuditRecord
+			resp, err := adminClient.Get(node.UrlSystem+"/audit", &v, params)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(auditCmd)
+	auditFlags := auditCmd.Flags()
+	auditFlags.Int("limit", 100, "Maximum number of the last records")
+	auditFlags.String("user", "", "User of records (address, 'local' or 'anonymous')")
+	auditFlags.String("since", "", "Records since the time in RFC3339 (ex: 2023-01-02T15:04:05Z)")
+	auditFlags.Bool("verify", false, "Verify hash chain of all records")
+
 	return rootCmd, vc
 }
 
@@ -669,7 +859,7 @@ func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command
 		Short: "List users",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			l := make([]string, 0)
+			l := make([]*node.UserView, 0)
 			reqUrl := node.UrlUser
 			resp, err := adminClient.Get(reqUrl, &l)
 			if err != nil {
@@ -681,18 +871,50 @@ func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command
 			return nil
 		},
 	}, &cobra.Command{
+		Use:   "rm ADDRESS",
+		Short: "Remove user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			reqUrl := node.UrlUser + "/" + args[0]
+			var v string
+			if _, err := adminClient.Delete(reqUrl, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	})
+
+	addCmd := &cobra.Command{
 		Use:   "add ADDRESS",
 		Short: "Add user",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			reqUrl := node.UrlUser
 			param := &struct {
-				Id string `json:"id"`
+				Id     string               `json:"id"`
+				Role   node.Role            `json:"role"`
+				Chains map[string]node.Role `json:"chains,omitempty"`
 			}{Id: args[0]}
 			addr := &common.Address{}
 			if err := addr.SetString(param.Id); err != nil {
 				return errors.Wrap(err, "invalid Address format")
 			}
+			fs := cmd.Flags()
+			var err error
+			role, _ := fs.GetString("role")
+			if param.Role, err = node.ParseRole(role); err != nil {
+				return err
+			}
+			chainRoles, _ := fs.GetStringToString("chain_roles")
+			for cid, role := range chainRoles {
+				if param.Chains == nil {
+					param.Chains = make(map[string]node.Role)
+				}
+				if param.Chains[cid], err = node.ParseRole(role); err != nil {
+					return err
+				}
+			}
 			var v string
 			if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
 				return err
@@ -700,20 +922,34 @@ func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command
 			fmt.Println(v)
 			return nil
 		},
-	}, &cobra.Command{
-		Use:   "rm ADDRESS",
-		Short: "Remove user",
-		Args:  cobra.ExactArgs(1),
+	}
+	rootCmd.AddCommand(addCmd)
+	addFlags := addCmd.Flags()
+	addFlags.String("role", node.RoleAdmin.String(), "Role of the user for all chains (viewer,operator,admin)")
+	addFlags.StringToString("chain_roles", nil, "Roles of the user for specific chains, comma-separated 'CID=ROLE'")
+
+	roleCmd := &cobra.Command{
+		Use:   "role ADDRESS ROLE",
+		Short: "Set role of user (none,viewer,operator,admin)",
+		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			reqUrl := node.UrlUser + "/" + args[0]
+			param := &node.UserRoleParam{}
+			var err error
+			if param.Role, err = node.ParseRole(args[1]); err != nil {
+				return err
+			}
+			param.Chain, _ = cmd.Flags().GetString("chain")
+			reqUrl := node.UrlUser + "/" + args[0] + "/role"
 			var v string
-			if _, err := adminClient.Delete(reqUrl, &v); err != nil {
+			if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
 				return err
 			}
 			fmt.Println(v)
 			return nil
 		},
-	})
+	}
+	rootCmd.AddCommand(roleCmd)
+	roleCmd.Flags().String("chain", "", "CID of the chain to set the role only for it (none: removes the role for the chain)")
 	return rootCmd, vc
 }
 
@@ -17,13 +17,19 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
+	"path"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	v3 "github.com/icon-project/goloop/server/v3"
 )
@@ -56,9 +62,10 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		Short: "Get trace of the transaction",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			param := &v3.TransactionHashParam{
+			param := &v3.TraceParam{
 				Hash: jsonrpc.HexBytes(args[0]),
 			}
+			param.Mode, _ = cmd.Flags().GetString("mode")
 			trace, err := debugClient.Do("debug_getTrace", param, nil)
 			if err != nil {
 				return err
@@ -67,6 +74,170 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		},
 	}
 	rootCmd.AddCommand(traceCmd)
+	traceCmd.Flags().String("mode", "", "Trace mode (invoke, stateDiff)")
+
+	simulateCmd := &cobra.Command{
+		Use:   "simulate TX_FILE",
+		Short: "Simulate the transaction in the file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bs, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			param := &v3.SimulateTransactionParam{}
+			if err := json.Unmarshal(bs, &param.Transaction); err != nil {
+				return err
+			}
+			if height, _ := cmd.Flags().GetInt64("height"); height >= 0 {
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+			}
+			if trace, _ := cmd.Flags().GetBool("trace"); trace {
+				param.Trace = "0x1"
+			}
+			result, err := debugClient.Do("debug_simulateTransaction", param, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, result.Result)
+		},
+	}
+	rootCmd.AddCommand(simulateCmd)
+	simulateCmd.Flags().Int64("height", -1, "Height of the state to simulate on (default: last)")
+	simulateCmd.Flags().Bool("trace", false, "Include balance changes")
+
+	rootCmd.AddCommand(NewDebugWALCmd())
 
 	return rootCmd, vc
 }
+
+type walRecordOutput struct {
+	File    string                    `json:"file"`
+	Offset  int64                     `json:"offset"`
+	Length  int                       `json:"length"`
+	Valid   bool                      `json:"valid"`
+	Message *consensus.WALMessageInfo `json:"message,omitempty"`
+	Error   string                    `json:"error,omitempty"`
+}
+
+func walIDsOf(cmd *cobra.Command) []string {
+	if id, _ := cmd.Flags().GetString("id"); len(id) > 0 {
+		return []string{id}
+	}
+	return consensus.WALIDs
+}
+
+func NewDebugWALCmd() *cobra.Command {
+	walCmd := &cobra.Command{
+		Use:   "wal",
+		Short: "Inspect consensus WAL of the chain",
+		// WAL commands work on local files without DEBUG API.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	dumpCmd := &cobra.Command{
+		Use:   "dump WAL_DIR",
+		Short: "Print messages in WAL",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, _ := cmd.Flags().GetInt64("height")
+			var records []*walRecordOutput
+			for _, id := range walIDsOf(cmd) {
+				err := consensus.ReadWALRecords(path.Join(args[0], id), func(rec *consensus.WALRecord) error {
+					out := &walRecordOutput{
+						File:   rec.File,
+						Offset: rec.Offset,
+						Length: rec.Length,
+						Valid:  rec.Valid,
+					}
+					if rec.Error != nil {
+						out.Error = rec.Error.Error()
+					} else {
+						out.Message = rec.Info()
+						if height > 0 && out.Message.Height != height {
+							return nil
+						}
+					}
+					records = append(records, out)
+					return nil
+				})
+				if err != nil && !consensus.IsNotExist(err) {
+					return err
+				}
+			}
+			return JsonPrettyPrintln(os.Stdout, records)
+		},
+	}
+	walCmd.AddCommand(dumpCmd)
+	dumpCmd.Flags().String("id", "", "WAL ID (round, lock, commit, sign) (default: all)")
+	dumpCmd.Flags().Int64("height", 0, "Height of messages to print (default: all)")
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify WAL_DIR",
+		Short: "Verify checksums of records in WAL",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var nRecords, nCorrupted int
+			for _, id := range walIDsOf(cmd) {
+				err := consensus.ReadWALRecords(path.Join(args[0], id), func(rec *consensus.WALRecord) error {
+					nRecords++
+					if rec.Error != nil {
+						nCorrupted++
+						fmt.Fprintf(os.Stderr, "%s@%d: %v\n", rec.File, rec.Offset, rec.Error)
+					}
+					return nil
+				})
+				if err != nil && !consensus.IsNotExist(err) {
+					return err
+				}
+			}
+			fmt.Printf("records=%d corrupted=%d\n", nRecords, nCorrupted)
+			if nCorrupted > 0 {
+				return errors.Errorf("%d corrupted records", nCorrupted)
+			}
+			return nil
+		},
+	}
+	walCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().String("id", "", "WAL ID (round, lock, commit, sign) (default: all)")
+
+	summaryCmd := &cobra.Command{
+		Use:   "summary WAL_DIR",
+		Short: "Summarize WAL and print the consensus state of the height",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, _ := cmd.Flags().GetInt64("height")
+			res, err := consensus.SummarizeWAL(args[0], height)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, res)
+		},
+	}
+	walCmd.AddCommand(summaryCmd)
+	summaryCmd.Flags().Int64("height", 0, "Height to summarize (default: highest in WAL)")
+
+	replayCmd := &cobra.Command{
+		Use:   "replay WAL_DIR",
+		Short: "Replay WAL and print the consensus state after each record of the height",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, _ := cmd.Flags().GetInt64("height")
+			var steps []*consensus.WALReplayStep
+			_, err := consensus.ReplayWAL(args[0], height, func(s *consensus.WALReplayStep) error {
+				steps = append(steps, s)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, steps)
+		},
+	}
+	walCmd.AddCommand(replayCmd)
+	replayCmd.Flags().Int64("height", 0, "Height to replay (default: highest in WAL)")
+
+	return walCmd
+}
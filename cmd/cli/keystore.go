@@ -3,10 +3,14 @@ package cli
 import (
 	"encoding/hex"
 	"fmt"
-	"github.com/icon-project/goloop/common/wallet"
-	"github.com/spf13/cobra"
 	"io/ioutil"
 	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common/crypto/bls"
+	"github.com/icon-project/goloop/common/wallet"
 )
 
 func newKeystoreGenCmd(c string) *cobra.Command {
@@ -18,7 +22,24 @@ func newKeystoreGenCmd(c string) *cobra.Command {
 	out := flags.StringP("out", "o", "keystore.json", "Output file path")
 	pass := flags.StringP("password", "p", "gochain", "Password for the keystore")
 
+	dsa := flags.String("dsa", "", "DSA of the key for BTP network types (bls/bls12-381,eddsa/ed25519)")
+
 	cmd.Run = func(cmd *cobra.Command, args []string) {
+		if *dsa != "" && *dsa != wallet.DSASecp256k1 {
+			secret, err := wallet.NewDSASecret(*dsa)
+			if err != nil {
+				log.Panicf("Fail to generate key err=%+v", err)
+			}
+			ks, err := wallet.EncryptDSAKeyAsKeyStore(*dsa, secret, []byte(*pass))
+			if err != nil {
+				log.Panicf("Fail to generate keystore err=%+v", err)
+			}
+			if err := ioutil.WriteFile(*out, ks, 0600); err != nil {
+				log.Panicf("Fail to write keystore err=%+v", err)
+			}
+			fmt.Printf("%s ==> %s\n", *dsa, *out)
+			return
+		}
 		w := wallet.New()
 		ks, err := wallet.KeyStoreFromWallet(w, []byte(*pass))
 		if err != nil {
@@ -62,6 +83,14 @@ func publickeyFromKeyStore(c string) *cobra.Command {
 				pb = []byte(*pass)
 			}
 
+			if wallet.IsDSAKeyStore(kb) {
+				key, err := dsaKeyFromKeyStore(kb, pb)
+				if err != nil {
+					log.Panicf("Fail to decrypt KeyStore err=%+v", err)
+				}
+				fmt.Println("0x" + hex.EncodeToString(key))
+				return
+			}
 			w, err := wallet.NewFromKeyStore(kb, pb)
 			if err != nil {
 				log.Panicf("Fail to decrypt KeyStore err=%+v", err)
@@ -71,3 +100,25 @@ func publickeyFromKeyStore(c string) *cobra.Command {
 	}
 	return cmd
 }
+
+// dsaKeyFromKeyStore returns the key to register for the DSA. The key of
+// bls/bls12-381 includes the proof of possession of the private key.
+func dsaKeyFromKeyStore(kb, pb []byte) ([]byte, error) {
+	dsa, secret, err := wallet.DecryptDSAKeyStore(kb, pb)
+	if err != nil {
+		return nil, err
+	}
+	switch dsa {
+	case wallet.DSABLS12381:
+		sk, err := bls.ParsePrivateKey(secret)
+		if err != nil {
+			return nil, err
+		}
+		return ntm.NewBLS12381DSAKey(sk)
+	}
+	w, err := wallet.NewDSAWalletFromSecret(dsa, secret)
+	if err != nil {
+		return nil, err
+	}
+	return w.PublicKey(), nil
+}
@@ -479,6 +479,39 @@ func NewRpcCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command,
 	flags = scoreStatusCmd.Flags()
 	flags.Int("height", -1, "BlockHeight")
 
+	estimateRewardCmd := &cobra.Command{
+		Use:   "estimatereward ADDRESS",
+		Short: "Estimate I-Score of the account for the current and the next term",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			param := &v3.CallParam{
+				ToAddress: jsonrpc.Address("cx0000000000000000000000000000000000000000"),
+				DataType:  "call",
+				Data: map[string]interface{}{
+					"method": "estimateReward",
+					"params": map[string]interface{}{
+						"address": args[0],
+					},
+				},
+			}
+			height, err := intconv.ParseInt(cmd.Flag("height").Value.String(), 64)
+			if err != nil {
+				return err
+			}
+			if height != -1 {
+				param.Height = jsonrpc.HexInt(intconv.FormatInt(height))
+			}
+			reward, err := rpcClient.Call(param)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, reward)
+		},
+	}
+	rootCmd.AddCommand(estimateRewardCmd)
+	flags = estimateRewardCmd.Flags()
+	flags.Int("height", -1, "BlockHeight")
+
 	rootCmd.AddCommand(
 		&cobra.Command{
 			Use:   "btpnetwork ID [HEIGHT]",
@@ -25,9 +25,16 @@ type ServerConfig struct {
 	KeyStorePass  string          `json:"key_password,omitempty"`
 	isPresentPass bool
 
+	// DSAKeyStores are key stores for BTP network types using other DSAs.
+	// They are decrypted with KeyStorePass.
+	DSAKeyStores []json.RawMessage `json:"dsa_key_stores,omitempty"`
+
 	KeyPlugin     string            `json:"key_plugin,omitempty"`
 	KeyPlgOptions map[string]string `json:"key_plugin_options,omitempty"`
 
+	KeySigner        string            `json:"key_signer,omitempty"`
+	KeySignerOptions map[string]string `json:"key_signer_options,omitempty"`
+
 	Wallet module.Wallet `json:"-"`
 
 	LogLevel     string               `json:"log_level"`
@@ -52,6 +59,44 @@ func (cfg *ServerConfig) MakesureWallet(gen bool) error {
 	if cfg.Wallet != nil {
 		return nil
 	}
+	if err := cfg.makesureWallet(gen); err != nil {
+		return err
+	}
+	return cfg.attachDSAWallets()
+}
+
+func (cfg *ServerConfig) attachDSAWallets() error {
+	if len(cfg.DSAKeyStores) == 0 {
+		return nil
+	}
+	pass := cfg.KeyStorePass
+	if pass == "" {
+		pass = DefaultKeyStorePass
+	}
+	wallets := make(map[string]module.BaseWallet)
+	for _, ks := range cfg.DSAKeyStores {
+		dsa, w, err := wallet.NewDSAWalletFromKeyStore(ks, []byte(pass))
+		if err != nil {
+			return errors.Errorf("fail to decrypt DSA KeyStore err=%+v", err)
+		}
+		if _, ok := wallets[dsa]; ok {
+			return errors.Errorf("duplicate DSA KeyStore dsa=%s", dsa)
+		}
+		wallets[dsa] = w
+	}
+	cfg.Wallet = wallet.WithDSAWallets(cfg.Wallet, wallets)
+	return nil
+}
+
+func (cfg *ServerConfig) makesureWallet(gen bool) error {
+	if cfg.KeySigner != "" {
+		if w, err := wallet.OpenRemote(cfg.KeySigner, cfg.KeySignerOptions); err != nil {
+			return err
+		} else {
+			cfg.Wallet = w
+			return nil
+		}
+	}
 	if cfg.KeyPlugin != "" {
 		options := make(map[string]string)
 		for k, v := range cfg.KeyPlgOptions {
@@ -154,8 +199,11 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	//
 	rootPFlags.String("key_store", "", "KeyStore file for wallet")
 	rootPFlags.String("key_secret", "", "Secret (password) file for KeyStore")
+	rootPFlags.StringSlice("dsa_key_stores", nil, "KeyStore files for BTP network types using other DSAs, comma-separated")
 	rootPFlags.String("key_plugin", "", "KeyPlugin file for wallet")
 	rootPFlags.StringToString("key_plugin_options", nil, "KeyPlugin options")
+	rootPFlags.String("key_signer", "", "Remote signer address for wallet (unix://PATH or tcp://HOST:PORT)")
+	rootPFlags.StringToString("key_signer_options", nil, "Remote signer options (cert,key,ca,server_name,timeout)")
 	//
 	rootPFlags.String("log_forwarder_vendor", "", "LogForwarder vendor (fluentd,logstash)")
 	rootPFlags.String("log_forwarder_address", "", "LogForwarder address")
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+var srcType string
+var dstType string
+
+func convert(srcPath, dstPath string) error {
+	if _, err := os.Stat(dstPath); err == nil {
+		return errors.Errorf("destination already exists path=%s", dstPath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if _, err := os.Stat(srcPath); err != nil {
+		return err
+	}
+
+	src, err := db.Open(srcPath, srcType, "")
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := db.Open(dstPath, dstType, "")
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	fmt.Printf("Convert %s(%s) to %s(%s)\n", srcPath, srcType, dstPath, dstType)
+	start := time.Now()
+	err = db.Copy(dst, src, func(count int) {
+		fmt.Printf("\rCopied entries : %d", count)
+	})
+	fmt.Println()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Done in %s\n", time.Since(start).Round(time.Second))
+	return nil
+}
+
+func er(msg interface{}) {
+	_, _ = fmt.Fprintln(os.Stderr, "Error:", msg)
+	os.Exit(1)
+}
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   os.Args[0] + " <src_path> <dst_path>",
+		Short: "Copy all the entries of the database to new database",
+		Long: "Copy all the entries of the database to new database.\n" +
+			"For example, src_path is .chain/hxd81df51476cee82617f6fa658ebecc31d24ddce3/bfdc51/db/bfdc51\n" +
+			"Stop the node before conversion, then replace the database with the new one\n" +
+			"and set db_type of the chain to the destination type.",
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := convert(args[0], args[1]); err != nil {
+				er(err)
+			}
+		},
+	}
+
+	types := strings.Join(db.RegisteredBackendTypes(), ", ")
+	flag := rootCmd.PersistentFlags()
+	flag.StringVar(&srcType, "src_type", string(db.GoLevelDBBackend),
+		fmt.Sprintf("Name of source database system (%s)", types))
+	flag.StringVar(&dstType, "dst_type", string(db.PebbleBackend),
+		fmt.Sprintf("Name of destination database system (%s)", types))
+	err := rootCmd.Execute()
+	if err != nil {
+		er(err)
+	}
+}
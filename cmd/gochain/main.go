@@ -45,6 +45,8 @@ type GoChainConfig struct {
 	EEInstances   int    `json:"ee_instances"`
 	Engines       string `json:"engines"`
 	WSMaxSession  int    `json:"ws_max_session"`
+	RPCRateLimit  string `json:"rpc_rate_limit,omitempty"`
+	RPCAPIKeys    string `json:"rpc_api_keys,omitempty"`
 
 	Key          []byte          `json:"key,omitempty"`
 	KeyStoreData json.RawMessage `json:"key_store"`
@@ -138,6 +140,11 @@ func main() {
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	flag.BoolVar(&cfg.ValidateTxOnSend, "validate_tx_on_send", false, "Validate transaction on send")
+	flag.BoolVar(&cfg.EventIndex, "event_index", false, "Index event logs of finalized blocks for icx_getLogs")
+	flag.Int64Var(&cfg.PruneKeepBlocks, "prune_keep_blocks", 0, "Number of recent blocks to keep world states while running (0: disable online pruning)")
+	flag.Int64Var(&cfg.PruneInterval, "prune_interval", 0, "Number of blocks between online pruning (0: uses system default value)")
+	flag.IntVar(&cfg.TxPoolSenderLimit, "tx_pool_sender_limit", 0, "Maximum number of transactions from a sender in normal transaction pool (0: uses system default value, -1: no limit)")
+	flag.IntVar(&cfg.FastSyncWindow, "fast_sync_window", 0, "Number of blocks fetched ahead in fast sync from multiple peers at the same time (0: uses system default value)")
 	cfg.ChildrenLimit = flag.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	cfg.NephewsLimit = flag.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
@@ -153,6 +160,8 @@ func main() {
 	flag.Int64Var(&cfg.TxTimeout, "tx_timeout", 0, "Transaction timeout in milli-second (0: uses system default value)")
 	flag.StringVar(&cfg.Engines, "engines", "python", "Execution engines, comma-separated (python,java)")
 	flag.IntVar(&cfg.WSMaxSession, "ws_max_session", server.DefaultWSMaxSession, "Websocket session limit (use -1 to disable)")
+	flag.StringVar(&cfg.RPCRateLimit, "rpc_rate_limit", "", "JSON-RPC rate limits per client, comma-separated 'group=rate[:burst]' (groups: icx,debug,btp,rosetta,ws)")
+	flag.StringVar(&cfg.RPCAPIKeys, "rpc_api_keys", "", "JSON-RPC API keys having own rate limits, comma-separated")
 	flag.StringVar(&lwCfg.Filename, "log_writer_filename", "", "Log filename")
 	flag.IntVar(&lwCfg.MaxSize, "log_writer_maxsize", 100, "Log file max size")
 	flag.IntVar(&lwCfg.MaxAge, "log_writer_maxage", 0, "Log file max age")
@@ -492,6 +501,8 @@ func Execute(cmd *cobra.Command, args []string) {
 		JSONRPCRosetta:      cfg.RPCRosetta,
 		JSONRPCBatchLimit:   cfg.RPCBatchLimit,
 		WSMaxSession:        cfg.WSMaxSession,
+		RateLimit:           cfg.RPCRateLimit,
+		APIKeys:             cfg.RPCAPIKeys,
 	}
 	srv := server.NewManager(config, wallet, logger)
 	hex.EncodeToString(wallet.Address().ID())
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/icsim"
+)
+
+var logLevel string
+
+func run(file string) error {
+	s, err := icsim.LoadScenario(file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Run %s\n", file)
+	return s.Run(os.Stdout)
+}
+
+func er(msg interface{}) {
+	_, _ = fmt.Fprintln(os.Stderr, "Error:", msg)
+	os.Exit(1)
+}
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   os.Args[0],
+		Short: "Simulator for ICON network",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			lv, err := log.ParseLevel(logLevel)
+			if err != nil {
+				return err
+			}
+			log.GlobalLogger().SetLevel(lv)
+			return nil
+		},
+	}
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log_level", "warn",
+		"Log level (trace, debug, info, warn, error, fatal, panic)")
+	runCmd := &cobra.Command{
+		Use:   "run SCENARIO...",
+		Short: "Run scenario files",
+		Long: "Run scenario files and check expectations in them.\n" +
+			"Refer doc/icsim_scenario.md for the format of the scenario file.",
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			failed := 0
+			for _, file := range args {
+				if err := run(file); err != nil {
+					fmt.Printf("FAIL %s: %v\n", file, err)
+					failed += 1
+				} else {
+					fmt.Printf("PASS %s\n", file)
+				}
+			}
+			if failed > 0 {
+				er(errors.Errorf("%d of %d scenarios failed", failed, len(args)))
+			}
+		},
+	}
+	rootCmd.AddCommand(runCmd)
+	err := rootCmd.Execute()
+	if err != nil {
+		er(err)
+	}
+}
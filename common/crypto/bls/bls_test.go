@@ -0,0 +1,115 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bls
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivateKey_Bytes(t *testing.T) {
+	sk, pk := GenerateKeyPair()
+	sk2, err := ParsePrivateKey(sk.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, pk.Equal(sk2.PublicKey()))
+
+	_, err = ParsePrivateKey(sk.Bytes()[1:])
+	assert.Error(t, err)
+	_, err = ParsePrivateKey(make([]byte, PrivateKeyLen))
+	assert.Error(t, err)
+}
+
+func TestPublicKey_Bytes(t *testing.T) {
+	_, pk := GenerateKeyPair()
+	bs := pk.Bytes()
+	assert.Len(t, bs, PublicKeyLen)
+
+	pk2, err := ParsePublicKey(bs)
+	assert.NoError(t, err)
+	assert.True(t, pk.Equal(pk2))
+
+	_, err = ParsePublicKey(bs[1:])
+	assert.Error(t, err)
+
+	infinity := make([]byte, PublicKeyLen)
+	infinity[0] = 0xc0
+	_, err = ParsePublicKey(infinity)
+	assert.Error(t, err)
+}
+
+func TestSignature_Verify(t *testing.T) {
+	msg := []byte("test message")
+	sk, pk := GenerateKeyPair()
+	_, pk2 := GenerateKeyPair()
+
+	sig, err := NewSignature(msg, sk)
+	assert.NoError(t, err)
+	bs := sig.Bytes()
+	assert.Len(t, bs, SignatureLen)
+
+	sig2, err := ParseSignature(bs)
+	assert.NoError(t, err)
+	assert.True(t, sig2.Verify(msg, pk))
+	assert.False(t, sig2.Verify([]byte("other message"), pk))
+	assert.False(t, sig2.Verify(msg, pk2))
+
+	_, err = ParseSignature(bs[1:])
+	assert.Error(t, err)
+}
+
+func TestSignature_VerifyProofOfPossession(t *testing.T) {
+	sk, pk := GenerateKeyPair()
+	_, pk2 := GenerateKeyPair()
+
+	pop, err := NewProofOfPossession(sk)
+	assert.NoError(t, err)
+	assert.True(t, pop.VerifyProofOfPossession(pk))
+	assert.False(t, pop.VerifyProofOfPossession(pk2))
+
+	// a signature of the public key is not a proof of possession
+	sig, err := NewSignature(pk.Bytes(), sk)
+	assert.NoError(t, err)
+	assert.False(t, sig.VerifyProofOfPossession(pk))
+}
+
+func TestSignature_FastAggregateVerify(t *testing.T) {
+	msg := []byte("test message")
+	var pks []*PublicKey
+	var sigs []*Signature
+	for i := 0; i < 4; i++ {
+		sk, pk := GenerateKeyPair()
+		sig, err := NewSignature(msg, sk)
+		assert.NoError(t, err)
+		pks = append(pks, pk)
+		sigs = append(sigs, sig)
+	}
+
+	agg, err := AggregateSignatures(sigs...)
+	assert.NoError(t, err)
+	assert.True(t, agg.FastAggregateVerify(msg, pks))
+	assert.False(t, agg.FastAggregateVerify(msg, pks[:3]))
+	assert.False(t, agg.FastAggregateVerify([]byte("other message"), pks))
+	assert.False(t, agg.FastAggregateVerify(msg, nil))
+
+	agg, err = AggregateSignatures(sigs[:3]...)
+	assert.NoError(t, err)
+	assert.True(t, agg.FastAggregateVerify(msg, pks[:3]))
+
+	_, err = AggregateSignatures()
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bls implements BLS signatures over the BLS12-381 curve.
+//
+// Public keys are points on G1 and signatures are points on G2, both in
+// compressed form. It follows the proof of possession scheme, so that
+// signatures of the same message can be aggregated safely.
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+const (
+	// PrivateKeyLen is the byte length of a private key
+	PrivateKeyLen = 32
+	// PublicKeyLen is the byte length of a compressed public key
+	PublicKeyLen = 48
+)
+
+// PrivateKey is a type representing a private key.
+type PrivateKey struct {
+	real *big.Int
+}
+
+// String returns the string representation.
+func (key *PrivateKey) String() string {
+	return "0x" + hex.EncodeToString(key.Bytes())
+}
+
+// PublicKey generates a public key paired with itself.
+func (key *PrivateKey) PublicKey() *PublicKey {
+	g1 := bls12381.NewG1()
+	p := g1.New()
+	g1.MulScalarBig(p, g1.One(), key.real)
+	return &PublicKey{real: g1.Affine(p)}
+}
+
+// Bytes returns bytes form of private key.
+func (key *PrivateKey) Bytes() []byte {
+	bs := make([]byte, PrivateKeyLen)
+	return key.real.FillBytes(bs)
+}
+
+// PublicKey is a type representing a public key.
+type PublicKey struct {
+	real *bls12381.PointG1
+}
+
+// ParsePublicKey parses the compressed public key into a PublicKey instance.
+// It rejects the point at infinity and points out of the subgroup.
+func ParsePublicKey(pubKey []byte) (*PublicKey, error) {
+	g1 := bls12381.NewG1()
+	p, err := g1.FromCompressed(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if g1.IsZero(p) {
+		return nil, errors.New("public key is infinity")
+	}
+	return &PublicKey{real: p}, nil
+}
+
+// Bytes returns the compressed form of the public key.
+func (key *PublicKey) Bytes() []byte {
+	return bls12381.NewG1().ToCompressed(key.real)
+}
+
+// Equal returns true if the given public key is same as this instance
+// semantically
+func (key *PublicKey) Equal(key2 *PublicKey) bool {
+	return bls12381.NewG1().Equal(key.real, key2.real)
+}
+
+// String returns the string representation.
+func (key *PublicKey) String() string {
+	return "0x" + hex.EncodeToString(key.Bytes())
+}
+
+// AggregatePublicKeys returns the sum of the public keys.
+func AggregatePublicKeys(keys []*PublicKey) (*PublicKey, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no public key to aggregate")
+	}
+	g1 := bls12381.NewG1()
+	p := g1.New().Set(keys[0].real)
+	for _, key := range keys[1:] {
+		g1.Add(p, p, key.real)
+	}
+	return &PublicKey{real: g1.Affine(p)}, nil
+}
+
+// GenerateKeyPair generates a private and public key pair.
+func GenerateKeyPair() (privKey *PrivateKey, pubKey *PublicKey) {
+	q := bls12381.NewG1().Q()
+	for {
+		s, err := rand.Int(rand.Reader, q)
+		if err != nil {
+			panic(err)
+		}
+		if s.Sign() != 0 {
+			privKey = &PrivateKey{real: s}
+			return privKey, privKey.PublicKey()
+		}
+	}
+}
+
+// ParsePrivateKey parses the private key into a PrivateKey instance.
+func ParsePrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeyLen {
+		return nil, errors.New("invalid private key length")
+	}
+	s := new(big.Int).SetBytes(b)
+	if s.Sign() == 0 || s.Cmp(bls12381.NewG1().Q()) >= 0 {
+		return nil, errors.New("private key out of range")
+	}
+	return &PrivateKey{real: s}, nil
+}
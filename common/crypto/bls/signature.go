@@ -0,0 +1,130 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bls
+
+import (
+	"encoding/hex"
+	"errors"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+const (
+	// SignatureLen is the byte length of a compressed signature
+	SignatureLen = 96
+)
+
+var (
+	signatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+	popDST       = []byte("BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+)
+
+// Signature is a type representing a signature or an aggregated signature.
+type Signature struct {
+	real *bls12381.PointG2
+}
+
+func sign(msg []byte, dst []byte, privKey *PrivateKey) (*Signature, error) {
+	g2 := bls12381.NewG2()
+	h, err := g2.HashToCurve(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	p := g2.New()
+	g2.MulScalarBig(p, h, privKey.real)
+	return &Signature{real: g2.Affine(p)}, nil
+}
+
+// NewSignature calculates a signature of the message with the private key.
+func NewSignature(msg []byte, privKey *PrivateKey) (*Signature, error) {
+	return sign(msg, signatureDST, privKey)
+}
+
+// NewProofOfPossession calculates the proof of possession of the private key,
+// which is the signature of the public key paired with it.
+func NewProofOfPossession(privKey *PrivateKey) (*Signature, error) {
+	return sign(privKey.PublicKey().Bytes(), popDST, privKey)
+}
+
+// ParseSignature parses the compressed signature into a Signature instance.
+func ParseSignature(sig []byte) (*Signature, error) {
+	p, err := bls12381.NewG2().FromCompressed(sig)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{real: p}, nil
+}
+
+// Bytes returns the compressed form of the signature.
+func (sig *Signature) Bytes() []byte {
+	return bls12381.NewG2().ToCompressed(sig.real)
+}
+
+// String returns the string representation.
+func (sig *Signature) String() string {
+	return "0x" + hex.EncodeToString(sig.Bytes())
+}
+
+func verify(msg []byte, dst []byte, sig *Signature, pubKey *PublicKey) bool {
+	g2 := bls12381.NewG2()
+	h, err := g2.HashToCurve(msg, dst)
+	if err != nil {
+		return false
+	}
+	if g2.IsZero(sig.real) {
+		return false
+	}
+	e := bls12381.NewEngine()
+	e.AddPair(pubKey.real, h)
+	e.AddPairInv(e.G1.One(), sig.real)
+	return e.Check()
+}
+
+// Verify verifies the signature of the message with the public key.
+func (sig *Signature) Verify(msg []byte, pubKey *PublicKey) bool {
+	return verify(msg, signatureDST, sig, pubKey)
+}
+
+// VerifyProofOfPossession verifies the signature as the proof of possession
+// of the private key paired with the public key.
+func (sig *Signature) VerifyProofOfPossession(pubKey *PublicKey) bool {
+	return verify(pubKey.Bytes(), popDST, sig, pubKey)
+}
+
+// FastAggregateVerify verifies the aggregated signature of the message with
+// the public keys of the signers. Public keys shall be verified with the
+// proof of possession in advance.
+func (sig *Signature) FastAggregateVerify(msg []byte, pubKeys []*PublicKey) bool {
+	pubKey, err := AggregatePublicKeys(pubKeys)
+	if err != nil {
+		return false
+	}
+	return sig.Verify(msg, pubKey)
+}
+
+// AggregateSignatures returns the sum of the signatures.
+func AggregateSignatures(sigs ...*Signature) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("no signature to aggregate")
+	}
+	g2 := bls12381.NewG2()
+	p := g2.New().Set(sigs[0].real)
+	for _, sig := range sigs[1:] {
+		g2.Add(p, p, sig.real)
+	}
+	return &Signature{real: g2.Affine(p)}, nil
+}
@@ -66,6 +66,10 @@ const (
 	// ListByMerkleRootBase is the base for the bucket that maps list
 	// from network type dependent merkle root(list)
 	ListByMerkleRootBase BucketID = "L"
+
+	// EventIndex maps logs bloom and posting lists of event logs
+	// from height, address or signature.
+	EventIndex BucketID = "E"
 )
 
 // internalKey returns key prefixed with the bucket's id.
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const copyBatchSize = 4 * 1024 * 1024
+
+// Copy copies all the entries of src into dst. src should implement Iterable
+// and dst should implement Batcher. onProgress is called with the number of
+// copied entries whenever a batch is written, if it's not nil.
+func Copy(dst, src Database, onProgress func(count int)) error {
+	iterable, ok := src.(Iterable)
+	if !ok {
+		return errors.UnsupportedError.Errorf("NotIterable(type=%T)", src)
+	}
+	batcher, ok := dst.(Batcher)
+	if !ok {
+		return errors.UnsupportedError.Errorf("NotBatcher(type=%T)", dst)
+	}
+	batch := batcher.NewBatch()
+	defer batch.Reset()
+
+	count := 0
+	write := func() error {
+		count += batch.Len()
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		if onProgress != nil {
+			onProgress(count)
+		}
+		return nil
+	}
+	err := iterable.Iterate(func(key, value []byte) error {
+		if err := batch.Set(key, value); err != nil {
+			return err
+		}
+		if batch.Size() >= copyBatchSize {
+			return write()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return write()
+}
@@ -17,6 +17,34 @@ type LayerDB interface {
 	Unwrap() Database
 }
 
+// Batch collects changes to write them at once. Keys are prefixed with the
+// bucket IDs.
+type Batch interface {
+	Set(key, value []byte) error
+	Delete(key []byte) error
+
+	// Len returns the number of the changes.
+	Len() int
+
+	// Size returns the size of the changes in bytes.
+	Size() int
+
+	Write() error
+	Reset()
+}
+
+// Batcher is implemented by the Database supporting Batch.
+type Batcher interface {
+	NewBatch() Batch
+}
+
+// Iterable is implemented by the Database supporting iteration over all the
+// entries regardless of buckets. Keys are prefixed with the bucket IDs.
+// Key and value are valid only in the call of fn.
+type Iterable interface {
+	Iterate(fn func(key, value []byte) error) error
+}
+
 type BackendType string
 
 type dbCreator func(name string, dir string) (Database, error)
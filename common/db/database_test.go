@@ -258,3 +258,43 @@ func TestDatabase_OperationAfterClose(t *testing.T) {
 		})
 	}
 }
+
+func TestCopy(t *testing.T) {
+	dir := t.TempDir()
+	src, err := NewGoLevelDB("src", dir)
+	assert.NoError(t, err)
+	defer src.Close()
+
+	buckets := []BucketID{MerkleTrie, BytesByHash, ChainProperty}
+	for _, id := range buckets {
+		bk, err := src.GetBucket(id)
+		assert.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, bk.Set([]byte{byte(i)}, []byte(id+"value")))
+		}
+	}
+
+	dst, err := NewPebbleDB("dst", dir)
+	assert.NoError(t, err)
+	defer dst.Close()
+
+	var progress int
+	err = Copy(dst, src, func(count int) {
+		progress = count
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(buckets)*100, progress)
+
+	for _, id := range buckets {
+		bk, err := dst.GetBucket(id)
+		assert.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			value, err := bk.Get([]byte{byte(i)})
+			assert.NoError(t, err)
+			assert.Equal(t, []byte(id+"value"), value)
+		}
+	}
+
+	err = Copy(dst, NewMapDB(), nil)
+	assert.Error(t, err)
+}
@@ -79,6 +79,76 @@ func (db *GoLevelDB) Close() error {
 	return nil
 }
 
+// Iterate calls fn for all the entries in the order of the keys.
+// Keys are prefixed with the bucket IDs.
+func (db *GoLevelDB) Iterate(fn func(key, value []byte) error) error {
+	db.lock.Lock()
+	ldb := db.db
+	db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	iter := ldb.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if err := fn(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// NewBatch returns a new Batch for writing entries atomically.
+func (db *GoLevelDB) NewBatch() Batch {
+	return &goLevelBatch{db: db}
+}
+
+//----------------------------------------
+// Batch
+
+type goLevelBatch struct {
+	db    *GoLevelDB
+	batch leveldb.Batch
+}
+
+func (b *goLevelBatch) Set(key, value []byte) error {
+	b.batch.Put(key, value)
+	return nil
+}
+
+func (b *goLevelBatch) Delete(key []byte) error {
+	b.batch.Delete(key)
+	return nil
+}
+
+func (b *goLevelBatch) Len() int {
+	return b.batch.Len()
+}
+
+func (b *goLevelBatch) Size() int {
+	return len(b.batch.Dump())
+}
+
+func (b *goLevelBatch) Write() error {
+	b.db.lock.Lock()
+	ldb := b.db.db
+	b.db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	if err := ldb.Write(&b.batch, &opt.WriteOptions{Sync: true}); err != nil {
+		return err
+	}
+	b.batch.Reset()
+	return nil
+}
+
+func (b *goLevelBatch) Reset() {
+	b.batch.Reset()
+}
+
 //----------------------------------------
 // GetBucket
 
@@ -0,0 +1,61 @@
+package db
+
+// Journal records changes of the buckets in the order of them.
+type Journal interface {
+	OnSet(id BucketID, key []byte, value []byte) error
+	OnDelete(id BucketID, key []byte) error
+}
+
+type journalBucket struct {
+	id      BucketID
+	real    Bucket
+	journal Journal
+}
+
+func (bk *journalBucket) Get(key []byte) ([]byte, error) {
+	return bk.real.Get(key)
+}
+
+func (bk *journalBucket) Has(key []byte) (bool, error) {
+	return bk.real.Has(key)
+}
+
+func (bk *journalBucket) Set(key []byte, value []byte) error {
+	if err := bk.journal.OnSet(bk.id, key, value); err != nil {
+		return err
+	}
+	return bk.real.Set(key, value)
+}
+
+func (bk *journalBucket) Delete(key []byte) error {
+	if err := bk.journal.OnDelete(bk.id, key); err != nil {
+		return err
+	}
+	return bk.real.Delete(key)
+}
+
+type journalDB struct {
+	Database
+	journal Journal
+}
+
+func (jdb *journalDB) GetBucket(id BucketID) (Bucket, error) {
+	bk, err := jdb.Database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &journalBucket{
+		id:      id,
+		real:    bk,
+		journal: jdb.journal,
+	}, nil
+}
+
+// WithJournal returns the database recording changes to the journal before
+// they are applied.
+func WithJournal(database Database, j Journal) Database {
+	return &journalDB{
+		Database: database,
+		journal:  j,
+	}
+}
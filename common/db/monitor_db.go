@@ -0,0 +1,59 @@
+package db
+
+// Monitor is notified of accesses to buckets of the database.
+type Monitor interface {
+	OnRead(id BucketID)
+	OnWrite(id BucketID)
+}
+
+type monitorBucket struct {
+	id      BucketID
+	real    Bucket
+	monitor Monitor
+}
+
+func (bk *monitorBucket) Get(key []byte) ([]byte, error) {
+	bk.monitor.OnRead(bk.id)
+	return bk.real.Get(key)
+}
+
+func (bk *monitorBucket) Has(key []byte) (bool, error) {
+	bk.monitor.OnRead(bk.id)
+	return bk.real.Has(key)
+}
+
+func (bk *monitorBucket) Set(key []byte, value []byte) error {
+	bk.monitor.OnWrite(bk.id)
+	return bk.real.Set(key, value)
+}
+
+func (bk *monitorBucket) Delete(key []byte) error {
+	bk.monitor.OnWrite(bk.id)
+	return bk.real.Delete(key)
+}
+
+type monitorDB struct {
+	Database
+	monitor Monitor
+}
+
+func (mdb *monitorDB) GetBucket(id BucketID) (Bucket, error) {
+	bk, err := mdb.Database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &monitorBucket{
+		id:      id,
+		real:    bk,
+		monitor: mdb.monitor,
+	}, nil
+}
+
+// WithMonitor returns the database notifying the monitor of reads and
+// writes of the buckets.
+func WithMonitor(database Database, m Monitor) Database {
+	return &monitorDB{
+		Database: database,
+		monitor:  m,
+	}
+}
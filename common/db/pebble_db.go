@@ -0,0 +1,278 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+)
+
+const PebbleBackend BackendType = "pebble"
+
+func init() {
+	dbCreator := func(name string, dir string) (Database, error) {
+		return NewPebbleDB(name, dir)
+	}
+	registerDBCreator(PebbleBackend, dbCreator, false)
+}
+
+func NewPebbleDB(name string, dir string) (*PebbleDB, error) {
+	return NewPebbleDBWithOpts(name, dir, nil)
+}
+
+func NewPebbleDBWithOpts(name string, dir string, o *pebble.Options) (*PebbleDB, error) {
+	dbPath := filepath.Join(dir, name)
+	db, err := pebble.Open(dbPath, o)
+	if err != nil {
+		return nil, err
+	}
+	database := &PebbleDB{
+		db:      db,
+		buckets: make(map[BucketID]Bucket),
+	}
+	return database, nil
+}
+
+//----------------------------------------
+// Database
+
+var _ Database = (*PebbleDB)(nil)
+
+type PebbleDB struct {
+	lock    sync.RWMutex
+	db      *pebble.DB
+	buckets map[BucketID]Bucket
+}
+
+func (db *PebbleDB) GetBucket(id BucketID) (Bucket, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return nil, pebble.ErrClosed
+	}
+
+	if bk, ok := db.buckets[id]; ok {
+		return bk, nil
+	} else {
+		bk = &pebbleBucket{
+			id: id,
+			db: db,
+		}
+		db.buckets[id] = bk
+		return bk, nil
+	}
+}
+
+func (db *PebbleDB) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	if err := db.db.Close(); err != nil {
+		return err
+	}
+	db.db = nil
+	return nil
+}
+
+func (db *PebbleDB) getValue(k []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, pebble.ErrClosed
+	}
+	value, closer, err := db.db.Get(k)
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return append([]byte{}, value...), nil
+}
+
+func (db *PebbleDB) hasValue(k []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return false, pebble.ErrClosed
+	}
+	_, closer, err := db.db.Get(k)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, closer.Close()
+}
+
+func (db *PebbleDB) setValue(k, v []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	return db.db.Set(k, v, pebble.NoSync)
+}
+
+func (db *PebbleDB) deleteValue(k []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	return db.db.Delete(k, pebble.NoSync)
+}
+
+// Iterate calls fn for all the entries in the order of the keys.
+// Keys are prefixed with the bucket IDs.
+func (db *PebbleDB) Iterate(fn func(key, value []byte) error) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return pebble.ErrClosed
+	}
+	iter := db.db.NewIter(nil)
+	for iter.First(); iter.Valid(); iter.Next() {
+		if err := fn(iter.Key(), iter.Value()); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+	return iter.Close()
+}
+
+// NewBatch returns a new Batch for writing entries atomically.
+func (db *PebbleDB) NewBatch() Batch {
+	return &pebbleBatch{db: db}
+}
+
+//----------------------------------------
+// Batch
+
+type pebbleBatch struct {
+	db    *PebbleDB
+	batch *pebble.Batch
+	count int
+}
+
+func (b *pebbleBatch) ensureBatch() (*pebble.Batch, error) {
+	if b.batch == nil {
+		b.db.lock.RLock()
+		defer b.db.lock.RUnlock()
+		if b.db.db == nil {
+			return nil, pebble.ErrClosed
+		}
+		b.batch = b.db.db.NewBatch()
+	}
+	return b.batch, nil
+}
+
+func (b *pebbleBatch) Set(key, value []byte) error {
+	batch, err := b.ensureBatch()
+	if err != nil {
+		return err
+	}
+	b.count += 1
+	return batch.Set(key, value, nil)
+}
+
+func (b *pebbleBatch) Delete(key []byte) error {
+	batch, err := b.ensureBatch()
+	if err != nil {
+		return err
+	}
+	b.count += 1
+	return batch.Delete(key, nil)
+}
+
+func (b *pebbleBatch) Len() int {
+	return b.count
+}
+
+func (b *pebbleBatch) Size() int {
+	if b.batch == nil {
+		return 0
+	}
+	return b.batch.Len()
+}
+
+func (b *pebbleBatch) Write() error {
+	if b.batch == nil {
+		return nil
+	}
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return pebble.ErrClosed
+	}
+	if err := b.batch.Commit(pebble.Sync); err != nil {
+		return err
+	}
+	return b.reset()
+}
+
+func (b *pebbleBatch) Reset() {
+	_ = b.reset()
+}
+
+func (b *pebbleBatch) reset() error {
+	b.count = 0
+	if b.batch == nil {
+		return nil
+	}
+	err := b.batch.Close()
+	b.batch = nil
+	return err
+}
+
+//----------------------------------------
+// Bucket
+
+var _ Bucket = (*pebbleBucket)(nil)
+
+type pebbleBucket struct {
+	id BucketID
+	db *PebbleDB
+}
+
+func (bucket *pebbleBucket) Get(key []byte) ([]byte, error) {
+	return bucket.db.getValue(internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) Has(key []byte) (bool, error) {
+	return bucket.db.hasValue(internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) Set(key []byte, value []byte) error {
+	return bucket.db.setValue(internalKey(bucket.id, key), value)
+}
+
+func (bucket *pebbleBucket) Delete(key []byte) error {
+	return bucket.db.deleteValue(internalKey(bucket.id, key))
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ompt
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// cursor points a position in the trie. For extensions and leaves, off is
+// the number of nibbles of the keys already consumed.
+type cursor struct {
+	n   node
+	off int
+}
+
+type diffWalker struct {
+	bucket  db.Bucket
+	partial bool
+	onNode  func(h []byte) error
+	onValue func(v, bv []byte) error
+}
+
+func (w *diffWalker) load(h []byte) (node, error) {
+	bs, err := w.bucket.Get(h)
+	if err != nil {
+		return nil, err
+	}
+	if bs == nil {
+		return nil, errors.NotFoundError.Errorf("NoNode(hash=%#x)", h)
+	}
+	return deserialize(h, bs, stateFlushed)
+}
+
+// skip moves the cursor at the end of the extension to its next node.
+func (c cursor) skip() cursor {
+	for {
+		if e, ok := c.n.(*extension); ok && c.off == len(e.keys) {
+			c = cursor{e.next, 0}
+			continue
+		}
+		return c
+	}
+}
+
+func (c cursor) child(nib byte) cursor {
+	switch n := c.n.(type) {
+	case *branch:
+		return cursor{n.children[nib], 0}
+	case *extension:
+		if c.off < len(n.keys) && n.keys[c.off] == nib {
+			return cursor{n, c.off + 1}
+		}
+	case *leaf:
+		if c.off < len(n.keys) && n.keys[c.off] == nib {
+			return cursor{n, c.off + 1}
+		}
+	}
+	return cursor{}
+}
+
+func (c cursor) value() []byte {
+	switch n := c.n.(type) {
+	case *branch:
+		if n.value != nil {
+			return n.value.Bytes()
+		}
+	case *leaf:
+		if c.off == len(n.keys) {
+			return n.value.Bytes()
+		}
+	}
+	return nil
+}
+
+func (w *diffWalker) walkValue(v, bv []byte) error {
+	if w.onValue == nil || bytes.Equal(v, bv) {
+		return nil
+	}
+	return w.onValue(v, bv)
+}
+
+func (w *diffWalker) walk(a, b cursor) error {
+	if a.n == nil {
+		return nil
+	}
+	b = b.skip()
+	if ah, ok := a.n.(*hash); ok {
+		if bh, ok := b.n.(*hash); ok && bytes.Equal(ah.value, bh.value) {
+			return nil
+		}
+		n, err := w.load(ah.value)
+		if err != nil {
+			if w.partial && errors.NotFoundError.Equals(err) {
+				return nil
+			}
+			return err
+		}
+		if err := w.onNode(ah.value); err != nil {
+			return err
+		}
+		a.n = n
+	}
+	if bh, ok := b.n.(*hash); ok {
+		n, err := w.load(bh.value)
+		if err != nil {
+			if !w.partial || !errors.NotFoundError.Equals(err) {
+				return err
+			}
+		}
+		b.n = n
+	}
+
+	switch n := a.n.(type) {
+	case *extension:
+		if a.off == len(n.keys) {
+			return w.walk(cursor{n.next, 0}, b)
+		}
+		return w.walk(cursor{n, a.off + 1}, b.child(n.keys[a.off]))
+	case *leaf:
+		if a.off == len(n.keys) {
+			return w.walkValue(n.value.Bytes(), b.value())
+		}
+		return w.walk(cursor{n, a.off + 1}, b.child(n.keys[a.off]))
+	case *branch:
+		if n.value != nil {
+			if err := w.walkValue(n.value.Bytes(), b.value()); err != nil {
+				return err
+			}
+		}
+		for i, child := range n.children {
+			if child == nil {
+				continue
+			}
+			if err := w.walk(cursor{child, 0}, b.child(byte(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.InvalidStateError.Errorf("UnknownNode(type=%T)", a.n)
+	}
+}
+
+// WalkDiff visits nodes of the trie for root, which don't exist at the same
+// position of the trie for base. base can be nil to visit all the nodes.
+// onNode is called with the hash of the node stored in the bucket after it's
+// loaded. onValue is called with the value and the value of base at the
+// same key (nil if it doesn't exist) if they are different.
+func WalkDiff(bk db.Bucket, root, base []byte,
+	onNode func(h []byte) error, onValue func(v, bv []byte) error,
+) error {
+	return walkDiff(bk, false, root, base, onNode, onValue)
+}
+
+// WalkDiffPartial is same as WalkDiff except that it allows missing nodes
+// of the tries being pruned. Subtrees of missing nodes are skipped, and
+// missing nodes of base are regarded as empty.
+func WalkDiffPartial(bk db.Bucket, root, base []byte,
+	onNode func(h []byte) error, onValue func(v, bv []byte) error,
+) error {
+	return walkDiff(bk, true, root, base, onNode, onValue)
+}
+
+func walkDiff(bk db.Bucket, partial bool, root, base []byte,
+	onNode func(h []byte) error, onValue func(v, bv []byte) error,
+) error {
+	w := &diffWalker{
+		bucket:  bk,
+		partial: partial,
+		onNode:  onNode,
+		onValue: onValue,
+	}
+	return w.walk(cursor{nodeFromHash(root), 0}, cursor{nodeFromHash(base), 0})
+}
@@ -0,0 +1,90 @@
+package ompt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func collectNodes(t *testing.T, bk db.Bucket, root, base []byte) (map[string]bool, map[string]string) {
+	nodes := make(map[string]bool)
+	values := make(map[string]string)
+	err := WalkDiff(bk, root, base, func(h []byte) error {
+		nodes[string(h)] = true
+		return nil
+	}, func(v, bv []byte) error {
+		values[string(v)] = string(bv)
+		return nil
+	})
+	assert.NoError(t, err)
+	return nodes, values
+}
+
+func TestWalkDiff(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, err := dbase.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+
+	m1 := NewMPTForBytes(dbase, nil)
+	for i := 0; i < 1000; i++ {
+		_, err := m1.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	s1 := m1.GetSnapshot()
+	assert.NoError(t, s1.Flush())
+	h1 := s1.Hash()
+
+	m2 := NewMPTForBytes(dbase, h1)
+	for i := 0; i < 1000; i += 97 {
+		_, err := m2.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("new%d", i)))
+		assert.NoError(t, err)
+	}
+	for i := 1000; i < 1010; i++ {
+		_, err := m2.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	for i := 1; i < 1000; i += 131 {
+		_, err := m2.Delete([]byte(fmt.Sprintf("key%d", i)))
+		assert.NoError(t, err)
+	}
+	s2 := m2.GetSnapshot()
+	assert.NoError(t, s2.Flush())
+	h2 := s2.Hash()
+
+	all1, values1 := collectNodes(t, bk, h1, nil)
+	all2, _ := collectNodes(t, bk, h2, nil)
+	assert.Len(t, values1, 1000)
+
+	// all the nodes only in the new trie should be visited
+	diff, values := collectNodes(t, bk, h2, h1)
+	for h := range all2 {
+		if !all1[h] {
+			assert.True(t, diff[h], "missing node %x", h)
+		}
+	}
+	for h := range diff {
+		assert.True(t, all2[h])
+	}
+	assert.Less(t, len(diff), len(all2))
+
+	assert.Equal(t, "value97", values["new97"])
+	assert.Equal(t, "", values["value1005"])
+	_, ok := values["value2"]
+	assert.False(t, ok)
+
+	// and vice versa
+	diff, _ = collectNodes(t, bk, h1, h2)
+	for h := range all1 {
+		if !all2[h] {
+			assert.True(t, diff[h], "missing node %x", h)
+		}
+	}
+
+	// same trie
+	diff, values = collectNodes(t, bk, h1, h1)
+	assert.Empty(t, diff)
+	assert.Empty(t, values)
+}
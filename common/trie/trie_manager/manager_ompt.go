@@ -40,3 +40,11 @@ func SetCacheOfMutable(mutable trie.Mutable, cache *cache.NodeCache) {
 func SetCacheOfMutableForObject(mutable trie.MutableForObject, cache *cache.NodeCache) {
 	ompt.SetCacheOfMutableForObject(mutable, cache)
 }
+
+func WalkDiff(bk db.Bucket, root, base []byte, onNode func(h []byte) error, onValue func(v, bv []byte) error) error {
+	return ompt.WalkDiff(bk, root, base, onNode, onValue)
+}
+
+func WalkDiffPartial(bk db.Bucket, root, base []byte, onNode func(h []byte) error, onValue func(v, bv []byte) error) error {
+	return ompt.WalkDiffPartial(bk, root, base, onNode, onValue)
+}
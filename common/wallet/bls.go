@@ -0,0 +1,55 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"github.com/icon-project/goloop/common/crypto/bls"
+	"github.com/icon-project/goloop/module"
+)
+
+type blsWallet struct {
+	skey *bls.PrivateKey
+	pkey *bls.PublicKey
+}
+
+func (w *blsWallet) Sign(data []byte) ([]byte, error) {
+	sig, err := bls.NewSignature(data, w.skey)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Bytes(), nil
+}
+
+func (w *blsWallet) PublicKey() []byte {
+	return w.pkey.Bytes()
+}
+
+// NewBLS returns a new wallet signing with a BLS12-381 key.
+func NewBLS() module.BaseWallet {
+	sk, pk := bls.GenerateKeyPair()
+	return &blsWallet{
+		skey: sk,
+		pkey: pk,
+	}
+}
+
+func NewBLSFromPrivateKey(sk *bls.PrivateKey) module.BaseWallet {
+	return &blsWallet{
+		skey: sk,
+		pkey: sk.PublicKey(),
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto/bls"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DSASecp256k1 = "ecdsa/secp256k1"
+	DSABLS12381  = "bls/bls12-381"
+	DSAEd25519   = "eddsa/ed25519"
+)
+
+// DSAKeyStoreData is a key store for the key of a DSA used by BTP network
+// types. The key of DSASecp256k1 is stored with KeyStoreData.
+type DSAKeyStoreData struct {
+	DSA       string          `json:"dsa"`
+	PublicKey common.HexBytes `json:"publicKey"`
+	ID        string          `json:"id"`
+	Version   int             `json:"version"`
+	Crypto    CryptoData      `json:"crypto"`
+}
+
+func EncryptDSAKeyAsKeyStore(dsa string, secret []byte, pw []byte) ([]byte, error) {
+	w, err := NewDSAWalletFromSecret(dsa, secret)
+	if err != nil {
+		return nil, err
+	}
+	cd, err := encryptSecret(secret, pw)
+	if err != nil {
+		return nil, err
+	}
+	ks := DSAKeyStoreData{
+		DSA:       dsa,
+		PublicKey: w.PublicKey(),
+		ID:        uuid.Must(uuid.NewV4()).String(),
+		Version:   3,
+		Crypto:    *cd,
+	}
+	return json.Marshal(&ks)
+}
+
+// DecryptDSAKeyStore returns the DSA and the secret in the key store.
+func DecryptDSAKeyStore(data, pw []byte) (string, []byte, error) {
+	var ksData DSAKeyStoreData
+	if err := json.Unmarshal(data, &ksData); err != nil {
+		return "", nil, err
+	}
+	if ksData.DSA == "" {
+		return "", nil, errors.New("NoDSAInKeyStore")
+	}
+	secret, err := decryptSecret(&ksData.Crypto, pw)
+	if err != nil {
+		return "", nil, err
+	}
+	return ksData.DSA, secret, nil
+}
+
+// IsDSAKeyStore returns whether the data is a key store made by
+// EncryptDSAKeyAsKeyStore.
+func IsDSAKeyStore(data []byte) bool {
+	var ksData DSAKeyStoreData
+	if err := json.Unmarshal(data, &ksData); err != nil {
+		return false
+	}
+	return ksData.DSA != ""
+}
+
+func NewDSAWalletFromSecret(dsa string, secret []byte) (module.BaseWallet, error) {
+	switch dsa {
+	case DSABLS12381:
+		sk, err := bls.ParsePrivateKey(secret)
+		if err != nil {
+			return nil, err
+		}
+		return NewBLSFromPrivateKey(sk), nil
+	case DSAEd25519:
+		if len(secret) != ed25519.SeedSize {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidSeedSize(size=%d)", len(secret))
+		}
+		return NewEd25519FromPrivateKey(ed25519.NewKeyFromSeed(secret)), nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("UnsupportedDSA(dsa=%s)", dsa)
+	}
+}
+
+func NewDSAWalletFromKeyStore(data, pw []byte) (string, module.BaseWallet, error) {
+	dsa, secret, err := DecryptDSAKeyStore(data, pw)
+	if err != nil {
+		return "", nil, err
+	}
+	w, err := NewDSAWalletFromSecret(dsa, secret)
+	if err != nil {
+		return "", nil, err
+	}
+	return dsa, w, nil
+}
+
+// NewDSASecret returns a new secret for the DSA.
+func NewDSASecret(dsa string) ([]byte, error) {
+	switch dsa {
+	case DSABLS12381:
+		sk, _ := bls.GenerateKeyPair()
+		return sk.Bytes(), nil
+	case DSAEd25519:
+		_, sk, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return sk.Seed(), nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("UnsupportedDSA(dsa=%s)", dsa)
+	}
+}
+
+type dsaWallets struct {
+	module.Wallet
+	wallets map[string]module.BaseWallet
+}
+
+func (w *dsaWallets) WalletFor(dsa string) module.BaseWallet {
+	if dsa == DSASecp256k1 {
+		return w.Wallet
+	}
+	if bw, ok := w.wallets[dsa]; ok {
+		return bw
+	}
+	return nil
+}
+
+// WithDSAWallets returns the wallet which also works as module.WalletProvider
+// for wallets of other DSAs.
+func WithDSAWallets(w module.Wallet, wallets map[string]module.BaseWallet) module.Wallet {
+	return &dsaWallets{
+		Wallet:  w,
+		wallets: wallets,
+	}
+}
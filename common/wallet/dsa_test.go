@@ -0,0 +1,75 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestDSAKeyStore_Basic(t *testing.T) {
+	pw := []byte("password")
+	for _, name := range []string{DSABLS12381, DSAEd25519} {
+		secret, err := NewDSASecret(name)
+		assert.NoError(t, err)
+		ks, err := EncryptDSAKeyAsKeyStore(name, secret, pw)
+		assert.NoError(t, err)
+		assert.True(t, IsDSAKeyStore(ks))
+
+		_, _, err = NewDSAWalletFromKeyStore(ks, []byte("invalid"))
+		assert.Error(t, err)
+
+		dsa, bw, err := NewDSAWalletFromKeyStore(ks, pw)
+		assert.NoError(t, err)
+		assert.Equal(t, name, dsa)
+		bw2, err := NewDSAWalletFromSecret(dsa, secret)
+		assert.NoError(t, err)
+		assert.Equal(t, bw2.PublicKey(), bw.PublicKey())
+
+		sig, err := bw.Sign([]byte("data"))
+		assert.NoError(t, err)
+		assert.NotEmpty(t, sig)
+	}
+
+	w := New()
+	pks, err := KeyStoreFromWallet(w, pw)
+	assert.NoError(t, err)
+	assert.False(t, IsDSAKeyStore(pks))
+
+	_, err = NewDSASecret("unknown")
+	assert.Error(t, err)
+}
+
+func TestWithDSAWallets(t *testing.T) {
+	secret, err := NewDSASecret(DSABLS12381)
+	assert.NoError(t, err)
+	bw, err := NewDSAWalletFromSecret(DSABLS12381, secret)
+	assert.NoError(t, err)
+
+	w := New()
+	dw := WithDSAWallets(w, map[string]module.BaseWallet{DSABLS12381: bw})
+	assert.True(t, w.Address().Equal(dw.Address()))
+
+	wp, ok := dw.(module.WalletProvider)
+	assert.True(t, ok)
+	assert.Equal(t, w.PublicKey(), wp.WalletFor(DSASecp256k1).PublicKey())
+	assert.Equal(t, bw.PublicKey(), wp.WalletFor(DSABLS12381).PublicKey())
+	assert.Nil(t, wp.WalletFor("unknown"))
+}
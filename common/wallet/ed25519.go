@@ -0,0 +1,49 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+
+	"github.com/icon-project/goloop/module"
+)
+
+type ed25519Wallet struct {
+	skey ed25519.PrivateKey
+}
+
+func (w *ed25519Wallet) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(w.skey, data), nil
+}
+
+func (w *ed25519Wallet) PublicKey() []byte {
+	return w.skey.Public().(ed25519.PublicKey)
+}
+
+// NewEd25519 returns a new wallet signing with an ed25519 key.
+func NewEd25519() module.BaseWallet {
+	_, sk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return &ed25519Wallet{skey: sk}
+}
+
+func NewEd25519FromPrivateKey(sk ed25519.PrivateKey) module.BaseWallet {
+	return &ed25519Wallet{skey: sk}
+}
@@ -79,8 +79,8 @@ func SHA3SumKeccak256(data ...[]byte) []byte {
 	return s.Sum([]byte{})
 }
 
-func EncryptKeyAsKeyStore(s *crypto.PrivateKey, pw []byte) ([]byte, error) {
-	var ks KeyStoreData
+func encryptSecret(secret, pw []byte) (*CryptoData, error) {
+	var cd CryptoData
 	var c AES128CTRParams
 	var k ScryptParams
 
@@ -91,8 +91,8 @@ func EncryptKeyAsKeyStore(s *crypto.PrivateKey, pw []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	ks.Crypto.KDF = kdfScrypt
-	ks.Crypto.KDFParams, err = json.Marshal(&k)
+	cd.KDF = kdfScrypt
+	cd.KDFParams, err = json.Marshal(&k)
 	if err != nil {
 		return nil, err
 	}
@@ -106,53 +106,35 @@ func EncryptKeyAsKeyStore(s *crypto.PrivateKey, pw []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	secret := s.Bytes()
 	cipherText := make([]byte, len(secret))
 	enc := cipher.NewCTR(b, c.IV)
 	enc.XORKeyStream(cipherText, secret)
 
-	ks.Crypto.Cipher = cipherAES128CTR
-	ks.Crypto.CipherParams, err = json.Marshal(&c)
+	cd.Cipher = cipherAES128CTR
+	cd.CipherParams, err = json.Marshal(&c)
 	if err != nil {
 		return nil, err
 	}
-	ks.Crypto.CipherText = cipherText
-	ks.Crypto.MAC = SHA3SumKeccak256(key[16:32], cipherText)
-	ks.Version = 3
-	ks.CoinType = coinTypeICON
-	ks.ID = uuid.Must(uuid.NewV4()).String()
-	if addr := common.NewAccountAddressFromPublicKey(s.PublicKey()); addr == nil {
-		return nil, errors.New("FailToMakeAddressForTheKey")
-	} else {
-		ks.Address.Set(addr)
-	}
-
-	return json.Marshal(&ks)
+	cd.CipherText = cipherText
+	cd.MAC = SHA3SumKeccak256(key[16:32], cipherText)
+	return &cd, nil
 }
 
-func DecryptKeyStore(data, pw []byte) (*crypto.PrivateKey, error) {
-	var ksData KeyStoreData
-	if err := json.Unmarshal(data, &ksData); err != nil {
-		return nil, err
-	}
-	if ksData.CoinType != coinTypeICON {
-		return nil, errors.Errorf("InvalidCoinType(coin=%s)", ksData.CoinType)
-	}
-
-	if ksData.Crypto.Cipher != cipherAES128CTR {
+func decryptSecret(cd *CryptoData, pw []byte) ([]byte, error) {
+	if cd.Cipher != cipherAES128CTR {
 		return nil, errors.Errorf("UnsupportedCipher(cipher=%s)",
-			ksData.Crypto.Cipher)
+			cd.Cipher)
 	}
 	var cipherParams AES128CTRParams
-	if err := json.Unmarshal(ksData.Crypto.CipherParams, &cipherParams); err != nil {
+	if err := json.Unmarshal(cd.CipherParams, &cipherParams); err != nil {
 		return nil, err
 	}
 
-	if ksData.Crypto.KDF != kdfScrypt {
-		return nil, errors.Errorf("UnsupportedKDF(kdf=%s)", ksData.Crypto.KDF)
+	if cd.KDF != kdfScrypt {
+		return nil, errors.Errorf("UnsupportedKDF(kdf=%s)", cd.KDF)
 	}
 	var kdfParams ScryptParams
-	if err := json.Unmarshal(ksData.Crypto.KDFParams, &kdfParams); err != nil {
+	if err := json.Unmarshal(cd.KDFParams, &kdfParams); err != nil {
 		return nil, err
 	}
 
@@ -161,13 +143,13 @@ func DecryptKeyStore(data, pw []byte) (*crypto.PrivateKey, error) {
 		return nil, err
 	}
 
-	cipheredBytes := ksData.Crypto.CipherText.Bytes()
+	cipheredBytes := cd.CipherText.Bytes()
 
 	s := sha3.NewLegacyKeccak256()
 	s.Write(key[16:32])
 	s.Write(cipheredBytes)
 	mac := s.Sum([]byte{})
-	if !bytes.Equal(mac, ksData.Crypto.MAC.Bytes()) {
+	if !bytes.Equal(mac, cd.MAC.Bytes()) {
 		return nil, errors.Errorf("InvalidPassword")
 	}
 
@@ -180,6 +162,42 @@ func DecryptKeyStore(data, pw []byte) (*crypto.PrivateKey, error) {
 
 	stream := cipher.NewCTR(block, cipherParams.IV.Bytes())
 	stream.XORKeyStream(secretBytes, cipheredBytes)
+	return secretBytes, nil
+}
+
+func EncryptKeyAsKeyStore(s *crypto.PrivateKey, pw []byte) ([]byte, error) {
+	var ks KeyStoreData
+
+	cd, err := encryptSecret(s.Bytes(), pw)
+	if err != nil {
+		return nil, err
+	}
+	ks.Crypto = *cd
+	ks.Version = 3
+	ks.CoinType = coinTypeICON
+	ks.ID = uuid.Must(uuid.NewV4()).String()
+	if addr := common.NewAccountAddressFromPublicKey(s.PublicKey()); addr == nil {
+		return nil, errors.New("FailToMakeAddressForTheKey")
+	} else {
+		ks.Address.Set(addr)
+	}
+
+	return json.Marshal(&ks)
+}
+
+func DecryptKeyStore(data, pw []byte) (*crypto.PrivateKey, error) {
+	var ksData KeyStoreData
+	if err := json.Unmarshal(data, &ksData); err != nil {
+		return nil, err
+	}
+	if ksData.CoinType != coinTypeICON {
+		return nil, errors.Errorf("InvalidCoinType(coin=%s)", ksData.CoinType)
+	}
+
+	secretBytes, err := decryptSecret(&ksData.Crypto, pw)
+	if err != nil {
+		return nil, err
+	}
 
 	secret, err := crypto.ParsePrivateKey(secretBytes)
 	if err != nil {
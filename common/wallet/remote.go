@@ -0,0 +1,332 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// Remote signer protocol
+//
+// A client connects to the signer with TLS requiring certificates of both
+// sides, then sends requests and receives responses in order on the
+// connection. Each message is encoded with codec.BC, and prefixed with its
+// length as 4 bytes big-endian integer.
+const (
+	RemoteMethodPublicKey = "publicKey"
+	RemoteMethodSign      = "sign"
+
+	// Options for OpenRemote and NewRemoteSignerTLSConfig
+	RemoteOptionCert       = "cert"
+	RemoteOptionKey        = "key"
+	RemoteOptionCA         = "ca"
+	RemoteOptionServerName = "server_name"
+	RemoteOptionTimeout    = "timeout"
+
+	remoteDefaultTimeout = 5 * time.Second
+	remoteMaxMessageSize = 1024 * 64
+)
+
+type remoteRequest struct {
+	Method string
+	Data   []byte
+}
+
+type remoteResponse struct {
+	Error string
+	Data  []byte
+}
+
+func writeRemoteMessage(w io.Writer, v interface{}) error {
+	bs, err := codec.BC.MarshalToBytes(v)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 4+len(bs))
+	binary.BigEndian.PutUint32(buf, uint32(len(bs)))
+	copy(buf[4:], bs)
+	_, err = w.Write(buf)
+	return err
+}
+
+func readRemoteMessage(r io.Reader, v interface{}) error {
+	var sz [4]byte
+	if _, err := io.ReadFull(r, sz[:]); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(sz[:])
+	if size > remoteMaxMessageSize {
+		return errors.IllegalArgumentError.Errorf("TooLargeMessage(size=%d)", size)
+	}
+	bs := make([]byte, size)
+	if _, err := io.ReadFull(r, bs); err != nil {
+		return err
+	}
+	_, err := codec.BC.UnmarshalFromBytes(bs, v)
+	return err
+}
+
+// ParseRemoteAddress returns network and address for the signer address.
+// The address is one of "unix://PATH", "tcp://HOST:PORT" and "HOST:PORT".
+func ParseRemoteAddress(addr string) (string, string) {
+	if strings.HasPrefix(addr, "unix://") {
+		return "unix", strings.TrimPrefix(addr, "unix://")
+	}
+	return "tcp", strings.TrimPrefix(addr, "tcp://")
+}
+
+// NewRemoteSignerTLSConfig returns TLS configuration with the certificate
+// and the private key of the side, and the CA certificate to verify the
+// other side. Set server true for the signer.
+func NewRemoteSignerTLSConfig(opts map[string]string, server bool) (*tls.Config, error) {
+	certFile, keyFile, caFile := opts[RemoteOptionCert], opts[RemoteOptionKey], opts[RemoteOptionCA]
+	if certFile == "" || keyFile == "" || caFile == "" {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"NoCertificates(%s=%q,%s=%q,%s=%q)",
+			RemoteOptionCert, certFile, RemoteOptionKey, keyFile, RemoteOptionCA, caFile)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load key pair cert=%s key=%s", certFile, keyFile)
+	}
+	caPEM, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to read CA file=%s", caFile)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidCACertificate(file=%s)", caFile)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if server {
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		cfg.RootCAs = pool
+		cfg.ServerName = opts[RemoteOptionServerName]
+	}
+	return cfg, nil
+}
+
+type remoteWallet struct {
+	network string
+	address string
+	config  *tls.Config
+	timeout time.Duration
+
+	lock sync.Mutex
+	conn net.Conn
+
+	pubKey []byte
+	addr   module.Address
+}
+
+func (w *remoteWallet) connect() (net.Conn, error) {
+	if w.conn != nil {
+		return w.conn, nil
+	}
+	d := &net.Dialer{Timeout: w.timeout}
+	conn, err := tls.DialWithDialer(d, w.network, w.address, w.config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to connect signer address=%s", w.address)
+	}
+	w.conn = conn
+	return conn, nil
+}
+
+func (w *remoteWallet) call(method string, data []byte) ([]byte, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	conn, err := w.connect()
+	if err != nil {
+		return nil, err
+	}
+	res, err := w.exchange(conn, &remoteRequest{Method: method, Data: data})
+	if err != nil {
+		// connection may be in unknown state, so make a new one next time.
+		log.Warnf("RemoteWallet: fail to call method=%s err=%+v", method, err)
+		_ = conn.Close()
+		w.conn = nil
+		return nil, err
+	}
+	if len(res.Error) > 0 {
+		return nil, errors.Errorf("RemoteSignerError(method=%s,msg=%s)", method, res.Error)
+	}
+	return res.Data, nil
+}
+
+func (w *remoteWallet) exchange(conn net.Conn, req *remoteRequest) (*remoteResponse, error) {
+	if err := conn.SetDeadline(time.Now().Add(w.timeout)); err != nil {
+		return nil, err
+	}
+	if err := writeRemoteMessage(conn, req); err != nil {
+		return nil, err
+	}
+	res := new(remoteResponse)
+	if err := readRemoteMessage(conn, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (w *remoteWallet) Address() module.Address {
+	return w.addr
+}
+
+func (w *remoteWallet) Sign(data []byte) ([]byte, error) {
+	sig, err := w.call(RemoteMethodSign, data)
+	if err != nil {
+		return nil, err
+	}
+	// verify the signature to protect from misconfigured signer.
+	s, err := crypto.ParseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := crypto.ParsePublicKey(w.pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Verify(data, pk) {
+		return nil, errors.InvalidStateError.New("InvalidSignatureFromSigner")
+	}
+	return sig, nil
+}
+
+func (w *remoteWallet) PublicKey() []byte {
+	return w.pubKey
+}
+
+// OpenRemote returns a wallet signing with the remote signer at the address.
+// Private key is kept by the signer, and the wallet only sends the data to
+// be signed. Options are for NewRemoteSignerTLSConfig and RemoteOptionTimeout.
+func OpenRemote(addr string, opts map[string]string) (module.Wallet, error) {
+	cfg, err := NewRemoteSignerTLSConfig(opts, false)
+	if err != nil {
+		return nil, err
+	}
+	timeout := remoteDefaultTimeout
+	if s, ok := opts[RemoteOptionTimeout]; ok {
+		if timeout, err = time.ParseDuration(s); err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidTimeout(timeout=%s)", s)
+		}
+	}
+	network, address := ParseRemoteAddress(addr)
+	if network == "tcp" && cfg.ServerName == "" {
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			cfg.ServerName = host
+		}
+	}
+	w := &remoteWallet{
+		network: network,
+		address: address,
+		config:  cfg,
+		timeout: timeout,
+	}
+	pkBytes, err := w.call(RemoteMethodPublicKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := crypto.ParsePublicKey(pkBytes)
+	if err != nil {
+		return nil, err
+	}
+	w.pubKey = pk.SerializeCompressed()
+	w.addr = common.NewAccountAddressFromPublicKey(pk)
+	return w, nil
+}
+
+// RemoteSigner serves the remote signer protocol with the wallet. It's for
+// the signer daemons implemented in Go.
+type RemoteSigner struct {
+	w      module.BaseWallet
+	config *tls.Config
+	logger log.Logger
+}
+
+func NewRemoteSigner(w module.BaseWallet, config *tls.Config, logger log.Logger) *RemoteSigner {
+	if logger == nil {
+		logger = log.GlobalLogger()
+	}
+	return &RemoteSigner{w: w, config: config, logger: logger}
+}
+
+// Serve accepts connections from the listener and handles requests until
+// the listener is closed.
+func (s *RemoteSigner) Serve(l net.Listener) error {
+	tl := tls.NewListener(l, s.config)
+	for {
+		conn, err := tl.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *RemoteSigner) handle(conn net.Conn) {
+	defer func() {
+		_ = conn.Close()
+	}()
+	for {
+		req := new(remoteRequest)
+		if err := readRemoteMessage(conn, req); err != nil {
+			if err != io.EOF {
+				s.logger.Warnf("RemoteSigner: fail to read request from=%s err=%+v",
+					conn.RemoteAddr(), err)
+			}
+			return
+		}
+		res := new(remoteResponse)
+		switch req.Method {
+		case RemoteMethodPublicKey:
+			res.Data = s.w.PublicKey()
+		case RemoteMethodSign:
+			if sig, err := s.w.Sign(req.Data); err != nil {
+				res.Error = err.Error()
+			} else {
+				res.Data = sig
+			}
+		default:
+			res.Error = "UnknownMethod(" + req.Method + ")"
+		}
+		if err := writeRemoteMessage(conn, res); err != nil {
+			s.logger.Warnf("RemoteSigner: fail to write response to=%s err=%+v",
+				conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
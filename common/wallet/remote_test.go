@@ -0,0 +1,136 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{
+			x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth,
+		},
+		DNSNames:    []string{name},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	} else {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCert{cert, key}
+}
+
+func (c *testCert) write(t *testing.T, dir, name string) map[string]string {
+	certFile := path.Join(dir, name+".crt")
+	keyFile := path.Join(dir, name+".key")
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return map[string]string{
+		RemoteOptionCert: certFile,
+		RemoteOptionKey:  keyFile,
+	}
+}
+
+func TestRemoteWallet_Basic(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "ca", nil)
+	caOpts := ca.write(t, dir, "ca")
+
+	serverOpts := newTestCert(t, "localhost", ca).write(t, dir, "server")
+	serverOpts[RemoteOptionCA] = caOpts[RemoteOptionCert]
+	serverCfg, err := NewRemoteSignerTLSConfig(serverOpts, true)
+	assert.NoError(t, err)
+
+	w := New()
+	s := NewRemoteSigner(w, serverCfg, nil)
+	tl, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer tl.Close()
+	go s.Serve(tl)
+	sockPath := path.Join(dir, "signer.sock")
+	ul, err := net.Listen("unix", sockPath)
+	assert.NoError(t, err)
+	defer ul.Close()
+	go s.Serve(ul)
+
+	clientOpts := newTestCert(t, "client", ca).write(t, dir, "client")
+	clientOpts[RemoteOptionCA] = caOpts[RemoteOptionCert]
+	clientOpts[RemoteOptionServerName] = "localhost"
+
+	for _, addr := range []string{tl.Addr().String(), "unix://" + sockPath} {
+		rw, err := OpenRemote(addr, clientOpts)
+		assert.NoError(t, err)
+		assert.Equal(t, w.PublicKey(), rw.PublicKey())
+		assert.True(t, w.Address().Equal(rw.Address()))
+
+		hash := crypto.SHA3Sum256([]byte(addr))
+		sigBytes, err := rw.Sign(hash)
+		assert.NoError(t, err)
+		sig, err := crypto.ParseSignature(sigBytes)
+		assert.NoError(t, err)
+		pk, err := crypto.ParsePublicKey(rw.PublicKey())
+		assert.NoError(t, err)
+		assert.True(t, sig.Verify(hash, pk))
+	}
+
+	// client without certificate signed by the CA is refused
+	other := newTestCert(t, "other", nil)
+	otherOpts := newTestCert(t, "client", other).write(t, dir, "other")
+	otherOpts[RemoteOptionCA] = caOpts[RemoteOptionCert]
+	otherOpts[RemoteOptionServerName] = "localhost"
+	_, err = OpenRemote(tl.Addr().String(), otherOpts)
+	assert.Error(t, err)
+}
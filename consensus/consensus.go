@@ -12,6 +12,7 @@ import (
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/consensus/fastsync"
@@ -81,6 +82,7 @@ type consensus struct {
 	roundWAL    *WalMessageWriter
 	lockWAL     *WalMessageWriter
 	commitWAL   *WalMessageWriter
+	signGuard   *signGuard
 	timestamper module.Timestamper
 	nid         []byte
 	bpp         fastsync.BlockProofProvider
@@ -290,11 +292,13 @@ func (cs *consensus) OnReceive(
 	msg, err := UnmarshalMessage(sp.Uint16(), bs)
 	if err != nil {
 		cs.log.Warnf("malformed consensus message: OnReceive(subprotocol:%v, from:%v): %+v\n", sp, common.HexPre(id.Bytes()), err)
+		cs.ph.ReportPeer(id, module.PenaltyInvalidMessage)
 		return false, err
 	}
 	cs.log.Debugf("OnReceive(msg:%v, from:%v)\n", msg, common.HexPre(id.Bytes()))
 	if err = msg.Verify(); err != nil {
 		cs.log.Warnf("consensus message verify failed: OnReceive(msg:%v, from:%v): %+v\n", msg, common.HexPre(id.Bytes()), err)
+		cs.ph.ReportPeer(id, module.PenaltyInvalidMessage)
 		return false, err
 	}
 	switch m := msg.(type) {
@@ -302,6 +306,9 @@ func (cs *consensus) OnReceive(
 		err = cs.ReceiveProposalMessage(m, false)
 	case *BlockPartMessage:
 		_, err = cs.ReceiveBlockPartMessage(m, false)
+		if err != nil {
+			cs.ph.ReportPeer(id, module.PenaltyInvalidBlockPart)
+		}
 	case *VoteMessage:
 		_, err = cs.ReceiveVoteMessage(m, false)
 	case *VoteListMessage:
@@ -1000,6 +1007,11 @@ func (cs *consensus) doSendProposal(blockParts PartSet, polRound int32) error {
 	msg.Round = cs.round
 	msg.BlockPartSetID = blockParts.ID()
 	msg.POLRound = polRound
+	digest := crypto.SHA3Sum256(msg.proposal.bytes())
+	if err := cs.signGuard.check(msg.Height, msg.Round, signStepProposal, digest); err != nil {
+		cs.log.Errorf("refuse to sign proposal: %+v", err)
+		return err
+	}
 	err := msg.Sign(cs.c.Wallet())
 	if err != nil {
 		return err
@@ -1149,6 +1161,11 @@ func (cs *consensus) doSendVote(vt VoteType, blockParts *blockPartSet) error {
 	}
 	msg.Timestamp = cs.voteTimestamp()
 
+	digest := msg.RoundDecisionDigest()
+	if err := cs.signGuard.check(msg.Height, msg.Round, signStepOfVote(vt), digest); err != nil {
+		cs.log.Errorf("refuse to sign vote: %+v", err)
+		return err
+	}
 	err := msg.Sign(cs.c.Wallet())
 	if err != nil {
 		return err
@@ -1705,9 +1722,14 @@ func (cs *consensus) Start() error {
 	}
 	cs.commitWAL = &WalMessageWriter{ww}
 
+	cs.signGuard, err = openSignGuard(cs.wm, cs.walDir, cs.log)
+	if err != nil {
+		return err
+	}
+
 	cs.started = true
 	cs.log.Infof("Start consensus wallet:%v", common.HexPre(cs.c.Wallet().Address().ID()))
-	cs.syncer, err = newSyncer(cs, cs.log, cs.c.NetworkManager(), cs.c.BlockManager(), &cs.mutex, cs.c.Wallet().Address())
+	cs.syncer, err = newSyncer(cs, cs.log, cs.c.NetworkManager(), cs.c.BlockManager(), &cs.mutex, cs.c.Wallet().Address(), cs.c.FastSyncWindow())
 	if err != nil {
 		return err
 	}
@@ -1765,6 +1787,9 @@ func (cs *consensus) Term() {
 	if cs.commitWAL != nil {
 		cs.log.Must(cs.commitWAL.Close())
 	}
+	if cs.signGuard != nil {
+		cs.log.Must(cs.signGuard.Close())
+	}
 
 	if cs.log != nil {
 		cs.log.Infof("Term consensus.\n")
@@ -2026,6 +2051,16 @@ func (cs *consensus) processBlock(br fastsync.BlockResult) {
 	}
 }
 
+func (cs *consensus) FastSyncStats() *fastsync.FetchStats {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if cs.syncer == nil {
+		return nil
+	}
+	return cs.syncer.FetchStats()
+}
+
 func (cs *consensus) GetBlockProof(height int64, opt int32) ([]byte, error) {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
@@ -27,7 +27,9 @@ import (
 
 	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto/bls"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/consensus/fastsync"
 	"github.com/icon-project/goloop/module"
@@ -432,6 +434,81 @@ func TestConsensus_BTPBlockBasic(t_ *testing.T) {
 	assert.EqualValues(0, len(bbh.NetworkSectionToRoot()))
 }
 
+func TestConsensus_BLSNetworkType(t_ *testing.T) {
+	const dsa = "bls/bls12-381"
+	const uid = "bls"
+	assert := assert.New(t_)
+	f := test.NewFixture(t_, test.AddDefaultNode(false), test.AddValidatorNodes(4))
+	defer f.Close()
+
+	f.SendTransactionToProposer(test.NewTx().Call("setRevision", map[string]string{
+		"code": fmt.Sprintf("0x%x", basic.MaxRevision),
+	}).Call("setMinimizeBlockGen", map[string]string{
+		"yn": "0x1",
+	}))
+	test.NodeInterconnect(f.Nodes)
+	for _, n := range f.Nodes {
+		assert.NoError(n.CS.Start())
+	}
+	blk := f.WaitForBlock(2)
+
+	// network type is opened after the revision
+	tx := test.NewTx().SetTimestamp(blk.Timestamp())
+	for _, v := range f.Validators {
+		sk, _ := bls.GenerateKeyPair()
+		v.Chain.SetWalletFor(dsa, wallet.NewBLSFromPrivateKey(sk))
+		key, err := ntm.NewBLS12381DSAKey(sk)
+		assert.NoError(err)
+		tx.CallFrom(v.CommonAddress(), "setBTPPublicKey", map[string]string{
+			"name":   dsa,
+			"pubKey": fmt.Sprintf("0x%x", key),
+		})
+	}
+	tx.Call("openBTPNetwork", map[string]string{
+		"networkTypeName": uid,
+		"name":            fmt.Sprintf("%s-test", uid),
+		"owner":           f.CommonAddress().String(),
+	})
+	blk = f.SendTXToAllAndWaitForResultBlock(tx)
+	bd, err := blk.BTPDigest()
+	assert.NoError(err)
+	assert.EqualValues(1, len(bd.NetworkTypeDigests()))
+
+	testMsg := ([]byte)("test message")
+	blk = f.SendTXToAllAndWaitForResultBlock(
+		f.NewTx().CallFrom(f.CommonAddress(), "sendBTPMessage", map[string]string{
+			"networkId": "0x1",
+			"message":   fmt.Sprintf("0x%x", testMsg),
+		}),
+	)
+	bd, err = blk.BTPDigest()
+	assert.NoError(err)
+	assert.EqualValues(1, len(bd.NetworkTypeDigests()))
+
+	// the proof made of the aggregated signature of validators is verified
+	bbh, pfBytes, err := f.CS.GetBTPBlockHeaderAndProof(
+		blk, 1,
+		module.FlagBTPBlockHeader|module.FlagBTPBlockProof,
+	)
+	assert.NoError(err)
+	prevBlk, err := f.BM.GetBlockByHeight(blk.Height() - 1)
+	assert.NoError(err)
+	pcm, err := prevBlk.NextProofContextMap()
+	assert.NoError(err)
+	pc, err := pcm.ProofContextFor(1)
+	assert.NoError(err)
+	assert.EqualValues(dsa, pc.DSA())
+	pf, err := pc.NewProofFromBytes(pfBytes)
+	assert.NoError(err)
+	ntsd := pc.NewDecision(module.SourceNetworkUID(1), 1, blk.Height(), bbh.Round(), bd.NetworkTypeDigestFor(1).NetworkTypeSectionHash())
+	assert.NoError(pc.Verify(ntsd.Hash(), pf))
+
+	// blocks are produced continuously with NTS votes of the validators
+	height := blk.Height()
+	blk = f.SendTXToAllAndWaitForResultBlock(f.NewTx())
+	assert.True(blk.Height() > height)
+}
+
 func TestConsensus_ChangeBTPKey(t_ *testing.T) {
 	const dsa = "ecdsa/secp256k1"
 	tst := newBTPTest(t_)
@@ -26,8 +26,22 @@ type client struct {
 	bm  module.BlockDataFactory
 	log log.Logger
 
+	window    int
+	maxActive int
+
 	fetchID uint16
 	fr      *fetchRequest
+	stats   fetchStats
+}
+
+type fetchStats struct {
+	start    time.Time
+	end      time.Time
+	begin    int64
+	height   int64
+	blocks   int64
+	bytes    int64
+	rejected int64
 }
 
 type blockResult struct {
@@ -58,6 +72,8 @@ func (br *blockResult) Consume() {
 	}
 
 	fr.consumeOffset++
+	cl.stats.height = fr.consumeOffset
+	cl.stats.blocks++
 	copy(fr.pendingResults, fr.pendingResults[1:])
 	fr.pendingResults[len(fr.pendingResults)-1] = nil
 	fr._reschedule()
@@ -79,10 +95,12 @@ func (br *blockResult) Reject() {
 
 	cl := br.cl
 	cl.log.Tracef("Reject %d\n", br.blk.Height())
+	cl.ph.ReportPeer(br.id, module.PenaltyFastSyncFailure)
 	fr := br.fr
 	if cl.fr != fr {
 		return
 	}
+	cl.stats.rejected++
 
 	for i, p := range fr.validPeers {
 		if p.id.Equal(br.id) {
@@ -141,9 +159,63 @@ func newClient(nm module.NetworkManager, ph module.ProtocolHandler,
 	cl.ph = ph
 	cl.bm = bm
 	cl.log = logger
+	cl.window = configMaxPendingResults
+	cl.maxActive = configMaxActive
 	return cl
 }
 
+// setWindow sets the number of blocks fetched ahead of consumption. It's
+// also the maximum number of blocks being downloaded at the same time.
+// If window is not positive, the default is used. It's applied from the
+// next fetch request.
+func (cl *client) setWindow(window int) {
+	cl.Lock()
+	defer cl.Unlock()
+
+	if window <= 0 {
+		cl.window = configMaxPendingResults
+		cl.maxActive = configMaxActive
+	} else {
+		cl.window = window
+		cl.maxActive = window
+	}
+}
+
+func (cl *client) getStats() *FetchStats {
+	cl.Lock()
+	defer cl.Unlock()
+
+	st := &FetchStats{
+		Fetching:  cl.fr != nil,
+		Window:    cl.window,
+		MaxActive: cl.maxActive,
+		Begin:     cl.stats.begin,
+		Height:    cl.stats.height,
+		Blocks:    cl.stats.blocks,
+		Bytes:     cl.stats.bytes,
+		Rejected:  cl.stats.rejected,
+	}
+	if cl.fr != nil {
+		st.Window = len(cl.fr.pendingResults)
+		st.MaxActive = cl.fr.maxActive
+		st.Active = cl.fr.nActivePeers
+		st.Peers = len(cl.fr.validPeers)
+	}
+	if !cl.stats.start.IsZero() {
+		end := cl.stats.end
+		if cl.fr != nil {
+			end = time.Now()
+		}
+		elapsed := end.Sub(cl.stats.start)
+		st.Elapsed = elapsed.Round(time.Millisecond).String()
+		if elapsed > 0 {
+			st.BlocksPerSecond = float64(cl.stats.blocks) / elapsed.Seconds()
+			st.BytesPerSecond = float64(cl.stats.bytes) / elapsed.Seconds()
+		}
+	}
+	return st
+}
+
 func (cl *client) fetchBlocks(
 	begin int64,
 	end int64,
@@ -164,7 +236,7 @@ func (cl *client) fetchBlocks(
 	fr.cl = cl
 	fr.heightSet = newHeightSet(begin, end)
 	fr.cb = cb
-	fr.maxActive = configMaxActive
+	fr.maxActive = cl.maxActive
 
 	peerIDs := cl.ph.GetPeers()
 	fr.validPeers = make([]*peer, len(peerIDs))
@@ -173,7 +245,12 @@ func (cl *client) fetchBlocks(
 	}
 	fr.nActivePeers = 0
 	fr.consumeOffset = begin
-	fr.pendingResults = make([]*blockResult, configMaxPendingResults)
+	fr.pendingResults = make([]*blockResult, cl.window)
+	cl.stats = fetchStats{
+		start:  time.Now(),
+		begin:  begin,
+		height: begin,
+	}
 	fr._reschedule()
 	cl.fr = fr
 	return fr, nil
@@ -402,6 +479,7 @@ func (fr *fetchRequest) newFetcher(id module.PeerID, height int64, requestID uin
 func (fr *fetchRequest) _cancel() bool {
 	if fr.cl.fr == fr {
 		fr.cl.fr = nil
+		fr.cl.stats.end = time.Now()
 	}
 
 	for _, p := range fr.validPeers {
@@ -521,6 +599,7 @@ func (f *fetcher) onReceive(pi module.ProtocolInfo, b []byte) {
 		}
 		f.dataList = append(f.dataList, msg.Data)
 		f.left -= int32(len(msg.Data))
+		f.cl.stats.bytes += int64(len(msg.Data))
 		f.cl.log.Tracef("onReceive BlockData rid=%d, data len=%d left=%d\n", msg.RequestID, len(msg.Data), f.left)
 		if f.left == 0 {
 			f.step = fstepFin
@@ -528,30 +607,50 @@ func (f *fetcher) onReceive(pi module.ProtocolInfo, b []byte) {
 				f.timer.Stop()
 				f.timer = nil
 			}
-			bufs := make([]io.Reader, len(f.dataList))
-			for i, d := range f.dataList {
-				bufs[i] = bytes.NewReader(d)
-			}
-			r := io.MultiReader(bufs...)
-			blk, err := f.cl.bm.NewBlockDataFromReader(r)
-			if err != nil {
-				f.cl.onResult(f, err, nil, nil)
-			} else if blk.Height() != f.height {
-				f.cl.onResult(f, errors.Errorf("bad Height"), nil, nil)
-			} else {
-				f.cl.onResult(f, nil, blk, f.voteList)
-			}
+			go f.decode(f.dataList, f.voteList)
+			f.dataList = nil
 		} else if f.left < 0 {
 			f.step = fstepFin
 			if f.timer != nil {
 				f.timer.Stop()
 				f.timer = nil
 			}
+			f.cl.ph.ReportPeer(f.id, module.PenaltyFastSyncFailure)
 			f.cl.onResult(f, errors.Errorf("bad data"), nil, nil)
 		}
 	}
 }
 
+// decode decodes and verifies the block out of the lock, so that blocks
+// from multiple peers are handled in parallel. The result is delivered in
+// order of height through pendingResults.
+func (f *fetcher) decode(dataList [][]byte, votes []byte) {
+	bufs := make([]io.Reader, len(dataList))
+	for i, d := range dataList {
+		bufs[i] = bytes.NewReader(d)
+	}
+	r := io.MultiReader(bufs...)
+	blk, err := f.cl.bm.NewBlockDataFromReader(r)
+	if err == nil && blk.Height() != f.height {
+		err = errors.Errorf("bad Height")
+	}
+	if err == nil {
+		if bv, ok := f.fr.cb.(BlockVerifier); ok {
+			err = bv.VerifyBlock(blk, votes)
+		}
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if err != nil {
+		f.cl.ph.ReportPeer(f.id, module.PenaltyFastSyncFailure)
+		f.cl.onResult(f, err, nil, nil)
+	} else {
+		f.cl.onResult(f, nil, blk, votes)
+	}
+}
+
 func isTemporary(err error) bool {
 	ne, ok := err.(module.NetworkError)
 	return ok && ne.Temporary()
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/consensus/internal/test"
 	"github.com/icon-project/goloop/module"
@@ -175,3 +177,80 @@ func TestClient_SuccessMulti(t *testing.T) {
 	ev2 = <-s.cb.ch
 	s.assertEndEvent(nil, ev2)
 }
+
+type tVerifyingFetchCallback struct {
+	*tFetchCallback
+	fail map[int64]bool
+}
+
+func (cb *tVerifyingFetchCallback) VerifyBlock(blk module.BlockData, votes []byte) error {
+	if cb.fail[blk.Height()] {
+		delete(cb.fail, blk.Height())
+		return errors.New("verification failure")
+	}
+	return nil
+}
+
+func (s *clientTestSetUp) receiveBlockRequest(peers ...int) (int, *BlockRequestV1) {
+	for {
+		for _, i := range peers {
+			select {
+			case ev := <-s.reactors[i].ch:
+				rev := ev.(tReceiveEvent)
+				assert.Equal(s.t, ProtoBlockRequest, rev.pi)
+				msg := new(BlockRequestV1)
+				codec.MustUnmarshalFromBytes(rev.b, msg)
+				return i, msg
+			default:
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestClient_WindowAndVerifier(t *testing.T) {
+	s := newClientTestSetUp(t, 3)
+	s.m.SetWindow(1)
+	cb := &tVerifyingFetchCallback{
+		tFetchCallback: s.cb,
+		fail:           map[int64]bool{1: true},
+	}
+	_, err := s.m.FetchBlocks(1, 2, cb)
+	assert.NoError(t, err)
+	st := s.m.Stats()
+	assert.True(t, st.Fetching)
+	assert.Equal(t, 1, st.Window)
+	assert.Equal(t, 1, st.Active)
+
+	// fails in verification, so it's fetched from the other peer
+	p, req := s.receiveBlockRequest(1, 2)
+	assert.EqualValues(t, 1, req.Height)
+	s.respondBlockRequest(s.phs[p], req.RequestID, s.rawBlocks[1], s.votes[2], s.nms[0].ID)
+	other := 3 - p
+	p, req = s.receiveBlockRequest(other)
+	assert.EqualValues(t, 1, req.Height)
+	s.respondBlockRequest(s.phs[p], req.RequestID, s.rawBlocks[1], s.votes[2], s.nms[0].ID)
+
+	ev := <-s.cb.ch
+	s.assertBlockEvent(s.rawBlocks[1], ev)
+	ev.(tOnBlockEvent).br.Consume()
+
+	// only one block in the window
+	p, req = s.receiveBlockRequest(other)
+	assert.EqualValues(t, 2, req.Height)
+	s.respondBlockRequest(s.phs[p], req.RequestID, s.rawBlocks[2], s.votes[3], s.nms[0].ID)
+
+	ev = <-s.cb.ch
+	s.assertBlockEvent(s.rawBlocks[2], ev)
+	ev.(tOnBlockEvent).br.Consume()
+
+	ev = <-s.cb.ch
+	s.assertEndEvent(nil, ev)
+
+	st = s.m.Stats()
+	assert.False(t, st.Fetching)
+	assert.EqualValues(t, 1, st.Begin)
+	assert.EqualValues(t, 3, st.Height)
+	assert.EqualValues(t, 2, st.Blocks)
+	assert.True(t, st.Bytes > 0)
+}
@@ -28,6 +28,31 @@ type FetchCallback interface {
 	OnEnd(err error)
 }
 
+// BlockVerifier may be implemented by FetchCallback. VerifyBlock is called
+// for each fetched block in its own goroutine before the block is delivered
+// by OnBlock, so it shall not depend on the blocks delivered before.
+// If it returns error, the block is dropped and fetched from other peer.
+type BlockVerifier interface {
+	VerifyBlock(blk module.BlockData, votes []byte) error
+}
+
+// FetchStats is the status of the last fetch request.
+type FetchStats struct {
+	Fetching        bool    `json:"fetching"`
+	Window          int     `json:"window"`
+	MaxActive       int     `json:"maxActive"`
+	Active          int     `json:"active"`
+	Peers           int     `json:"peers"`
+	Begin           int64   `json:"begin"`
+	Height          int64   `json:"height"`
+	Blocks          int64   `json:"blocks"`
+	Bytes           int64   `json:"bytes"`
+	Rejected        int64   `json:"rejected"`
+	Elapsed         string  `json:"elapsed,omitempty"`
+	BlocksPerSecond float64 `json:"blocksPerSecond"`
+	BytesPerSecond  float64 `json:"bytesPerSecond"`
+}
+
 type Manager interface {
 	StartServer()
 	StopServer()
@@ -36,6 +61,11 @@ type Manager interface {
 		end int64,
 		cb FetchCallback,
 	) (canceler func() bool, err error)
+	// SetWindow sets the number of blocks fetched ahead. Blocks in the
+	// window are downloaded from different peers at the same time.
+	// Not positive value means the default.
+	SetWindow(window int)
+	Stats() *FetchStats
 	Term()
 }
 
@@ -90,6 +120,14 @@ func (m *manager) FetchBlocks(
 	}, nil
 }
 
+func (m *manager) SetWindow(window int) {
+	m.client.setWindow(window)
+}
+
+func (m *manager) Stats() *FetchStats {
+	return m.client.getStats()
+}
+
 func (m *manager) Term() {
 	if m.nm != nil {
 		err := m.nm.UnregisterReactor(m)
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"github.com/icon-project/goloop/consensus/fastsync"
+	"github.com/icon-project/goloop/module"
+)
+
+// FastSyncInspector is implemented by consensus using fast sync.
+type FastSyncInspector interface {
+	// FastSyncStats returns the status of the last fast sync. It returns
+	// nil if fast sync isn't used.
+	FastSyncStats() *fastsync.FetchStats
+}
+
+func Inspect(c module.Chain, informal bool) map[string]interface{} {
+	fi, ok := c.Consensus().(FastSyncInspector)
+	if !ok {
+		return nil
+	}
+	st := fi.FastSyncStats()
+	if st == nil {
+		return nil
+	}
+	m := make(map[string]interface{})
+	m["fastsync"] = st
+	return m
+}
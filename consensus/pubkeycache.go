@@ -0,0 +1,134 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const configPublicKeyCacheSize = 1 << 15
+
+// publicKeyCache keeps public keys recovered from signatures of commit votes
+// of fetched blocks. Keys are recovered in parallel while blocks wait for
+// execution, and the cache is used when the blocks are verified in order.
+type publicKeyCache struct {
+	mtx   sync.Mutex
+	keys  map[string]*crypto.PublicKey
+	queue []string
+	next  int
+}
+
+func newPublicKeyCache(size int) *publicKeyCache {
+	return &publicKeyCache{
+		keys:  make(map[string]*crypto.PublicKey, size),
+		queue: make([]string, size),
+	}
+}
+
+func publicKeyCacheKey(sig common.Signature, hash []byte) (string, bool) {
+	if sig.Signature == nil {
+		return "", false
+	}
+	bs, err := sig.Signature.SerializeRSV()
+	if err != nil {
+		return "", false
+	}
+	return string(hash) + string(bs), true
+}
+
+func (c *publicKeyCache) get(sig common.Signature, hash []byte) *crypto.PublicKey {
+	k, ok := publicKeyCacheKey(sig, hash)
+	if !ok {
+		return nil
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.keys[k]
+}
+
+func (c *publicKeyCache) put(sig common.Signature, hash []byte, pk *crypto.PublicKey) {
+	k, ok := publicKeyCacheKey(sig, hash)
+	if !ok {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.keys[k]; ok {
+		return
+	}
+	if old := c.queue[c.next]; len(old) > 0 {
+		delete(c.keys, old)
+	}
+	c.queue[c.next] = k
+	c.next = (c.next + 1) % len(c.queue)
+	c.keys[k] = pk
+}
+
+var pubKeyCache = newPublicKeyCache(configPublicKeyCacheSize)
+
+func recoverPublicKey(sig common.Signature, hash []byte) (*crypto.PublicKey, error) {
+	if pk := pubKeyCache.get(sig, hash); pk != nil {
+		return pk, nil
+	}
+	return sig.RecoverPublicKey(hash)
+}
+
+// PrepareCommitVotes recovers public keys of the commit votes for the block
+// and keeps them for the verification of the block. It doesn't depend on
+// the previous blocks, so it can be called for blocks in any order.
+// It returns error only if the votes are malformed.
+func PrepareCommitVotes(blk module.BlockData, votes []byte) error {
+	cvs := NewCommitVoteSetFromBytes(votes)
+	if cvs == nil {
+		return errors.IllegalArgumentError.Errorf("InvalidCommitVotes(height=%d)", blk.Height())
+	}
+	cvl := cvs.(*CommitVoteList)
+	entries, err := blk.NTSHashEntryList()
+	if err != nil {
+		return err
+	}
+	// NTS votes for inactive network types are excluded in the verification,
+	// which isn't known without the previous block. Skip the votes then.
+	nCount := entries.NTSHashEntryCount()
+	if cvl.BlockPartSetIDAndNTSVoteCount == nil ||
+		int(cvl.BlockPartSetIDAndNTSVoteCount.AppData()) != nCount {
+		return nil
+	}
+	bases := make([]ntsVoteBase, 0, nCount)
+	for i := 0; i < nCount; i++ {
+		bases = append(bases, ntsVoteBase(entries.NTSHashEntryAt(i)))
+	}
+	msg := newVoteMessage()
+	msg.Height = blk.Height()
+	msg.Round = cvl.Round
+	msg.Type = VoteTypePrecommit
+	msg.SetRoundDecision(blk.ID(), cvl.BlockPartSetIDAndNTSVoteCount, bases)
+	for _, item := range cvl.Items {
+		msg.Timestamp = item.Timestamp
+		msg.setSignature(item.Signature)
+		if pk, err := item.Signature.RecoverPublicKey(msg.hash()); err == nil {
+			pubKeyCache.put(item.Signature, msg.hash(), pk)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+)
+
+func TestPublicKeyCache_Basic(t *testing.T) {
+	c := newPublicKeyCache(2)
+
+	type entry struct {
+		hash []byte
+		sig  common.Signature
+		pk   *crypto.PublicKey
+	}
+	var entries []entry
+	for i := 0; i < 3; i++ {
+		sk, pk := crypto.GenerateKeyPair()
+		hash := crypto.SHA3Sum256([]byte{byte(i)})
+		sig, err := crypto.NewSignature(hash, sk)
+		assert.NoError(t, err)
+		entries = append(entries, entry{hash, common.Signature{Signature: sig}, pk})
+	}
+
+	assert.Nil(t, c.get(entries[0].sig, entries[0].hash))
+	c.put(entries[0].sig, entries[0].hash, entries[0].pk)
+	c.put(entries[1].sig, entries[1].hash, entries[1].pk)
+	assert.Equal(t, entries[0].pk, c.get(entries[0].sig, entries[0].hash))
+	assert.Nil(t, c.get(entries[0].sig, entries[1].hash))
+
+	// the oldest one is evicted
+	c.put(entries[2].sig, entries[2].hash, entries[2].pk)
+	assert.Nil(t, c.get(entries[0].sig, entries[0].hash))
+	assert.Equal(t, entries[1].pk, c.get(entries[1].sig, entries[1].hash))
+	assert.Equal(t, entries[2].pk, c.get(entries[2].sig, entries[2].hash))
+}
@@ -30,7 +30,7 @@ func (s *signedBase) hash() []byte {
 
 func (s *signedBase) publicKey() *crypto.PublicKey {
 	if s._publicKey == nil {
-		publicKey, err := s.Signature.RecoverPublicKey(s.hash())
+		publicKey, err := recoverPublicKey(s.Signature, s.hash())
 		if err != nil {
 			return nil
 		}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	configSignWALID       = "sign"
+	configSignWALDataSize = 1024 * 16
+)
+
+type signStep byte
+
+const (
+	signStepProposal signStep = iota
+	signStepPrevote
+	signStepPrecommit
+)
+
+func signStepOfVote(vt VoteType) signStep {
+	if vt == VoteTypePrevote {
+		return signStepPrevote
+	}
+	return signStepPrecommit
+}
+
+func (s signStep) String() string {
+	switch s {
+	case signStepProposal:
+		return "Proposal"
+	case signStepPrevote:
+		return "PreVote"
+	case signStepPrecommit:
+		return "PreCommit"
+	default:
+		return "Unknown"
+	}
+}
+
+type signRecord struct {
+	Height int64
+	Round  int32
+	Step   signStep
+	Digest []byte
+}
+
+func (r *signRecord) compare(r2 *signRecord) int {
+	switch {
+	case r.Height != r2.Height:
+		return compareInt64(r.Height, r2.Height)
+	case r.Round != r2.Round:
+		return compareInt64(int64(r.Round), int64(r2.Round))
+	default:
+		return compareInt64(int64(r.Step), int64(r2.Step))
+	}
+}
+
+func (r *signRecord) String() string {
+	return fmt.Sprintf("{H:%d R:%d S:%s D:%v}",
+		r.Height, r.Round, r.Step, common.HexPre(r.Digest))
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// signGuard refuses to sign a proposal or a vote conflicting with the one
+// signed before. The last signed one is written to the WAL before signing,
+// so the protection survives restart of the node.
+type signGuard struct {
+	log  log.Logger
+	wal  WALWriter
+	last *signRecord
+}
+
+func openSignGuard(wm WALManager, walDir string, logger log.Logger) (*signGuard, error) {
+	g := &signGuard{log: logger}
+	id := path.Join(walDir, configSignWALID)
+	if wr, err := wm.OpenForRead(id); err == nil {
+		for {
+			bs, err := wr.ReadBytes()
+			if IsEOF(err) {
+				break
+			} else if IsCorruptedWAL(err) || IsUnexpectedEOF(err) {
+				logger.Warnf("openSignGuard: %+v", err)
+				if err := wr.CloseAndRepair(); err != nil {
+					return nil, err
+				}
+				wr = nil
+				break
+			} else if err != nil {
+				logger.Must(wr.Close())
+				return nil, err
+			}
+			r := new(signRecord)
+			if _, err := msgCodec.UnmarshalFromBytes(bs, r); err != nil {
+				logger.Must(wr.Close())
+				return nil, err
+			}
+			if g.last == nil || g.last.compare(r) < 0 {
+				g.last = r
+			}
+		}
+		if wr != nil {
+			logger.Must(wr.Close())
+		}
+	}
+	ww, err := wm.OpenForWrite(id, &WALConfig{
+		FileLimit:  configSignWALDataSize,
+		TotalLimit: configSignWALDataSize * 3,
+	})
+	if err != nil {
+		return nil, err
+	}
+	g.wal = ww
+	return g, nil
+}
+
+// check returns error if signing the item conflicts with the last signed one.
+// Signing the same decision again is allowed. Otherwise, it records the item
+// as the last signed one.
+func (g *signGuard) check(height int64, round int32, step signStep, digest []byte) error {
+	r := &signRecord{
+		Height: height,
+		Round:  round,
+		Step:   step,
+		Digest: digest,
+	}
+	if g.last != nil {
+		switch cmp := r.compare(g.last); {
+		case cmp < 0:
+			return errors.InvalidStateError.Errorf(
+				"SignRegression(last=%s,new=%s)", g.last, r)
+		case cmp == 0:
+			if !bytes.Equal(r.Digest, g.last.Digest) {
+				return errors.InvalidStateError.Errorf(
+					"DoubleSign(last=%s,new=%s)", g.last, r)
+			}
+			return nil
+		}
+	}
+	bs, err := msgCodec.MarshalToBytes(r)
+	if err != nil {
+		return err
+	}
+	if _, err := g.wal.WriteBytes(bs); err != nil {
+		return err
+	}
+	if err := g.wal.Sync(); err != nil {
+		return err
+	}
+	g.last = r
+	return nil
+}
+
+func (g *signGuard) Close() error {
+	return g.wal.Close()
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+func TestSignGuard_Basic(t *testing.T) {
+	wal := NewTestWAL()
+	logger := log.New()
+
+	g, err := openSignGuard(wal, "", logger)
+	assert.NoError(t, err)
+	d1 := []byte{1}
+	d2 := []byte{2}
+
+	assert.NoError(t, g.check(10, 0, signStepProposal, d1))
+	assert.NoError(t, g.check(10, 0, signStepPrevote, d1))
+	// the same decision can be signed again
+	assert.NoError(t, g.check(10, 0, signStepPrevote, d1))
+	// conflicting decision
+	err = g.check(10, 0, signStepPrevote, d2)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	// previous step
+	err = g.check(10, 0, signStepProposal, d1)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.NoError(t, g.check(10, 1, signStepPrevote, d2))
+	assert.NoError(t, g.Close())
+
+	// the last one survives restart
+	g, err = openSignGuard(wal, "", logger)
+	assert.NoError(t, err)
+	err = g.check(10, 1, signStepPrevote, d1)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	err = g.check(9, 5, signStepPrecommit, d1)
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	assert.NoError(t, g.check(10, 1, signStepPrecommit, d1))
+	assert.NoError(t, g.check(11, 0, signStepPrevote, d2))
+	assert.NoError(t, g.Close())
+}
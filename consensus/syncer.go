@@ -41,6 +41,7 @@ type Syncer interface {
 	Start() error
 	Stop()
 	OnEngineStepChange()
+	FetchStats() *fastsync.FetchStats
 }
 
 var SyncerProtocols = []module.ProtocolInfo{
@@ -263,11 +264,12 @@ type syncer struct {
 	fetchCanceler func() bool
 }
 
-func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.BlockManager, mutex *common.Mutex, addr module.Address) (Syncer, error) {
+func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.BlockManager, mutex *common.Mutex, addr module.Address, window int) (Syncer, error) {
 	fsm, err := fastsync.NewManager(nm, bm, e, logger)
 	if err != nil {
 		return nil, err
 	}
+	fsm.SetWindow(window)
 	fsm.StartServer()
 	return &syncer{
 		engine: e,
@@ -494,6 +496,16 @@ func (s *syncer) OnBlock(br fastsync.BlockResult) {
 	s.engine.ReceiveBlock(br)
 }
 
+// VerifyBlock prepares verification of the commit votes while the block
+// waits for the previous blocks.
+func (s *syncer) VerifyBlock(blk module.BlockData, votes []byte) error {
+	return PrepareCommitVotes(blk, votes)
+}
+
+func (s *syncer) FetchStats() *fastsync.FetchStats {
+	return s.fsm.Stats()
+}
+
 func (s *syncer) OnEnd(err error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -33,12 +33,43 @@ type testWAL struct {
 	round  []*record
 	lock   []*record
 	commit []*record
+	sign   []*record
 }
 
 func NewTestWAL() *testWAL {
 	return &testWAL{}
 }
 
+// LoadTestWAL returns the test WAL manager having the records of WALs in
+// dir. It can be used to replay WALs of a node with a test consensus.
+// Like consensus, it stops reading a WAL at a corrupted record.
+func LoadTestWAL(dir string) (WALManager, error) {
+	w := NewTestWAL()
+	for _, id := range WALIDs {
+		wr, err := OpenWALForRead(path.Join(dir, id))
+		if IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		data := w.data(id)
+		for {
+			bs, err := wr.ReadBytes()
+			if IsEOF(err) || IsCorruptedWAL(err) || IsUnexpectedEOF(err) {
+				break
+			} else if err != nil {
+				log.Must(wr.Close())
+				return nil, err
+			}
+			*data = append(*data, &record{bs, nil})
+		}
+		if err := wr.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
 func (w *testWAL) data(id string) *[]*record {
 	id = path.Base(id)
 	switch id {
@@ -48,6 +79,8 @@ func (w *testWAL) data(id string) *[]*record {
 		return &w.lock
 	case "commit":
 		return &w.commit
+	case "sign":
+		return &w.sign
 	default:
 		log.Panicf("invalid wal id %s", id)
 		return nil
@@ -0,0 +1,483 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+// WALIDs is the list of WAL IDs used by consensus in the order of
+// application on start. The sign WAL has the last proposal or vote signed
+// by the node instead of messages.
+var WALIDs = []string{configRoundWALID, configLockWALID, configCommitWALID, configSignWALID}
+
+// WALRecord is a frame in a segment file of WAL.
+type WALRecord struct {
+	File   string
+	Offset int64
+	Length int
+
+	// Valid is true if the checksum of the frame matches.
+	Valid bool
+
+	// Message is the decoded message of round, lock and commit WALs.
+	// Sign is the decoded record of the sign WAL. They are nil if Error is
+	// not nil.
+	Message Message
+	Sign    *WALMessageInfo
+	Error   error
+}
+
+// Info returns the readable summary of the record. It returns nil if the
+// record is not decoded.
+func (rec *WALRecord) Info() *WALMessageInfo {
+	if rec.Sign != nil {
+		return rec.Sign
+	}
+	if rec.Message != nil {
+		return WALMessageInfoOf(rec.Message)
+	}
+	return nil
+}
+
+// ReadWALRecords calls fn for the frames in the segments of the WAL (id is
+// in the form /wal/dir/prefix). Unlike WALReader, it doesn't stop at a frame
+// with bad checksum. A truncated frame is reported with io.ErrUnexpectedEOF
+// and the rest of the segment is skipped.
+func ReadWALRecords(id string, fn func(rec *WALRecord) error) error {
+	wi, err := readWALInfo(id)
+	if err != nil {
+		return err
+	}
+	if wi.headIdx > wi.tailIdx {
+		return errors.Wrapf(os.ErrNotExist, "no file for wal %v", id)
+	}
+	decode := decodeWALMessageRecord
+	if path.Base(id) == configSignWALID {
+		decode = decodeWALSignRecord
+	}
+	for idx := wi.headIdx; idx <= wi.tailIdx; idx++ {
+		if err := readWALSegment(fileFor(id, idx), decode, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readWALSegment(name string, decode func(rec *WALRecord, bs []byte), fn func(rec *WALRecord) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	defer func() {
+		log.Must(f.Close())
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	size := fi.Size()
+
+	header := make([]byte, headerLen)
+	for offset := int64(0); offset < size; {
+		rec := &WALRecord{
+			File:   name,
+			Offset: offset,
+		}
+		if size-offset < headerLen {
+			rec.Error = errors.Wrapf(io.ErrUnexpectedEOF, "truncated header len=%d", size-offset)
+			return fn(rec)
+		}
+		if _, err := f.ReadAt(header, offset); err != nil {
+			return errors.WithStack(err)
+		}
+		crc := binary.BigEndian.Uint32(header[0:4])
+		payloadLen := int64(binary.BigEndian.Uint32(header[4:headerLen]))
+		if size-offset-headerLen < payloadLen {
+			rec.Error = errors.Wrapf(io.ErrUnexpectedEOF, "truncated payload len=%d remain=%d", payloadLen, size-offset-headerLen)
+			return fn(rec)
+		}
+		payload := make([]byte, payloadLen)
+		if _, err := f.ReadAt(payload, offset+headerLen); err != nil {
+			return errors.WithStack(err)
+		}
+		rec.Length = int(payloadLen)
+		rec.Valid = crc32.Checksum(payload, crc32c) == crc
+		if rec.Valid {
+			decode(rec, payload)
+		} else {
+			rec.Error = errors.Wrapf(errCorruptedWAL, "bad crc: read:%x actual:%x", crc, crc32.Checksum(payload, crc32c))
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+		offset += headerLen + payloadLen
+	}
+	return nil
+}
+
+func decodeWALMessageRecord(rec *WALRecord, bs []byte) {
+	rec.Message, rec.Error = decodeWALMessage(bs)
+}
+
+func decodeWALSignRecord(rec *WALRecord, bs []byte) {
+	r := new(signRecord)
+	if _, err := msgCodec.UnmarshalFromBytes(bs, r); err != nil {
+		rec.Error = err
+		return
+	}
+	rec.Sign = &WALMessageInfo{
+		Type:   "sign",
+		Height: r.Height,
+		Round:  r.Round,
+		Step:   r.Step.String(),
+		Digest: fmt.Sprintf("%#x", r.Digest),
+	}
+}
+
+func decodeWALMessage(bs []byte) (Message, error) {
+	if len(bs) < 2 {
+		return nil, errors.Errorf("too short wal message len=%v", len(bs))
+	}
+	sp := binary.BigEndian.Uint16(bs[0:2])
+	msg, err := UnmarshalMessage(sp, bs[2:])
+	if err != nil {
+		return nil, err
+	}
+	if err = msg.Verify(); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// WALMessageInfo is a readable summary of a message in WAL.
+type WALMessageInfo struct {
+	Type      string            `json:"type"`
+	Height    int64             `json:"height"`
+	Round     int32             `json:"round"`
+	VoteType  string            `json:"voteType,omitempty"`
+	Validator string            `json:"validator,omitempty"`
+	BlockID   string            `json:"blockID,omitempty"`
+	PartSetID string            `json:"partSetID,omitempty"`
+	POLRound  *int32            `json:"polRound,omitempty"`
+	Index     *uint16           `json:"index,omitempty"`
+	Votes     []*WALMessageInfo `json:"votes,omitempty"`
+
+	// Step and Digest are for the records of the sign WAL.
+	Step   string `json:"step,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+func formatPartSetID(psid *PartSetID) string {
+	if psid == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%#x", psid.Count, psid.Hash)
+}
+
+func voteInfoOf(m *VoteMessage) *WALMessageInfo {
+	info := &WALMessageInfo{
+		Type:      "vote",
+		Height:    m.Height,
+		Round:     m.Round,
+		VoteType:  m.Type.String(),
+		PartSetID: formatPartSetID(m.BlockPartSetIDAndNTSVoteCount.ID()),
+	}
+	if len(m.BlockID) > 0 {
+		info.BlockID = fmt.Sprintf("%#x", m.BlockID)
+	}
+	if addr := m.address(); addr != nil {
+		info.Validator = addr.String()
+	}
+	return info
+}
+
+// WALMessageInfoOf returns the readable summary of the message.
+func WALMessageInfoOf(msg Message) *WALMessageInfo {
+	switch m := msg.(type) {
+	case *ProposalMessage:
+		info := &WALMessageInfo{
+			Type:      "proposal",
+			Height:    m.Height,
+			Round:     m.Round,
+			PartSetID: formatPartSetID(m.BlockPartSetID),
+			POLRound:  &m.POLRound,
+		}
+		if addr := m.address(); addr != nil {
+			info.Validator = addr.String()
+		}
+		return info
+	case *BlockPartMessage:
+		return &WALMessageInfo{
+			Type:   "blockPart",
+			Height: m.Height,
+			Index:  &m.Index,
+		}
+	case *VoteMessage:
+		return voteInfoOf(m)
+	case *VoteListMessage:
+		info := &WALMessageInfo{
+			Type: "voteList",
+		}
+		for i := 0; i < m.VoteList.Len(); i++ {
+			vote := voteInfoOf(m.VoteList.Get(i))
+			if i == 0 {
+				info.Height = vote.Height
+				info.Round = vote.Round
+				info.VoteType = vote.VoteType
+			}
+			info.Votes = append(info.Votes, vote)
+		}
+		return info
+	case *RoundStateMessage:
+		return &WALMessageInfo{
+			Type:   "roundState",
+			Height: m.Height,
+			Round:  m.Round,
+		}
+	default:
+		return &WALMessageInfo{
+			Type: fmt.Sprintf("%T", msg),
+		}
+	}
+}
+
+// WALRoundSummary is the messages of a round found in WAL.
+type WALRoundSummary struct {
+	Round      int32             `json:"round"`
+	Proposals  []*WALMessageInfo `json:"proposals,omitempty"`
+	Prevotes   []*WALMessageInfo `json:"prevotes,omitempty"`
+	Precommits []*WALMessageInfo `json:"precommits,omitempty"`
+
+	// POL is true if the lock WAL has polka (prevotes for a block over
+	// two thirds) for the round.
+	POL bool `json:"pol,omitempty"`
+
+	// Commit is true if the commit WAL has precommits for the round.
+	Commit bool `json:"commit,omitempty"`
+}
+
+// WALSummary is the consensus state of a height summarized from WAL.
+type WALSummary struct {
+	Height      int64              `json:"height"`
+	Round       int32              `json:"round"`
+	Step        string             `json:"step"`
+	LockedRound int32              `json:"lockedRound"`
+	BlockParts  int                `json:"blockParts"`
+	Rounds      []*WALRoundSummary `json:"rounds"`
+	Corrupted   []string           `json:"corrupted,omitempty"`
+
+	// LastSigned is the last proposal or vote signed by the node.
+	LastSigned *WALMessageInfo `json:"lastSigned,omitempty"`
+}
+
+// WALReplayStep is the consensus state of the height after a record of
+// WAL is applied.
+type WALReplayStep struct {
+	WAL         string          `json:"wal"`
+	File        string          `json:"file"`
+	Offset      int64           `json:"offset"`
+	Message     *WALMessageInfo `json:"message"`
+	Round       int32           `json:"round"`
+	Step        string          `json:"step"`
+	LockedRound int32           `json:"lockedRound"`
+	BlockParts  int             `json:"blockParts"`
+}
+
+type walSummarizer struct {
+	height int64
+	step   step
+	rounds map[int32]*WALRoundSummary
+	votes  map[string]bool
+	res    *WALSummary
+}
+
+func (r *walSummarizer) roundFor(round int32) *WALRoundSummary {
+	rr, ok := r.rounds[round]
+	if !ok {
+		rr = &WALRoundSummary{Round: round}
+		r.rounds[round] = rr
+	}
+	return rr
+}
+
+func (r *walSummarizer) reach(round int32, s step) {
+	if r.res.Round < round || (r.res.Round == round && r.step < s) {
+		r.res.Round = round
+		r.step = s
+		r.res.Step = s.String()
+	}
+}
+
+func (r *walSummarizer) addVote(vote *WALMessageInfo, vt VoteType) {
+	if vote.Height != r.height {
+		return
+	}
+	key := fmt.Sprintf("%d/%d/%s", vote.Round, vt, vote.Validator)
+	if r.votes[key] {
+		return
+	}
+	r.votes[key] = true
+	rr := r.roundFor(vote.Round)
+	if vt == VoteTypePrevote {
+		rr.Prevotes = append(rr.Prevotes, vote)
+		r.reach(vote.Round, stepPrevote)
+	} else {
+		rr.Precommits = append(rr.Precommits, vote)
+		r.reach(vote.Round, stepPrecommit)
+	}
+}
+
+func (r *walSummarizer) apply(id string, rec *WALRecord) {
+	if rec.Sign != nil {
+		// sign guard writes a record only if it's after the last one
+		r.res.LastSigned = rec.Sign
+		return
+	}
+	switch m := rec.Message.(type) {
+	case *ProposalMessage:
+		if m.Height != r.height {
+			return
+		}
+		rr := r.roundFor(m.Round)
+		rr.Proposals = append(rr.Proposals, WALMessageInfoOf(m))
+		r.reach(m.Round, stepPropose)
+	case *VoteMessage:
+		r.addVote(voteInfoOf(m), m.Type)
+	case *VoteListMessage:
+		if m.VoteList.Len() == 0 {
+			return
+		}
+		for i := 0; i < m.VoteList.Len(); i++ {
+			vmsg := m.VoteList.Get(i)
+			r.addVote(voteInfoOf(vmsg), vmsg.Type)
+		}
+		vmsg := m.VoteList.Get(0)
+		if vmsg.Height != r.height {
+			return
+		}
+		switch id {
+		case configLockWALID:
+			if vmsg.Type == VoteTypePrevote && vmsg.BlockPartSetIDAndNTSVoteCount != nil {
+				r.roundFor(vmsg.Round).POL = true
+				if r.res.LockedRound < vmsg.Round {
+					r.res.LockedRound = vmsg.Round
+					r.res.BlockParts = 0
+				}
+			}
+		case configCommitWALID:
+			if vmsg.Type == VoteTypePrecommit {
+				r.roundFor(vmsg.Round).Commit = true
+				r.reach(vmsg.Round, stepCommit)
+			}
+		}
+	case *BlockPartMessage:
+		if m.Height == r.height && r.res.LockedRound >= 0 {
+			r.res.BlockParts++
+		}
+	}
+}
+
+// SummarizeWAL summarizes the consensus state of the height from the WALs
+// in dir in the same order as consensus applies them on start. If height is
+// not positive, it uses the highest height found in WAL.
+// As the validator list is not available, it doesn't count voting power
+// and reports every round and vote found in WAL. Use LoadTestWAL with
+// a test consensus to replay WALs with validators.
+func SummarizeWAL(dir string, height int64) (*WALSummary, error) {
+	return ReplayWAL(dir, height, nil)
+}
+
+// ReplayWAL applies the records of the WALs in dir to the consensus state of
+// the height as SummarizeWAL does, and it calls fn with the state after each
+// record of the height is applied. So it shows how the node reached the
+// state. It returns the summary of the height.
+func ReplayWAL(dir string, height int64, fn func(s *WALReplayStep) error) (*WALSummary, error) {
+	var records []*WALRecord
+	ids := make([]string, 0, len(WALIDs))
+	res := &WALSummary{
+		Height:      height,
+		Round:       0,
+		Step:        stepNewHeight.String(),
+		LockedRound: -1,
+	}
+	for _, id := range WALIDs {
+		err := ReadWALRecords(path.Join(dir, id), func(rec *WALRecord) error {
+			if rec.Error != nil {
+				res.Corrupted = append(res.Corrupted,
+					fmt.Sprintf("%s@%d: %v", rec.File, rec.Offset, rec.Error))
+				return nil
+			}
+			records = append(records, rec)
+			ids = append(ids, id)
+			if info := rec.Info(); height <= 0 && info.Height > res.Height {
+				res.Height = info.Height
+			}
+			return nil
+		})
+		if err != nil && !IsNotExist(err) {
+			return nil, err
+		}
+	}
+	r := &walSummarizer{
+		height: res.Height,
+		step:   stepNewHeight,
+		rounds: make(map[int32]*WALRoundSummary),
+		votes:  make(map[string]bool),
+		res:    res,
+	}
+	for i, rec := range records {
+		r.apply(ids[i], rec)
+		if fn == nil {
+			continue
+		}
+		if info := rec.Info(); info.Height == res.Height {
+			err := fn(&WALReplayStep{
+				WAL:         ids[i],
+				File:        rec.File,
+				Offset:      rec.Offset,
+				Message:     info,
+				Round:       res.Round,
+				Step:        res.Step,
+				LockedRound: res.LockedRound,
+				BlockParts:  res.BlockParts,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, rr := range r.rounds {
+		res.Rounds = append(res.Rounds, rr)
+	}
+	sort.Slice(res.Rounds, func(i, j int) bool {
+		return res.Rounds[i].Round < res.Rounds[j].Round
+	})
+	return res, nil
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/test"
+)
+
+func writeWALMessages(t *testing.T, id string, msgs ...consensus.Message) {
+	ww, err := consensus.OpenWALForWrite(id, &consensus.WALConfig{})
+	assert.NoError(t, err)
+	mww := consensus.WalMessageWriter{WALWriter: ww}
+	for _, msg := range msgs {
+		assert.NoError(t, mww.WriteMessage(msg))
+	}
+	assert.NoError(t, mww.Close())
+}
+
+func TestWAL_InspectAndReplay(t *testing.T) {
+	assert := assert.New(t)
+	f := test.NewFixture(t, test.AddDefaultNode(false), test.AddValidatorNodes(4))
+	defer f.Close()
+
+	nid := codec.MustMarshalToBytes(f.Chain.NID())
+	var votes []*consensus.VoteMessage
+	for i := 0; i < 3; i++ {
+		votes = append(votes, newSignedNilVote(f.Nodes[i].Chain.Wallet(), consensus.VoteTypePrevote, 1, 3, nid, 10))
+	}
+	vl := consensus.NewVoteList()
+	for _, v := range votes {
+		vl.AddVote(v)
+	}
+
+	dir := t.TempDir()
+	writeWALMessages(t, path.Join(dir, "round"), votes[0])
+	writeWALMessages(t, path.Join(dir, "lock"), &consensus.VoteListMessage{VoteList: vl})
+
+	// the sign WAL has the last signed record of the node
+	ww, err := consensus.OpenWALForWrite(path.Join(dir, "sign"), &consensus.WALConfig{})
+	assert.NoError(err)
+	_, err = ww.WriteBytes(codec.BC.MustMarshalToBytes([]interface{}{int64(1), int32(2), byte(1), []byte{0x01}}))
+	assert.NoError(err)
+	assert.NoError(ww.Close())
+	assert.Contains(consensus.WALIDs, "sign")
+
+	// append a frame with bad checksum
+	fd, err := os.OpenFile(path.Join(dir, "round_0"), os.O_WRONLY|os.O_APPEND, 0600)
+	assert.NoError(err)
+	_, err = fd.Write([]byte{0, 0, 0, 0, 0, 0, 0, 2, 1, 2})
+	assert.NoError(err)
+	assert.NoError(fd.Close())
+
+	var records []*consensus.WALRecord
+	err = consensus.ReadWALRecords(path.Join(dir, "round"), func(rec *consensus.WALRecord) error {
+		records = append(records, rec)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Len(records, 2)
+	assert.True(records[0].Valid)
+	assert.NoError(records[0].Error)
+	info := consensus.WALMessageInfoOf(records[0].Message)
+	assert.Equal("vote", info.Type)
+	assert.EqualValues(1, info.Height)
+	assert.EqualValues(3, info.Round)
+	assert.Equal(consensus.VoteTypePrevote.String(), info.VoteType)
+	assert.Equal(f.Nodes[0].Chain.Wallet().Address().String(), info.Validator)
+	assert.False(records[1].Valid)
+	assert.True(consensus.IsCorruptedWAL(records[1].Error))
+	assert.Nil(records[1].Info())
+
+	records = nil
+	err = consensus.ReadWALRecords(path.Join(dir, "sign"), func(rec *consensus.WALRecord) error {
+		records = append(records, rec)
+		return nil
+	})
+	assert.NoError(err)
+	if assert.Len(records, 1) {
+		info := records[0].Info()
+		assert.Equal("sign", info.Type)
+		assert.EqualValues(1, info.Height)
+		assert.EqualValues(2, info.Round)
+		assert.Equal("PreVote", info.Step)
+		assert.Equal("0x01", info.Digest)
+	}
+
+	res, err := consensus.SummarizeWAL(dir, 0)
+	assert.NoError(err)
+	assert.EqualValues(1, res.Height)
+	assert.EqualValues(3, res.Round)
+	assert.Equal("stepPrevote", res.Step)
+	assert.EqualValues(-1, res.LockedRound)
+	assert.Len(res.Corrupted, 1)
+	assert.Len(res.Rounds, 1)
+	assert.Len(res.Rounds[0].Prevotes, 3)
+	if assert.NotNil(res.LastSigned) {
+		assert.EqualValues(2, res.LastSigned.Round)
+	}
+
+	// replay shows the state after each record
+	var steps []*consensus.WALReplayStep
+	res2, err := consensus.ReplayWAL(dir, 0, func(s *consensus.WALReplayStep) error {
+		steps = append(steps, s)
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(res, res2)
+	if assert.Len(steps, 3) {
+		assert.Equal("round", steps[0].WAL)
+		assert.Equal("vote", steps[0].Message.Type)
+		assert.EqualValues(3, steps[0].Round)
+		assert.Equal("stepPrevote", steps[0].Step)
+		assert.Equal("lock", steps[1].WAL)
+		assert.Equal("voteList", steps[1].Message.Type)
+		assert.Equal("sign", steps[2].WAL)
+		assert.EqualValues(3, steps[2].Round)
+	}
+
+	// replay with a test consensus
+	wal, err := consensus.LoadTestWAL(dir)
+	assert.NoError(err)
+	nd := f.AddNode(test.UseGenesis(string(f.Chain.Genesis())), test.UseWAL(wal))
+	err = nd.CS.Start()
+	assert.NoError(err)
+	status := nd.CS.GetStatus()
+	assert.EqualValues(3, status.Round)
+}
@@ -35,6 +35,8 @@ module.exports = {
                     '/goloop_admin_api',
                     ['/goloop_cli', "Goloop CLI"],
                     ['/metric', "Metric"],
+                    ['/archive_format', "Archive Format"],
+                    ['/icsim_scenario', "Simulator Scenario"],
                 ]
             },
             //EndOfSidebar
@@ -682,7 +682,18 @@ var chainMethods = []*chainMethod{
 		[]scoreapi.DataType{
 			scoreapi.Bytes,
 		},
-	}, icmodule.RevisionBTP2, 0},
+	}, icmodule.RevisionBTP2, icmodule.Revision21},
+	{scoreapi.Method{
+		scoreapi.Function, "getPRepNodePublicKey",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"dsa", scoreapi.String, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Bytes,
+		},
+	}, icmodule.RevisionPRepNodeKeyForDSA, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "setPRepNodePublicKey",
 		scoreapi.FlagExternal, 1,
@@ -690,7 +701,16 @@ var chainMethods = []*chainMethod{
 			{"pubKey", scoreapi.Bytes, nil, nil},
 		},
 		nil,
-	}, icmodule.RevisionBTP2, 0},
+	}, icmodule.RevisionBTP2, icmodule.Revision21},
+	{scoreapi.Method{
+		scoreapi.Function, "setPRepNodePublicKey",
+		scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"pubKey", scoreapi.Bytes, nil, nil},
+			{"dsa", scoreapi.String, nil, nil},
+		},
+		nil,
+	}, icmodule.RevisionPRepNodeKeyForDSA, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "registerPRepNodePublicKey",
 		scoreapi.FlagExternal, 2,
@@ -729,6 +749,39 @@ var chainMethods = []*chainMethod{
 		},
 		nil,
 	}, icmodule.RevisionBTP2, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "estimateReward",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionEstimateReward, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getPRepStatsOfTerm",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"sequence", scoreapi.Integer, nil, nil},
+			{"address", scoreapi.Address, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionPRepHistory, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getPenaltyHistory",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 1,
+		[]scoreapi.Parameter{
+			{"address", scoreapi.Address, nil, nil},
+			{"start", scoreapi.Integer, nil, nil},
+			{"end", scoreapi.Integer, nil, nil},
+		},
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, icmodule.RevisionPRepHistory, 0},
 }
 
 func applyStepLimits(fee *FeeConfig, as state.AccountState) error {
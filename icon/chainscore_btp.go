@@ -17,6 +17,7 @@
 package icon
 
 import (
+	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/intconv"
@@ -50,7 +51,7 @@ func (s *chainScore) Ex_getBTPNetworkTypeID(name string) (int64, error) {
 
 const iconDSA = "ecdsa/secp256k1"
 
-func (s *chainScore) Ex_getPRepNodePublicKey(address module.Address) ([]byte, error) {
+func (s *chainScore) Ex_getPRepNodePublicKey(address module.Address, dsa string) ([]byte, error) {
 	if err := s.tryChargeCall(false); err != nil {
 		return nil, err
 	}
@@ -63,13 +64,63 @@ func (s *chainScore) Ex_getPRepNodePublicKey(address module.Address) ([]byte, er
 		return nil, icmodule.IllegalArgumentError.New("address is not P-Rep")
 	}
 
-	return s.newBTPContext().GetPublicKey(prep.NodeAddress(), iconDSA), nil
+	if dsa == "" {
+		dsa = iconDSA
+	}
+	return s.newBTPContext().GetPublicKey(prep.NodeAddress(), dsa), nil
 }
 
-func (s *chainScore) Ex_setPRepNodePublicKey(pubKey []byte) error {
+func (s *chainScore) Ex_setPRepNodePublicKey(pubKey []byte, dsa string) error {
+	if dsa != "" && dsa != iconDSA {
+		return s.setPRepNodePublicKeyForDSA(dsa, pubKey)
+	}
 	return s.setPRepNodePublicKey(nil, pubKey)
 }
 
+// setPRepNodePublicKeyForDSA sets the public key of the node of the P-Rep for
+// the DSA other than iconDSA. The node address is not derived from the key,
+// so the key is bound to the current node address of the P-Rep.
+func (s *chainScore) setPRepNodePublicKeyForDSA(dsa string, pubKey []byte) error {
+	if err := s.tryChargeCall(false); err != nil {
+		return err
+	}
+	if s.from.IsContract() {
+		return scoreresult.New(module.StatusAccessDenied, "NoPermission")
+	}
+	if s.cc.Revision().Value() < icmodule.RevisionPRepNodeKeyForDSA {
+		return icmodule.IllegalArgumentError.Errorf("Not supported dsa %s", dsa)
+	}
+	if len(pubKey) == 0 {
+		return icmodule.IllegalArgumentError.New("Invalid pubKey")
+	}
+	if ntm.DSAModuleForName(dsa) == nil {
+		return icmodule.IllegalArgumentError.Errorf("Invalid dsa %s", dsa)
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return err
+	}
+	prep := es.GetPRep(s.from)
+	if prep == nil {
+		return icmodule.IllegalArgumentError.New("address is not P-Rep")
+	}
+	nodeAddress := prep.NodeAddress()
+
+	bc := s.newBTPContext()
+	bs, err := s.getBTPState()
+	if err != nil {
+		return err
+	}
+	if err = bs.SetPublicKey(bc, nodeAddress, dsa, pubKey); err != nil {
+		return err
+	}
+	prep.SetDSAMask(bc.GetPublicKeyMask(nodeAddress))
+	if err = es.OnSetPublicKey(s.newCallContext(s.cc), prep.Owner(), bc.GetDSAIndex(dsa)); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *chainScore) Ex_registerPRepNodePublicKey(address module.Address, pubKey []byte) error {
 	return s.setPRepNodePublicKey(address, pubKey)
 }
@@ -143,10 +194,20 @@ func (s *chainScore) setPRepNodePublicKey(address module.Address, pubKey []byte)
 	return nil
 }
 
+// btpNetworkTypeRevisions has revisions enabling network types added after
+// RevisionBTP2.
+var btpNetworkTypeRevisions = map[string]int{
+	"bls":     icmodule.RevisionBLSNetworkType,
+	"ed25519": icmodule.RevisionEd25519NetworkType,
+}
+
 func (s *chainScore) Ex_openBTPNetwork(networkTypeName string, name string, owner module.Address) (int64, error) {
 	if err := s.checkGovernance(true); err != nil {
 		return 0, err
 	}
+	if rev, ok := btpNetworkTypeRevisions[networkTypeName]; ok && s.cc.Revision().Value() < rev {
+		return 0, scoreresult.InvalidParameterError.Errorf("Not supported BTP network type %s", networkTypeName)
+	}
 	if bs, err := s.getBTPState(); err != nil {
 		return 0, err
 	} else {
@@ -463,6 +463,27 @@ func (s *chainScore) Ex_queryIScore(address module.Address) (map[string]interfac
 	return jso, nil
 }
 
+func (s *chainScore) Ex_estimateReward(address module.Address) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	jso, reads, err := es.EstimateReward(address, s.cc.BlockHeight())
+	if err != nil {
+		return nil, err
+	}
+	if !s.gov && (s.flags&SysNoCharge) == 0 {
+		if !s.cc.ApplySteps(state.StepTypeGetBase, reads) {
+			return nil, scoreresult.OutOfStepError.New("OutOfStepFor(estimateReward)")
+		}
+	}
+	jso["blockHeight"] = s.cc.BlockHeight()
+	return jso, nil
+}
+
 func (s *chainScore) Ex_estimateUnstakeLockPeriod() (map[string]interface{}, error) {
 	if err := s.tryChargeCall(true); err != nil {
 		return nil, err
@@ -560,6 +581,63 @@ func (s *chainScore) Ex_getPRepStats() (map[string]interface{}, error) {
 	return es.State.GetPRepStatsInJSON(s.cc.BlockHeight())
 }
 
+func (s *chainScore) Ex_getPRepStatsOfTerm(sequence *common.HexInt, address module.Address) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	if !sequence.IsInt64() || sequence.Sign() < 0 {
+		return nil, scoreresult.InvalidParameterError.Errorf("InvalidSequence(%s)", sequence)
+	}
+	blockHeight := s.cc.BlockHeight()
+	jso, err := es.State.GetPRepTermStatsInJSON(int(sequence.Int64()), address, blockHeight)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, scoreresult.InvalidParameterError.Wrap(err, "NoPRepStatsOfTerm")
+		}
+		return nil, err
+	}
+	jso["blockHeight"] = blockHeight
+	return jso, nil
+}
+
+func (s *chainScore) Ex_getPenaltyHistory(address module.Address, start, end *common.HexInt) (map[string]interface{}, error) {
+	if err := s.tryChargeCall(true); err != nil {
+		return nil, err
+	}
+	es, err := s.getExtensionState()
+	if err != nil {
+		return nil, err
+	}
+	if es.State.GetPRepStatusByOwner(address, false) == nil {
+		return nil, scoreresult.InvalidParameterError.Errorf("NotPRep(%s)", address)
+	}
+	var from, to int
+	if start != nil {
+		if !start.IsInt64() {
+			return nil, scoreresult.InvalidParameterError.Errorf("InvalidStart(%s)", start)
+		}
+		from = int(start.Int64())
+	}
+	if end != nil {
+		if !end.IsInt64() {
+			return nil, scoreresult.InvalidParameterError.Errorf("InvalidEnd(%s)", end)
+		}
+		to = int(end.Int64())
+	}
+	jso, err := es.State.GetPenaltyHistoryInJSON(address, from, to)
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrapf(
+			err, "Failed to get penalty history: start=%d end=%d", from, to,
+		)
+	}
+	jso["blockHeight"] = s.cc.BlockHeight()
+	return jso, nil
+}
+
 func (s *chainScore) Ex_disqualifyPRep(address module.Address) error {
 	if err := s.checkGovernance(true); err != nil {
 		return err
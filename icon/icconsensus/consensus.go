@@ -23,6 +23,7 @@ import (
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/consensus/fastsync"
 	"github.com/icon-project/goloop/icon/blockv0"
 	"github.com/icon-project/goloop/icon/icdb"
 	"github.com/icon-project/goloop/icon/merkle/hexary"
@@ -99,6 +100,16 @@ func (c *wrapper) GetStatus() *module.ConsensusStatus {
 	return c.Consensus.GetStatus()
 }
 
+func (c *wrapper) FastSyncStats() *fastsync.FetchStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if fi, ok := c.Consensus.(consensus.FastSyncInspector); ok {
+		return fi.FastSyncStats()
+	}
+	return nil
+}
+
 func (c *wrapper) GetVotesByHeight(height int64) (module.CommitVoteSet, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -94,6 +94,7 @@ func (f *fastSyncer) Start() error {
 		return err
 	}
 	f.fsm = fsm
+	f.fsm.SetWindow(f.c.FastSyncWindow())
 	f.fsm.StartServer()
 	canceler, err := f.fsm.FetchBlocks(f.height, f.to, f)
 	if err != nil {
@@ -122,6 +123,16 @@ func (f *fastSyncer) Term() {
 	f.running = false
 }
 
+func (f *fastSyncer) FastSyncStats() *fastsync.FetchStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.fsm == nil {
+		return nil
+	}
+	return f.fsm.Stats()
+}
+
 func (f *fastSyncer) GetStatus() *module.ConsensusStatus {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -40,6 +40,7 @@ const (
 	Revision19
 	Revision20
 	Revision21
+	Revision22
 	RevisionReserved
 )
 
@@ -105,6 +106,12 @@ const (
 	// RevisionJavaFixMapValues = Revision20
 
 	RevisionBTP2 = Revision21
+
+	RevisionPRepHistory        = Revision22
+	RevisionEstimateReward     = Revision22
+	RevisionBLSNetworkType     = Revision22
+	RevisionEd25519NetworkType = Revision22
+	RevisionPRepNodeKeyForDSA  = Revision22
 )
 
 var revisionFlags = []module.Revision{
@@ -152,6 +159,8 @@ var revisionFlags = []module.Revision{
 	module.FixMapValues,
 	// Revision21
 	module.MultipleFeePayers,
+	// Revision22
+	0,
 }
 
 func init() {
@@ -3,32 +3,32 @@ package icsim
 import "github.com/icon-project/goloop/icon/icmodule"
 
 type RewardFund struct {
-	Iglobal int64
-	Iprep   int64
-	Icps    int64
-	Irelay  int64
-	Ivoter  int64
+	Iglobal int64 `yaml:"iglobal"`
+	Iprep   int64 `yaml:"iprep"`
+	Icps    int64 `yaml:"icps"`
+	Irelay  int64 `yaml:"irelay"`
+	Ivoter  int64 `yaml:"ivoter"`
 }
 
 type config struct {
-	TermPeriod                            int64
-	MainPRepCount                         int64
-	SubPRepCount                          int64
-	Irep                                  int64
-	Rrep                                  int64
-	BondRequirement                       int64
-	UnbondingPeriodMultiplier             int64
-	UnstakeSlotMax                        int64
-	LockMinMultiplier                     int64
-	LockMaxMultiplier                     int64
-	UnbondingMax                          int64
-	ValidationPenaltyCondition            int
-	ConsistentValidationPenaltyCondition  int64
-	ConsistentValidationPenaltyMask       int64
-	ConsistentValidationPenaltySlashRatio int
-	DelegationSlotMax                     int64
-	RewardFund
-	BondedPRepCount int
+	TermPeriod                            int64 `yaml:"termPeriod"`
+	MainPRepCount                         int64 `yaml:"mainPRepCount"`
+	SubPRepCount                          int64 `yaml:"subPRepCount"`
+	Irep                                  int64 `yaml:"irep"`
+	Rrep                                  int64 `yaml:"rrep"`
+	BondRequirement                       int64 `yaml:"bondRequirement"`
+	UnbondingPeriodMultiplier             int64 `yaml:"unbondingPeriodMultiplier"`
+	UnstakeSlotMax                        int64 `yaml:"unstakeSlotMax"`
+	LockMinMultiplier                     int64 `yaml:"lockMinMultiplier"`
+	LockMaxMultiplier                     int64 `yaml:"lockMaxMultiplier"`
+	UnbondingMax                          int64 `yaml:"unbondingMax"`
+	ValidationPenaltyCondition            int   `yaml:"validationPenaltyCondition"`
+	ConsistentValidationPenaltyCondition  int64 `yaml:"consistentValidationPenaltyCondition"`
+	ConsistentValidationPenaltyMask       int64 `yaml:"consistentValidationPenaltyMask"`
+	ConsistentValidationPenaltySlashRatio int   `yaml:"consistentValidationPenaltySlashRatio"`
+	DelegationSlotMax                     int64 `yaml:"delegationSlotMax"`
+	RewardFund                            `yaml:"rewardFund"`
+	BondedPRepCount                       int `yaml:"bondedPRepCount"`
 }
 
 func NewConfig() *config {
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestSimulator_PRepHistory(t *testing.T) {
+	const (
+		termPeriod                 = 100
+		mainPRepCount              = 22
+		validationPenaltyCondition = 5
+	)
+
+	c := NewConfig()
+	c.MainPRepCount = mainPRepCount
+	c.TermPeriod = termPeriod
+	c.ValidationPenaltyCondition = validationPenaltyCondition
+	// PReps without power can't be elected since RevisionBTP2
+	c.BondedPRepCount = int(c.MainPRepCount + c.SubPRepCount)
+
+	env := initEnv(t, c, icmodule.Revision13)
+	sim := env.sim
+
+	// Stats of the term are recorded from the term after the revision change
+	_, err := sim.GoByTransaction(sim.SetRevision(icmodule.ValueToRevision(icmodule.RevisionPRepHistory)), nil)
+	assert.NoError(t, err)
+	seq := sim.TermSnapshot().Sequence()
+	assert.Nil(t, sim.GetPRepStatsOfTerm(seq, nil))
+	assert.NoError(t, sim.GoToTermEnd(nil))
+
+	term := sim.TermSnapshot()
+	seq = term.Sequence()
+	jso := sim.GetPRepStatsOfTerm(seq, nil)
+	assert.NotNil(t, jso)
+	assert.Equal(t, term.StartHeight(), jso["startBlockHeight"])
+	assert.Equal(t, term.GetEndHeight(), jso["endBlockHeight"])
+	assert.Equal(t, len(env.preps), len(jso["preps"].([]interface{})))
+	assert.NoError(t, sim.GoToTermEnd(nil))
+
+	// prep0 fails to vote for the blocks until it's penalized
+	vl := sim.ValidatorList()
+	voted := make([]bool, len(vl))
+	for i := range voted {
+		voted[i] = true
+	}
+	voted[0] = false
+	seq = sim.TermSnapshot().Sequence()
+	csi := newConsensusInfo(sim.Database(), vl, voted)
+	assert.NoError(t, sim.Go(validationPenaltyCondition, csi))
+	penalizedAt := sim.BlockHeight()
+
+	stats := sim.GetPRepStatsOfTerm(seq, env.preps[0])
+	preps := stats["preps"].([]interface{})
+	assert.Equal(t, 1, len(preps))
+	entry := preps[0].(map[string]interface{})
+	assert.Equal(t, int64(validationPenaltyCondition), entry["fail"])
+	assert.Equal(t, 1, entry["penalties"])
+
+	history := sim.GetPenaltyHistory(env.preps[0])
+	penalties := history["penalties"].([]interface{})
+	assert.Equal(t, 1, len(penalties))
+	penalty := penalties[0].(map[string]interface{})
+	assert.Equal(t, penalizedAt, penalty["blockHeight"])
+	assert.Equal(t, int(icmodule.PenaltyBlockValidation), penalty["type"])
+	assert.Zero(t, penalty["slashed"].(*big.Int).Sign())
+	assert.Zero(t, len(sim.GetPenaltyHistory(env.preps[1])["penalties"].([]interface{})))
+
+	// Counters are fixed at the end of the term
+	voted[0] = true
+	csi = newConsensusInfo(sim.Database(), sim.ValidatorList(), voted)
+	assert.NoError(t, sim.GoToTermEnd(csi))
+	assert.NoError(t, sim.Go(2, csi))
+	stats = sim.GetPRepStatsOfTerm(seq, nil)
+	for _, e := range stats["preps"].([]interface{}) {
+		entry = e.(map[string]interface{})
+		owner := entry["address"].(module.Address)
+		if owner.Equal(env.preps[0]) {
+			assert.Equal(t, int64(validationPenaltyCondition), entry["fail"])
+			assert.Equal(t, 1, entry["penalties"])
+		} else {
+			assert.Zero(t, entry["fail"])
+			assert.Zero(t, entry["penalties"])
+		}
+	}
+
+	assert.Nil(t, sim.GetPRepStatsOfTerm(seq+2, nil))
+	assert.Nil(t, sim.GetPRepStatsOfTerm(seq, env.users[0]))
+}
@@ -0,0 +1,554 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// Scenario describes accounts, actions and expectations for the simulator.
+// Refer doc/icsim_scenario.md for the format.
+type Scenario struct {
+	Revision   int               `yaml:"revision"`
+	Validators int               `yaml:"validators"`
+	Config     *config           `yaml:"config"`
+	Accounts   []ScenarioAccount `yaml:"accounts"`
+	Steps      []ScenarioStep    `yaml:"steps"`
+}
+
+type ScenarioAccount struct {
+	Name    string `yaml:"name"`
+	Balance string `yaml:"balance"`
+}
+
+// ScenarioStep is an action with its arguments. The arguments are decoded
+// on running, depending on the action.
+type ScenarioStep struct {
+	Action string
+	Line   int
+	args   *yaml.Node
+}
+
+func (s *ScenarioStep) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode || len(value.Content) != 2 {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidStep(line=%d): a step shall have only one action", value.Line)
+	}
+	s.Action = value.Content[0].Value
+	s.Line = value.Line
+	s.args = value.Content[1]
+	return nil
+}
+
+func (s *ScenarioStep) decode(v interface{}) error {
+	if s.args.Kind == yaml.ScalarNode && s.args.Tag == "!!null" {
+		return nil
+	}
+	if err := s.args.Decode(v); err != nil {
+		return errors.IllegalArgumentError.Wrapf(err,
+			"InvalidArguments(line=%d,action=%s)", s.Line, s.Action)
+	}
+	return nil
+}
+
+type scenarioTx struct {
+	From        string            `yaml:"from"`
+	Amount      string            `yaml:"amount"`
+	Delegations map[string]string `yaml:"delegations"`
+	Bonds       map[string]string `yaml:"bonds"`
+	Bonders     []string          `yaml:"bonders"`
+	Address     string            `yaml:"address"`
+	Revision    int               `yaml:"revision"`
+	Fail        bool              `yaml:"fail"`
+}
+
+type scenarioGo struct {
+	Blocks int64    `yaml:"blocks"`
+	Height int64    `yaml:"height"`
+	Missed []string `yaml:"missed"`
+}
+
+type scenarioExpect struct {
+	Balance     map[string]string            `yaml:"balance"`
+	Stake       map[string]string            `yaml:"stake"`
+	IScore      map[string]string            `yaml:"iscore"`
+	PRepStats   map[string]map[string]string `yaml:"prepStats"`
+	Validators  []string                     `yaml:"validators"`
+	TotalSupply string                       `yaml:"totalSupply"`
+}
+
+func ParseScenario(bs []byte) (*Scenario, error) {
+	s := &Scenario{
+		Revision: icmodule.RevisionIISS,
+		Config:   NewConfig(),
+	}
+	if err := yaml.Unmarshal(bs, s); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidScenario")
+	}
+	if s.Validators == 0 {
+		s.Validators = int(s.Config.MainPRepCount)
+	}
+	return s, nil
+}
+
+func LoadScenario(file string) (*Scenario, error) {
+	bs, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScenario(bs)
+}
+
+// parseAmount parses an amount in loop. The amount with "icx" suffix is
+// in ICX.
+func parseAmount(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	icx := strings.HasSuffix(strings.ToLower(s), "icx")
+	if icx {
+		s = strings.TrimSpace(s[:len(s)-3])
+	}
+	v := new(big.Int)
+	if err := intconv.ParseBigInt(v, s); err != nil {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidAmount(%q)", s)
+	}
+	if icx {
+		v.Mul(v, icmodule.BigIntICX)
+	}
+	return v, nil
+}
+
+// matchValue checks whether the value matches the expression, which is
+// an amount with an optional comparison operator, e.g. ">=10icx".
+func matchValue(expr string, v *big.Int) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	op := "=="
+	for _, o := range []string{">=", "<=", "!=", "==", ">", "<"} {
+		if strings.HasPrefix(expr, o) {
+			op = o
+			expr = expr[len(o):]
+			break
+		}
+	}
+	exp, err := parseAmount(expr)
+	if err != nil {
+		return false, err
+	}
+	c := v.Cmp(exp)
+	switch op {
+	case ">=":
+		return c >= 0, nil
+	case "<=":
+		return c <= 0, nil
+	case "!=":
+		return c != 0, nil
+	case ">":
+		return c > 0, nil
+	case "<":
+		return c < 0, nil
+	default:
+		return c == 0, nil
+	}
+}
+
+func toBigInt(v interface{}) (*big.Int, bool) {
+	switch o := v.(type) {
+	case *big.Int:
+		return o, true
+	case int:
+		return big.NewInt(int64(o)), true
+	case int64:
+		return big.NewInt(o), true
+	default:
+		return nil, false
+	}
+}
+
+type scenarioRunner struct {
+	s        *Scenario
+	sim      Simulator
+	w        io.Writer
+	accounts map[string]module.Address
+	names    map[string]string
+	voters   []module.Validator
+	failures int
+}
+
+// addressOf returns the address of the account. Address of the account
+// which is not in the scenario is derived from its name.
+func (r *scenarioRunner) addressOf(name string) module.Address {
+	if addr, ok := r.accounts[name]; ok {
+		return addr
+	}
+	if addr, err := common.NewAddressFromString(name); err == nil {
+		return addr
+	}
+	addr := common.NewAccountAddress(crypto.SHA3Sum256([]byte(name))[:common.AddressIDBytes])
+	r.accounts[name] = addr
+	r.names[icutils.ToKey(addr)] = name
+	return addr
+}
+
+func (r *scenarioRunner) nameOf(addr module.Address) string {
+	if name, ok := r.names[icutils.ToKey(addr)]; ok {
+		return name
+	}
+	return addr.String()
+}
+
+func (r *scenarioRunner) init() error {
+	s := r.s
+	balances := make(map[string]*big.Int)
+	for _, a := range s.Accounts {
+		if len(a.Name) == 0 {
+			return errors.IllegalArgumentError.New("NoAccountName")
+		}
+		balance, err := parseAmount(a.Balance)
+		if err != nil {
+			return err
+		}
+		balances[icutils.ToKey(r.addressOf(a.Name))] = balance
+	}
+	validators := make([]module.Validator, s.Validators)
+	for i := range validators {
+		v, err := state.ValidatorFromAddress(newDummyAddress(4000 + i))
+		if err != nil {
+			return err
+		}
+		validators[i] = v
+	}
+	r.sim = NewSimulator(icmodule.ValueToRevision(s.Revision), validators, balances, s.Config)
+	if r.sim == nil {
+		return errors.InvalidStateError.New("FailToInitSimulator")
+	}
+	return nil
+}
+
+func (r *scenarioRunner) newPRepInfo(name string) *icstate.PRepInfo {
+	city := "Seoul"
+	country := "KOR"
+	email := fmt.Sprintf("%s@email.com", name)
+	website := fmt.Sprintf("https://%s.example.com/", name)
+	details := fmt.Sprintf("%sdetails/", website)
+	endpoint := fmt.Sprintf("%s.example.com:9080", name)
+	return &icstate.PRepInfo{
+		City:        &city,
+		Country:     &country,
+		Name:        &name,
+		Email:       &email,
+		WebSite:     &website,
+		Details:     &details,
+		P2PEndpoint: &endpoint,
+	}
+}
+
+func (r *scenarioRunner) votesOf(m map[string]string) ([]*common.Address, []*big.Int, error) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	addrs := make([]*common.Address, len(names))
+	amounts := make([]*big.Int, len(names))
+	for i, name := range names {
+		amount, err := parseAmount(m[name])
+		if err != nil {
+			return nil, nil, err
+		}
+		addrs[i] = common.AddressToPtr(r.addressOf(name))
+		amounts[i] = amount
+	}
+	return addrs, amounts, nil
+}
+
+func (r *scenarioRunner) txOf(step *ScenarioStep) (Transaction, bool, error) {
+	var tx scenarioTx
+	if err := step.decode(&tx); err != nil {
+		return nil, false, err
+	}
+	sim := r.sim
+	from := r.addressOf(tx.From)
+	switch step.Action {
+	case "setStake":
+		amount, err := parseAmount(tx.Amount)
+		if err != nil {
+			return nil, false, err
+		}
+		return sim.SetStake(from, amount), tx.Fail, nil
+	case "setDelegation":
+		addrs, amounts, err := r.votesOf(tx.Delegations)
+		if err != nil {
+			return nil, false, err
+		}
+		ds := make(icstate.Delegations, len(addrs))
+		for i := range addrs {
+			ds[i] = icstate.NewDelegation(addrs[i], amounts[i])
+		}
+		return sim.SetDelegation(from, ds), tx.Fail, nil
+	case "setBond":
+		addrs, amounts, err := r.votesOf(tx.Bonds)
+		if err != nil {
+			return nil, false, err
+		}
+		bonds := make(icstate.Bonds, len(addrs))
+		for i := range addrs {
+			bonds[i] = icstate.NewBond(addrs[i], amounts[i])
+		}
+		return sim.SetBond(from, bonds), tx.Fail, nil
+	case "setBonderList":
+		bl := make(icstate.BonderList, len(tx.Bonders))
+		for i, name := range tx.Bonders {
+			bl[i] = common.AddressToPtr(r.addressOf(name))
+		}
+		return sim.SetBonderList(from, bl), tx.Fail, nil
+	case "registerPRep":
+		return sim.RegisterPRep(from, r.newPRepInfo(tx.From)), tx.Fail, nil
+	case "unregisterPRep":
+		return sim.UnregisterPRep(from), tx.Fail, nil
+	case "disqualifyPRep":
+		return sim.DisqualifyPRep(state.SystemAddress, r.addressOf(tx.Address)), tx.Fail, nil
+	case "setRevision":
+		return sim.SetRevision(icmodule.ValueToRevision(tx.Revision)), tx.Fail, nil
+	case "claimIScore":
+		return sim.ClaimIScore(from), tx.Fail, nil
+	default:
+		return nil, false, errors.IllegalArgumentError.Errorf(
+			"UnknownAction(line=%d,action=%s)", step.Line, step.Action)
+	}
+}
+
+func (r *scenarioRunner) runBlock(steps []*ScenarioStep) error {
+	block := NewBlock()
+	fails := make([]bool, len(steps))
+	for i, step := range steps {
+		tx, fail, err := r.txOf(step)
+		if err != nil {
+			return err
+		}
+		block.AddTransaction(tx)
+		fails[i] = fail
+	}
+	receipts, err := r.sim.GoByBlock(block, r.consensusInfo(nil))
+	if err != nil {
+		return err
+	}
+	for i, rct := range receipts {
+		if fails[i] != (rct.Status() == Failure) {
+			return errors.InvalidStateError.Errorf(
+				"UnexpectedResult(line=%d,action=%s,fail=%t,err=%v)",
+				steps[i].Line, steps[i].Action, fails[i], rct.Error())
+		}
+	}
+	return nil
+}
+
+// consensusInfo returns the consensus information for the next block.
+// It has votes for the previous block by its validators, and validators
+// in missed don't vote.
+func (r *scenarioRunner) consensusInfo(missed []string) module.ConsensusInfo {
+	vl := r.voters
+	r.voters = r.sim.ValidatorList()
+	if len(vl) == 0 {
+		return nil
+	}
+	vss, err := state.ValidatorSnapshotFromSlice(r.sim.Database(), vl)
+	if err != nil {
+		return nil
+	}
+	voted := make([]bool, len(vl))
+	for i, v := range vl {
+		voted[i] = true
+		for _, name := range missed {
+			if v.Address().Equal(r.addressOf(name)) {
+				voted[i] = false
+			}
+		}
+	}
+	return common.NewConsensusInfo(vl[len(vl)-1].Address(), vss, voted)
+}
+
+func (r *scenarioRunner) runGo(step *ScenarioStep) error {
+	var g scenarioGo
+	if err := step.decode(&g); err != nil {
+		return err
+	}
+	var height int64
+	switch step.Action {
+	case "go":
+		height = r.sim.BlockHeight() + g.Blocks
+	case "goTo":
+		height = g.Height
+	case "goToTermEnd":
+		height = r.sim.TermSnapshot().GetEndHeight()
+	}
+	if height < r.sim.BlockHeight() {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(line=%d,height=%d,current=%d)", step.Line, height, r.sim.BlockHeight())
+	}
+	// validators may change on every block
+	for r.sim.BlockHeight() < height {
+		if err := r.sim.Go(1, r.consensusInfo(g.Missed)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *scenarioRunner) check(step *ScenarioStep, item string, ok bool, err error, actual interface{}) {
+	if err != nil {
+		r.failures += 1
+		fmt.Fprintf(r.w, "  FAIL line=%d %s: %v\n", step.Line, item, err)
+	} else if !ok {
+		r.failures += 1
+		fmt.Fprintf(r.w, "  FAIL line=%d %s: actual=%v\n", step.Line, item, actual)
+	}
+}
+
+func (r *scenarioRunner) checkValues(step *ScenarioStep, kind string, exps map[string]string, get func(addr module.Address) *big.Int) {
+	names := make([]string, 0, len(exps))
+	for name := range exps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := get(r.addressOf(name))
+		ok, err := matchValue(exps[name], v)
+		r.check(step, fmt.Sprintf("%s[%s] %s", kind, name, exps[name]), ok, err, v)
+	}
+}
+
+func (r *scenarioRunner) runExpect(step *ScenarioStep) error {
+	var e scenarioExpect
+	if err := step.decode(&e); err != nil {
+		return err
+	}
+	sim := r.sim
+	r.checkValues(step, "balance", e.Balance, sim.GetBalance)
+	r.checkValues(step, "stake", e.Stake, func(addr module.Address) *big.Int {
+		v, _ := toBigInt(sim.GetStake(addr)["stake"])
+		return v
+	})
+	r.checkValues(step, "iscore", e.IScore, sim.QueryIScore)
+
+	names := make([]string, 0, len(e.PRepStats))
+	for name := range e.PRepStats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addr := r.addressOf(name)
+		if sim.GetPRep(addr) == nil {
+			r.check(step, fmt.Sprintf("prepStats[%s]", name), false, errors.New("NotPRep"), nil)
+			continue
+		}
+		stats := sim.GetPRepStats(addr)
+		exps := e.PRepStats[name]
+		keys := make([]string, 0, len(exps))
+		for key := range exps {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			item := fmt.Sprintf("prepStats[%s].%s %s", name, key, exps[key])
+			v, ok := toBigInt(stats[key])
+			if !ok {
+				r.check(step, item, false, errors.Errorf("UnknownKey(%s)", key), nil)
+				continue
+			}
+			ok, err := matchValue(exps[key], v)
+			r.check(step, item, ok, err, v)
+		}
+	}
+
+	if e.Validators != nil {
+		vl := sim.ValidatorList()
+		actual := make([]string, len(vl))
+		for i, v := range vl {
+			actual[i] = r.nameOf(v.Address())
+		}
+		ok := len(actual) == len(e.Validators)
+		for i := 0; ok && i < len(actual); i++ {
+			ok = r.addressOf(e.Validators[i]).Equal(vl[i].Address())
+		}
+		r.check(step, "validators", ok, nil, actual)
+	}
+	if len(e.TotalSupply) > 0 {
+		ts := sim.TotalSupply()
+		ok, err := matchValue(e.TotalSupply, ts)
+		r.check(step, "totalSupply "+e.TotalSupply, ok, err, ts)
+	}
+	return nil
+}
+
+func (r *scenarioRunner) runStep(step *ScenarioStep) error {
+	switch step.Action {
+	case "block":
+		var steps []*ScenarioStep
+		if err := step.decode(&steps); err != nil {
+			return err
+		}
+		return r.runBlock(steps)
+	case "go", "goTo", "goToTermEnd":
+		return r.runGo(step)
+	case "expect":
+		return r.runExpect(step)
+	default:
+		return r.runBlock([]*ScenarioStep{step})
+	}
+}
+
+// Run runs steps of the scenario and writes the progress to w. It stops on
+// the first step failed to run, but it runs all the expectations and
+// returns an error if any of them isn't met.
+func (s *Scenario) Run(w io.Writer) error {
+	r := &scenarioRunner{
+		s:        s,
+		w:        w,
+		accounts: make(map[string]module.Address),
+		names:    make(map[string]string),
+	}
+	if err := r.init(); err != nil {
+		return err
+	}
+	for i := range s.Steps {
+		step := &s.Steps[i]
+		fmt.Fprintf(w, "[%d] line=%d %s\n", r.sim.BlockHeight(), step.Line, step.Action)
+		if err := r.runStep(step); err != nil {
+			return err
+		}
+	}
+	if r.failures > 0 {
+		return errors.Errorf("%d expectations failed", r.failures)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icsim
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testScenario = `
+revision: 13
+config:
+  termPeriod: 10
+  mainPRepCount: 4
+  subPRepCount: 0
+  validationPenaltyCondition: 3
+accounts:
+  - {name: prep1, balance: 3000icx}
+  - {name: prep2, balance: 3000icx}
+  - {name: prep3, balance: 3000icx}
+  - {name: prep4, balance: 3000icx}
+  - {name: user, balance: 1000000icx}
+steps:
+  - block:
+      - registerPRep: {from: prep1}
+      - registerPRep: {from: prep2}
+      - registerPRep: {from: prep3}
+      - registerPRep: {from: prep4}
+      - registerPRep: {from: poor, fail: true}
+  - setStake: {from: user, amount: 900000icx}
+  - setDelegation: {from: user, delegations: {prep1: 900001icx}, fail: true}
+  - setDelegation:
+      from: user
+      delegations: {prep1: 400000icx, prep2: 250000icx, prep3: 150000icx, prep4: 100000icx}
+  - expect:
+      balance: {prep1: 1000icx, poor: 0}
+      stake: {user: 900000icx}
+  - goToTermEnd:
+  - goToTermEnd:
+  - expect:
+      validators: [prep1, prep2, prep3, prep4]
+      prepStats:
+        prep1: {grade: 0, penalties: 0}
+  - goToTermEnd:
+  - go: {blocks: 4, missed: [prep1]}
+  - expect:
+      validators: [prep2, prep3, prep4]
+      prepStats:
+        prep1: {grade: 2, penalties: 1, realFail: ">=3"}
+        prep2: {grade: 0, realFail: 0}
+      iscore: {poor: 0}
+      totalSupply: "<1012000icx"
+`
+
+func TestScenario_Run(t *testing.T) {
+	s, err := ParseScenario([]byte(testScenario))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), s.Config.TermPeriod)
+	assert.Equal(t, 4, s.Validators)
+
+	buf := bytes.NewBuffer(nil)
+	err = s.Run(buf)
+	assert.NoError(t, err, buf.String())
+}
+
+func TestScenario_RunFailure(t *testing.T) {
+	s, err := ParseScenario([]byte(`
+accounts:
+  - {name: user, balance: 100icx}
+steps:
+  - setStake: {from: user, amount: 10icx}
+  - expect:
+      balance: {user: 100icx}
+      stake: {user: ">10icx", unknown: 0}
+`))
+	assert.NoError(t, err)
+	buf := bytes.NewBuffer(nil)
+	err = s.Run(buf)
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "stake[user]")
+	assert.NotContains(t, buf.String(), "balance[user]")
+
+	_, err = ParseScenario([]byte("steps:\n  - {go: {blocks: 1}, expect: {}}\n"))
+	assert.Error(t, err)
+
+	s, err = ParseScenario([]byte("steps:\n  - unknown: {from: user}\n"))
+	assert.NoError(t, err)
+	assert.Error(t, s.Run(buf))
+}
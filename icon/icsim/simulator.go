@@ -98,6 +98,8 @@ type Simulator interface {
 	ClaimIScore(from module.Address) Transaction
 
 	GetPRepStats(address module.Address) map[string]interface{}
+	GetPRepStatsOfTerm(sequence int, address module.Address) map[string]interface{}
+	GetPenaltyHistory(address module.Address) map[string]interface{}
 	GetPRep(address module.Address) *icstate.PRep
 	SetPRep(from module.Address, info *icstate.PRepInfo) Transaction
 
@@ -532,6 +532,24 @@ func (sim *simulatorImpl) GetPRepStats(address module.Address) map[string]interf
 	return ps.GetStatsInJSON(sim.BlockHeight())
 }
 
+func (sim *simulatorImpl) GetPRepStatsOfTerm(sequence int, address module.Address) map[string]interface{} {
+	es := sim.getExtensionState(true)
+	jso, err := es.State.GetPRepTermStatsInJSON(sequence, address, sim.BlockHeight())
+	if err != nil {
+		return nil
+	}
+	return jso
+}
+
+func (sim *simulatorImpl) GetPenaltyHistory(address module.Address) map[string]interface{} {
+	es := sim.getExtensionState(true)
+	jso, err := es.State.GetPenaltyHistoryInJSON(address, 0, 0)
+	if err != nil {
+		return nil
+	}
+	return jso
+}
+
 func (sim *simulatorImpl) GetNetworkInfo() map[string]interface{} {
 	es := sim.getExtensionState(true)
 	jso, err := es.State.GetNetworkInfoInJSON()
@@ -75,6 +75,8 @@ type Calculator struct {
 	global      icstage.Global
 	temp        *icreward.State
 	stats       *statistics
+	// reads is the number of entries read for the calculation
+	reads int
 
 	lock    sync.Mutex
 	waiters []*sync.Cond
@@ -355,6 +357,7 @@ func (c *Calculator) calculateBlockProduce() error {
 
 	prefix := icstage.BlockProduceKey.Build()
 	for iter := c.back.Filter(prefix); iter.Has(); iter.Next() {
+		c.reads++
 		var obj trie.Object
 		obj, _, err = iter.Get()
 		if err != nil {
@@ -467,6 +470,7 @@ func (c *Calculator) calculateVotedReward() error {
 
 	eventPrefix := icstage.EventKey.Build()
 	for iter := c.back.Filter(eventPrefix); iter.Has(); iter.Next() {
+		c.reads++
 		o, key, err := iter.Get()
 		if err != nil {
 			return err
@@ -547,6 +551,7 @@ func (c *Calculator) loadVotedInfo() (*votedInfo, error) {
 
 	prefix := icreward.VotedKey.Build()
 	for iter := c.base.Filter(prefix); iter.Has(); iter.Next() {
+		c.reads++
 		o, key, err := iter.Get()
 		if err != nil {
 			return nil, err
@@ -580,6 +585,7 @@ func (c *Calculator) loadVotedInfo() (*votedInfo, error) {
 func (c *Calculator) loadPRepInfo() (map[string]*pRepEnable, error) {
 	prepInfo := make(map[string]*pRepEnable)
 	for iter := c.base.Filter(icreward.VotedKey.Build()); iter.Has(); iter.Next() {
+		c.reads++
 		o, key, err := iter.Get()
 		if err != nil {
 			return nil, err
@@ -636,25 +642,80 @@ func varForVotingReward(global icstage.Global, totalVotingAmount *big.Int) (mult
 }
 
 func (c *Calculator) calculateVotingReward() error {
-	totalVotingAmount := new(big.Int)
-	delegatingMap := make(map[string]map[int]icstage.VoteList)
-	bondingMap := make(map[string]map[int]icstage.VoteList)
-	prepInfo, err := c.loadPRepInfo()
+	prepInfo, delegatingMap, bondingMap, totalVotingAmount, err := c.loadVotingEvents()
 	if err != nil {
 		return err
 	}
+
+	// get variables for calculation
+	multiplier, divider := varForVotingReward(c.global, totalVotingAmount)
+	if multiplier.Sign() == 0 || divider.Sign() == 0 {
+		return nil
+	}
+
+	inputs := []struct {
+		_type    int
+		eventMap map[string]map[int]icstage.VoteList
+	}{
+		{icreward.TypeDelegating, delegatingMap},
+		{icreward.TypeBonding, bondingMap},
+	}
+
+	// calculate voting reward
+	for _, i := range inputs {
+		if err = c.processVoting(
+			i._type,
+			multiplier,
+			divider,
+			prepInfo,
+			i.eventMap,
+		); err != nil {
+			return err
+		}
+		if err = c.processVotingEvent(
+			i._type,
+			multiplier,
+			divider,
+			prepInfo,
+			i.eventMap,
+		); err != nil {
+			return err
+		}
+	}
+	// add preprocessed data for BugDisabledPRep
+	c.addDataForBugDisabledPRep(prepInfo, multiplier, divider)
+	return nil
+}
+
+// loadVotingEvents collects P-Rep status, delegating and bonding events of
+// each account and the max total voting amount of the term.
+func (c *Calculator) loadVotingEvents() (
+	prepInfo map[string]*pRepEnable,
+	delegatingMap map[string]map[int]icstage.VoteList,
+	bondingMap map[string]map[int]icstage.VoteList,
+	totalVotingAmount *big.Int,
+	err error,
+) {
+	totalVotingAmount = new(big.Int)
+	delegatingMap = make(map[string]map[int]icstage.VoteList)
+	bondingMap = make(map[string]map[int]icstage.VoteList)
+	prepInfo, err = c.loadPRepInfo()
+	if err != nil {
+		return
+	}
 	vInfo, err := c.loadVotedInfo()
 	if err != nil {
-		return err
+		return
 	}
 	totalVotingAmount.Set(vInfo.TotalVoted())
 
 	for iter := c.back.Filter(icstage.EventKey.Build()); iter.Has(); iter.Next() {
+		c.reads++
 		var o trie.Object
 		var key []byte
 		o, key, err = iter.Get()
 		if err != nil {
-			return err
+			return
 		}
 
 		obj := o.(*icobject.Object)
@@ -663,7 +724,7 @@ func (c *Calculator) calculateVotingReward() error {
 		var keySplit [][]byte
 		keySplit, err = containerdb.SplitKeys(key)
 		if err != nil {
-			return err
+			return
 		}
 		offset := int(intconv.BytesToInt64(keySplit[1]))
 		switch _type {
@@ -744,45 +805,7 @@ func (c *Calculator) calculateVotingReward() error {
 			totalVotingAmount.Set(vInfo.TotalVoted())
 		}
 	}
-
-	// get variables for calculation
-	multiplier, divider := varForVotingReward(c.global, totalVotingAmount)
-	if multiplier.Sign() == 0 || divider.Sign() == 0 {
-		return nil
-	}
-
-	inputs := []struct {
-		_type    int
-		eventMap map[string]map[int]icstage.VoteList
-	}{
-		{icreward.TypeDelegating, delegatingMap},
-		{icreward.TypeBonding, bondingMap},
-	}
-
-	// calculate voting reward
-	for _, i := range inputs {
-		if err = c.processVoting(
-			i._type,
-			multiplier,
-			divider,
-			prepInfo,
-			i.eventMap,
-		); err != nil {
-			return err
-		}
-		if err = c.processVotingEvent(
-			i._type,
-			multiplier,
-			divider,
-			prepInfo,
-			i.eventMap,
-		); err != nil {
-			return err
-		}
-	}
-	// add preprocessed data for BugDisabledPRep
-	c.addDataForBugDisabledPRep(prepInfo, multiplier, divider)
-	return nil
+	return
 }
 
 func (c *Calculator) addDataForBugDisabledPRep(prepInfo map[string]*pRepEnable, multiplier, divider *big.Int) error {
@@ -1083,6 +1106,7 @@ func (c *Calculator) postWork() (err error) {
 
 func (c *Calculator) processBTP() error {
 	for iter := c.back.Filter(icstage.BTPKey.Build()); iter.Has(); iter.Next() {
+		c.reads++
 		o, _, err := iter.Get()
 		if err != nil {
 			return err
@@ -1117,6 +1141,15 @@ func (c *Calculator) processBTP() error {
 const InitBlockHeight = -1
 
 func NewCalculator(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot, logger log.Logger) *Calculator {
+	c := newCalculator(database, back, reward, logger)
+	if c != nil && c.startHeight != InitBlockHeight {
+		go c.run()
+	}
+	return c
+}
+
+// newCalculator returns a calculator without starting calculation.
+func newCalculator(database db.Database, back *icstage.Snapshot, reward *icreward.Snapshot, logger log.Logger) *Calculator {
 	var err error
 	var global icstage.Global
 	var startHeight int64
@@ -1142,9 +1175,6 @@ func NewCalculator(database db.Database, back *icstage.Snapshot, reward *icrewar
 		startHeight: startHeight,
 		stats:       newStatistics(),
 	}
-	if startHeight != InitBlockHeight {
-		go c.run()
-	}
 	return c
 }
 
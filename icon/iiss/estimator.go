@@ -0,0 +1,361 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iiss
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/iiss/icreward"
+	"github.com/icon-project/goloop/icon/iiss/icstage"
+	"github.com/icon-project/goloop/icon/iiss/icutils"
+	"github.com/icon-project/goloop/module"
+)
+
+// RewardEstimate is the estimated I-Score of an account for a term
+// broken down by reward type.
+type RewardEstimate struct {
+	StartHeight  int64
+	Period       int
+	BlockProduce *big.Int
+	Voted        *big.Int
+	Delegating   *big.Int
+	Bonding      *big.Int
+}
+
+func (e *RewardEstimate) Total() *big.Int {
+	total := new(big.Int)
+	for _, v := range []*big.Int{e.BlockProduce, e.Voted, e.Delegating, e.Bonding} {
+		total.Add(total, v)
+	}
+	return total
+}
+
+func (e *RewardEstimate) ToJSON() map[string]interface{} {
+	total := e.Total()
+	jso := make(map[string]interface{})
+	jso["startBlockHeight"] = e.StartHeight
+	jso["endBlockHeight"] = e.StartHeight + int64(e.Period) - 1
+	jso["blockProduce"] = e.BlockProduce
+	jso["voted"] = e.Voted
+	jso["delegating"] = e.Delegating
+	jso["bonding"] = e.Bonding
+	jso["iscore"] = total
+	jso["estimatedICX"] = icutils.IScoreToICX(total)
+	return jso
+}
+
+// estimateTerm is the calculation of a term for all accounts except
+// voting rewards. Voting rewards depend on the delegations of the account,
+// so they are calculated for each account with it.
+type estimateTerm struct {
+	back          *icstage.Snapshot
+	global        icstage.Global
+	before        *icreward.Snapshot
+	produced      *icreward.Snapshot
+	voted         *icreward.Snapshot
+	after         *icreward.Snapshot
+	prepInfo      map[string]*pRepEnable
+	delegatingMap map[string]map[int]icstage.VoteList
+	bondingMap    map[string]map[int]icstage.VoteList
+	multiplier    *big.Int
+	divider       *big.Int
+	// reads is the number of entries read for the preparation
+	reads int
+}
+
+// prepareEstimate calculates block produce and voted rewards of the term
+// as run does, and it loads voting events for voting rewards. The result
+// of the term is used as the base of the following term.
+func (c *Calculator) prepareEstimate() (*estimateTerm, error) {
+	t := &estimateTerm{
+		back:   c.back,
+		global: c.global,
+		before: c.base,
+	}
+	if err := c.calculateBlockProduce(); err != nil {
+		return nil, err
+	}
+	t.produced = c.temp.GetSnapshot()
+	if err := c.calculateVotedReward(); err != nil {
+		return nil, err
+	}
+	t.voted = c.temp.GetSnapshot()
+
+	var totalVotingAmount *big.Int
+	var err error
+	t.prepInfo, t.delegatingMap, t.bondingMap, totalVotingAmount, err = c.loadVotingEvents()
+	if err != nil {
+		return nil, err
+	}
+	t.multiplier, t.divider = varForVotingReward(c.global, totalVotingAmount)
+
+	// BTP public keys of the term are effective from the next term
+	if err = c.processBTP(); err != nil {
+		return nil, err
+	}
+	t.after = c.temp.GetSnapshot()
+	t.reads = c.reads
+	return t, nil
+}
+
+// readsOf returns the number of entries read for the estimation of addr
+// with the term. They are voting events of addr in the term and its
+// delegations and bonds.
+func (t *estimateTerm) readsOf(addr module.Address) int {
+	key := icutils.ToKey(addr)
+	return len(t.delegatingMap[key]) + len(t.bondingMap[key]) + 2
+}
+
+// estimate returns the reward of addr for the term. voting has delegations
+// of addr at the start of the term, and it's updated with the events of addr
+// in the term, so it can be used for the following term.
+func (t *estimateTerm) estimate(database db.Database, voting *icreward.State, addr module.Address, logger log.Logger) (*RewardEstimate, error) {
+	var err error
+	e := &RewardEstimate{
+		StartHeight: t.global.GetStartHeight(),
+		Period:      t.global.GetTermPeriod(),
+	}
+	if e.BlockProduce, err = diffIScore(t.before, t.produced, addr); err != nil {
+		return nil, err
+	}
+	if e.Voted, err = diffIScore(t.produced, t.voted, addr); err != nil {
+		return nil, err
+	}
+
+	c := newCalculator(database, t.back, t.before, logger)
+	if c == nil {
+		return nil, errors.InvalidStateError.New("Failed to get Global values")
+	}
+	c.global = t.global
+	c.temp = voting
+	if e.Delegating, err = c.rewardOf(addr, func() error {
+		return c.estimateVotingReward(addr, icreward.TypeDelegating, t.multiplier, t.divider, t.prepInfo, t.delegatingMap)
+	}); err != nil {
+		return nil, err
+	}
+	if e.Bonding, err = c.rewardOf(addr, func() error {
+		return c.estimateVotingReward(addr, icreward.TypeBonding, t.multiplier, t.divider, t.prepInfo, t.bondingMap)
+	}); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func diffIScore(before, after *icreward.Snapshot, addr module.Address) (*big.Int, error) {
+	reward := new(big.Int)
+	if is, err := after.NewState().GetIScore(addr); err != nil {
+		return nil, err
+	} else if is != nil {
+		reward.Set(is.Value())
+	}
+	if is, err := before.NewState().GetIScore(addr); err != nil {
+		return nil, err
+	} else if is != nil {
+		reward.Sub(reward, is.Value())
+	}
+	return reward, nil
+}
+
+const (
+	estimateCacheSize = 16
+	// estimateRefreshBlocks is the number of blocks for which the
+	// calculation of the current term is reused.
+	estimateRefreshBlocks = 1800
+)
+
+// estimateEntry is the preparation of a term. It's done only once even if
+// multiple queries ask for it at the same time.
+type estimateEntry struct {
+	once sync.Once
+	term *estimateTerm
+	err  error
+}
+
+// estimateCache keeps the terms prepared for estimation. They are common for
+// all accounts, so they are calculated once for each term instead of each
+// query. Least recently used terms are evicted first.
+type estimateCache struct {
+	lock  sync.Mutex
+	terms *cache.LRUCache
+}
+
+var estimateTerms = &estimateCache{
+	terms: cache.NewLRUCache(estimateCacheSize, nil),
+}
+
+func (ec *estimateCache) entryOf(key []byte) *estimateEntry {
+	ec.lock.Lock()
+	defer ec.lock.Unlock()
+	if v, err := ec.terms.Get(string(key)); err == nil {
+		return v.(*estimateEntry)
+	}
+	e := new(estimateEntry)
+	ec.terms.Put(string(key), e)
+	return e
+}
+
+func (ec *estimateCache) dropEntry(key []byte, e *estimateEntry) {
+	ec.lock.Lock()
+	defer ec.lock.Unlock()
+	if v, err := ec.terms.Get(string(key)); err == nil && v == e {
+		ec.terms.Put(string(key), new(estimateEntry))
+	}
+}
+
+// termOf returns the term prepared with c for the key. reads is the number of
+// entries read for the preparation if it's prepared by this call.
+func (ec *estimateCache) termOf(key []byte, c *Calculator) (t *estimateTerm, reads int, err error) {
+	e := ec.entryOf(key)
+	e.once.Do(func() {
+		e.term, e.err = c.prepareEstimate()
+		if e.term != nil {
+			reads = e.term.reads
+		}
+	})
+	if e.err != nil {
+		ec.dropEntry(key, e)
+		return nil, 0, e.err
+	}
+	return e.term, reads, nil
+}
+
+func estimateKey(prev []byte, values ...interface{}) []byte {
+	return crypto.SHA3Sum256(codec.BC.MustMarshalToBytes(append([]interface{}{prev}, values...)))
+}
+
+// estimate returns the reward of addr for the term of front stage and the
+// next term of it. Terms of back stages are applied before the term of front
+// stage. The next term is calculated with the result of the front term and
+// the same global values. It also returns the number of entries read for
+// the estimation.
+//
+// Back stages and the reward don't change until the next calculation, so their
+// terms are cached with their hashes. The front stage changes on every block,
+// so its term is cached with its start height and is recalculated every
+// estimateRefreshBlocks blocks.
+func (ec *estimateCache) estimate(
+	database db.Database,
+	reward *icreward.Snapshot,
+	backs []*icstage.Snapshot,
+	front *icstage.Snapshot,
+	height int64,
+	addr module.Address,
+	logger log.Logger,
+) (current, next *RewardEstimate, reads int, err error) {
+	voting := reward.NewState()
+	base := reward
+	key := reward.Bytes()
+	var last *estimateTerm
+	for _, stage := range append(backs, front) {
+		c := newCalculator(database, stage, base, logger)
+		if c == nil {
+			return nil, nil, 0, errors.InvalidStateError.New("Failed to get Global values")
+		}
+		if c.global == nil {
+			continue
+		}
+		if stage == front {
+			start := c.global.GetStartHeight()
+			key = estimateKey(key, start, (height-start)/estimateRefreshBlocks)
+		} else {
+			key = estimateKey(key, stage.Bytes())
+		}
+		var n int
+		if last, n, err = ec.termOf(key, c); err != nil {
+			return nil, nil, 0, err
+		}
+		if current, err = last.estimate(database, voting, addr, logger); err != nil {
+			return nil, nil, 0, err
+		}
+		reads += n + last.readsOf(addr)
+		base = last.after
+	}
+	if last == nil {
+		return nil, nil, 0, errors.NotFoundError.New("NoTermToEstimate")
+	}
+
+	key = estimateKey(key, "next")
+	c := newCalculator(database, icstage.NewSnapshot(database, nil), base, logger)
+	c.global = last.global
+	t, n, err := ec.termOf(key, c)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if next, err = t.estimate(database, voting, addr, logger); err != nil {
+		return nil, nil, 0, err
+	}
+	reads += n + t.readsOf(addr)
+	next.StartHeight += int64(next.Period)
+	return current, next, reads, nil
+}
+
+// rewardOf returns I-Score of addr increased by the step.
+func (c *Calculator) rewardOf(addr module.Address, step func() error) (*big.Int, error) {
+	before, err := c.temp.GetIScore(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err = step(); err != nil {
+		return nil, err
+	}
+	after, err := c.temp.GetIScore(addr)
+	if err != nil {
+		return nil, err
+	}
+	reward := new(big.Int)
+	if after != nil {
+		reward.Set(after.Value())
+	}
+	if before != nil {
+		reward.Sub(reward, before.Value())
+	}
+	return reward, nil
+}
+
+// estimateVotingReward calculates the voting reward of addr as processVoting
+// and processVotingEvent do for all accounts.
+func (c *Calculator) estimateVotingReward(
+	addr module.Address,
+	_type int,
+	multiplier *big.Int,
+	divider *big.Int,
+	prepInfo map[string]*pRepEnable,
+	eventMap map[string]map[int]icstage.VoteList,
+) error {
+	if multiplier.Sign() == 0 || divider.Sign() == 0 {
+		return nil
+	}
+	key := icutils.ToKey(addr)
+	if events, ok := eventMap[key]; ok {
+		return c.processVotingEvent(_type, multiplier, divider, prepInfo,
+			map[string]map[int]icstage.VoteList{key: events})
+	}
+	voting, err := c.getVoting(_type, common.AddressToPtr(addr))
+	if err != nil {
+		return err
+	}
+	reward := c.votingReward(multiplier, divider, -1, c.global.GetOffsetLimit(), prepInfo, voting.Iterator())
+	return c.updateIScore(addr, reward, TypeVoting)
+}
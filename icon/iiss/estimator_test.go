@@ -0,0 +1,169 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iiss
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icreward"
+	"github.com/icon-project/goloop/icon/iiss/icstage"
+	"github.com/icon-project/goloop/module"
+)
+
+func newTestTerm(t *testing.T, database db.Database, startHeight int64) *icstage.State {
+	stage := icstage.NewState(database)
+	err := stage.AddGlobalV2(
+		icmodule.RevisionEstimateReward, startHeight, 99,
+		big.NewInt(1_000_000), big.NewInt(50), big.NewInt(50), big.NewInt(0), big.NewInt(0),
+		22, 0,
+	)
+	assert.NoError(t, err)
+	return stage
+}
+
+func iScoreOf(t *testing.T, s *icreward.State, addr module.Address) *big.Int {
+	is, err := s.GetIScore(addr)
+	assert.NoError(t, err)
+	if is == nil {
+		return new(big.Int)
+	}
+	return is.Value()
+}
+
+func TestCalculator_estimate(t *testing.T) {
+	database := db.NewMapDB()
+	prep1 := common.MustNewAddressFromString("hx1")
+	prep2 := common.MustNewAddressFromString("hx2")
+	user1 := common.MustNewAddressFromString("hx11")
+	user2 := common.MustNewAddressFromString("hx12")
+	amount := big.NewInt(1_000_000)
+
+	base := icreward.NewState(database, nil)
+	for _, prep := range []*common.Address{prep1, prep2} {
+		voted := icreward.NewVoted()
+		voted.SetEnable(true)
+		voted.SetDelegated(amount)
+		assert.NoError(t, base.SetVoted(prep, voted))
+	}
+	d1 := icreward.NewDelegating()
+	assert.NoError(t, d1.ApplyVotes(icstage.VoteList{icstage.NewVote(prep1, amount)}))
+	assert.NoError(t, base.SetDelegating(user1, d1))
+	d2 := icreward.NewDelegating()
+	assert.NoError(t, d2.ApplyVotes(icstage.VoteList{icstage.NewVote(prep2, amount)}))
+	assert.NoError(t, base.SetDelegating(user2, d2))
+
+	// user2 moves its delegation from prep2 to prep1 in the middle of the term
+	front := newTestTerm(t, database, 100)
+	votes := icstage.VoteList{
+		icstage.NewVote(prep1, amount),
+		icstage.NewVote(prep2, new(big.Int).Neg(amount)),
+	}
+	_, _, err := front.AddEventDelegationV2(50, user2, votes, votes)
+	assert.NoError(t, err)
+
+	next := newTestTerm(t, database, 200)
+
+	// calculate all the accounts for two terms
+	c1 := newCalculator(database, front.GetSnapshot(), base.GetSnapshot(), log.New())
+	assert.NoError(t, c1.run())
+	c2 := newCalculator(database, next.GetSnapshot(), c1.Result(), log.New())
+	assert.NoError(t, c2.run())
+	r1 := c1.Result().NewState()
+	r2 := c2.Result().NewState()
+
+	// terms are shared by estimations for the accounts
+	ec := &estimateCache{terms: cache.NewLRUCache(estimateCacheSize, nil)}
+	estimates := make(map[string]*RewardEstimate)
+	var firstReads int
+	for i, addr := range []*common.Address{prep1, prep2, user1, user2} {
+		t.Run(addr.String(), func(t *testing.T) {
+			current, next, reads, err := ec.estimate(database, base.GetSnapshot(), nil, front.GetSnapshot(), 150, addr, log.New())
+			assert.NoError(t, err)
+			assert.Equal(t, 0, iScoreOf(t, r1, addr).Cmp(current.Total()))
+			assert.Equal(t, int64(100), current.StartHeight)
+			assert.Equal(t, 100, current.Period)
+
+			reward := new(big.Int).Sub(iScoreOf(t, r2, addr), iScoreOf(t, r1, addr))
+			assert.Equal(t, 0, reward.Cmp(next.Total()))
+			assert.Equal(t, int64(200), next.StartHeight)
+			estimates[addr.String()] = current
+
+			// preparation of the terms is charged only once
+			if i == 0 {
+				firstReads = reads
+			} else {
+				assert.Less(t, reads, firstReads)
+			}
+		})
+	}
+	assert.Equal(t, 2, ec.terms.Len())
+
+	// changes of the front stage are applied after estimateRefreshBlocks
+	_, err = front.AddIScoreClaim(user1, big.NewInt(1))
+	assert.NoError(t, err)
+	_, _, reads, err := ec.estimate(database, base.GetSnapshot(), nil, front.GetSnapshot(), 151, user1, log.New())
+	assert.NoError(t, err)
+	assert.Less(t, reads, firstReads)
+	assert.Equal(t, 2, ec.terms.Len())
+	_, _, reads, err = ec.estimate(database, base.GetSnapshot(), nil, front.GetSnapshot(), 100+estimateRefreshBlocks, user1, log.New())
+	assert.NoError(t, err)
+	assert.Equal(t, firstReads, reads)
+	assert.Equal(t, 4, ec.terms.Len())
+
+	e := estimates[prep1.String()]
+	assert.Equal(t, 1, e.Voted.Sign())
+	assert.Equal(t, 0, e.Delegating.Sign())
+	e = estimates[user2.String()]
+	assert.Equal(t, 0, e.Voted.Sign())
+	assert.Equal(t, 1, e.Delegating.Sign())
+}
+
+func TestEstimateCache_termOf(t *testing.T) {
+	database := db.NewMapDB()
+	base := icreward.NewState(database, nil).GetSnapshot()
+	stage := newTestTerm(t, database, 100).GetSnapshot()
+	ec := &estimateCache{terms: cache.NewLRUCache(estimateCacheSize, nil)}
+
+	keyOf := func(i int) []byte {
+		return estimateKey(nil, i)
+	}
+	for i := 0; i < estimateCacheSize; i++ {
+		_, reads, err := ec.termOf(keyOf(i), newCalculator(database, stage, base, log.New()))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, reads)
+	}
+	first, _, err := ec.termOf(keyOf(0), nil)
+	assert.NoError(t, err)
+
+	// the least recently used term is evicted instead of all the terms
+	_, _, err = ec.termOf(keyOf(estimateCacheSize), newCalculator(database, stage, base, log.New()))
+	assert.NoError(t, err)
+	assert.Equal(t, estimateCacheSize, ec.terms.Len())
+	t0, _, err := ec.termOf(keyOf(0), nil)
+	assert.NoError(t, err)
+	assert.Same(t, first, t0)
+	_, err = ec.terms.Get(string(keyOf(1)))
+	assert.Error(t, err)
+}
@@ -592,7 +592,7 @@ func (es *ExtensionStateImpl) DisqualifyPRep(cc icmodule.CallContext, address mo
 			intconv.Int64ToBytes(int64(icmodule.PenaltyPRepDisqualification)),
 		},
 	)
-	return nil
+	return es.addPenaltyRecord(cc, address, icmodule.PenaltyPRepDisqualification, new(big.Int))
 }
 
 func (es *ExtensionStateImpl) PenalizeNonVoters(cc icmodule.CallContext, address module.Address) error {
@@ -606,7 +606,11 @@ func (es *ExtensionStateImpl) PenalizeNonVoters(cc icmodule.CallContext, address
 		},
 	)
 
-	return es.slash(cc, address, es.State.GetNonVotePenaltySlashRatio())
+	slashed, err := es.slash(cc, address, es.State.GetNonVotePenaltySlashRatio())
+	if err != nil {
+		return err
+	}
+	return es.addPenaltyRecord(cc, address, icmodule.PenaltyNonVote, slashed)
 }
 
 func (es *ExtensionStateImpl) SetBond(blockHeight int64, from module.Address, bonds icstate.Bonds) error {
@@ -969,6 +973,14 @@ func (es *ExtensionStateImpl) onTermEnd(wc icmodule.WorldContext) error {
 		isDecentralized = es.State.IsDecentralizationConditionMet(revision, totalSupply, prepSet)
 	}
 
+	term := es.State.GetTermSnapshot()
+	recordHistory := revision >= icmodule.RevisionPRepHistory
+	if recordHistory && term.IsDecentralized() {
+		if err = es.State.OnPRepTermEnd(term, wc.BlockHeight()); err != nil {
+			return err
+		}
+	}
+
 	if isDecentralized {
 		// Reset the status of all active preps ordered by power
 		limit := es.State.GetConsistentValidationPenaltyMask()
@@ -980,7 +992,13 @@ func (es *ExtensionStateImpl) onTermEnd(wc icmodule.WorldContext) error {
 		prepSet = nil
 	}
 
-	return es.moveOnToNextTerm(prepSet, totalSupply, revision, electedPRepCount)
+	if err = es.moveOnToNextTerm(prepSet, totalSupply, revision, electedPRepCount); err != nil {
+		return err
+	}
+	if nextTerm := es.State.GetTermSnapshot(); recordHistory && nextTerm.IsDecentralized() {
+		return es.State.OnPRepTermStart(nextTerm, wc.BlockHeight())
+	}
+	return nil
 }
 
 func (es *ExtensionStateImpl) moveOnToNextTerm(
@@ -1459,6 +1477,35 @@ func (es *ExtensionStateImpl) GetIScore(from module.Address, revision int, txID
 	return iScore, nil
 }
 
+// EstimateReward estimates I-Score of the address for the current and the
+// next term at the height. Terms waiting for calculation are applied first.
+// Then the current term is calculated with its events so far, assuming there
+// is no more change until the end of the term. The next term is calculated
+// with the result of the current term and the same global values.
+// Calculations common for all accounts are cached for each term.
+// It also returns the number of entries read for the estimation, so the caller
+// can charge steps for them.
+func (es *ExtensionStateImpl) EstimateReward(address module.Address, height int64) (map[string]interface{}, int, error) {
+	backs := []*icstage.Snapshot{es.Back2.GetSnapshot(), es.Back1.GetSnapshot()}
+	current, next, reads, err := estimateTerms.estimate(
+		es.database, es.Reward.GetSnapshot(), backs, es.Front.GetSnapshot(), height, address, es.logger)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, 0, scoreresult.InvalidInstanceError.New("No term to estimate reward")
+		}
+		return nil, 0, scoreresult.UnknownFailureError.Wrapf(
+			err,
+			"Failed to estimate reward: address=%v",
+			address,
+		)
+	}
+
+	jso := make(map[string]interface{})
+	jso["current"] = current.ToJSON()
+	jso["next"] = next.ToJSON()
+	return jso, reads, nil
+}
+
 func (es *ExtensionStateImpl) ClaimIScore(cc icmodule.CallContext) error {
 	from := cc.From()
 
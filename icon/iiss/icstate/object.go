@@ -33,6 +33,8 @@ const (
 	TypeValidators
 	TypeBlockVoters
 	TypeIllegalDelegation
+	TypePRepTermStats
+	TypePenaltyRecord
 )
 
 func NewObjectImpl(tag icobject.Tag) (icobject.Impl, error) {
@@ -57,6 +59,10 @@ func NewObjectImpl(tag icobject.Tag) (icobject.Impl, error) {
 		return NewBlockVotersWithTag(tag), nil
 	case TypeIllegalDelegation:
 		return NewIllegalDelegationWithTag(tag), nil
+	case TypePRepTermStats:
+		return NewPRepTermStatsWithTag(tag), nil
+	case TypePenaltyRecord:
+		return NewPenaltyRecordWithTag(tag), nil
 	default:
 		return nil, errors.IllegalArgumentError.Errorf(
 			"UnknownTypeTag(tag=%#x)", tag)
@@ -135,3 +141,17 @@ func ToIllegalDelegation(object trie.Object) *IllegalDelegation {
 	}
 	return object.(*icobject.Object).Real().(*IllegalDelegation)
 }
+
+func ToPRepTermStats(object trie.Object) *PRepTermStats {
+	if object == nil {
+		return nil
+	}
+	return object.(*icobject.Object).Real().(*PRepTermStats)
+}
+
+func ToPenaltyRecord(object trie.Object) *PenaltyRecord {
+	if object == nil {
+		return nil
+	}
+	return object.(*icobject.Object).Real().(*PenaltyRecord)
+}
@@ -0,0 +1,506 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icobject"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoredb"
+)
+
+var (
+	prepTermStatsDictPrefix = containerdb.ToKey(
+		containerdb.HashBuilder, scoredb.DictDBPrefix, "prep_term_stats",
+	)
+	penaltyHistoryArrayPrefix = containerdb.ToKey(
+		containerdb.HashBuilder, scoredb.ArrayDBPrefix, "penalty_history",
+	)
+)
+
+// PRepTermStatsEntry has validation counters of an elected PRep for a term.
+// start values are the counters of PRepStatus at the beginning of the term,
+// and total and fail are the numbers of blocks in the term, which are fixed
+// at the end of the term.
+type PRepTermStatsEntry struct {
+	owner      *common.Address
+	grade      Grade
+	power      *big.Int
+	startTotal int64
+	startFail  int64
+	total      int64
+	fail       int64
+}
+
+func (e *PRepTermStatsEntry) Owner() module.Address {
+	return e.owner
+}
+
+func (e *PRepTermStatsEntry) Grade() Grade {
+	return e.grade
+}
+
+func (e *PRepTermStatsEntry) Power() *big.Int {
+	return e.power
+}
+
+func (e *PRepTermStatsEntry) Total() int64 {
+	return e.total
+}
+
+func (e *PRepTermStatsEntry) Fail() int64 {
+	return e.fail
+}
+
+func (e *PRepTermStatsEntry) equal(o *PRepTermStatsEntry) bool {
+	return e.owner.Equal(o.owner) &&
+		e.grade == o.grade &&
+		e.power.Cmp(o.power) == 0 &&
+		e.startTotal == o.startTotal &&
+		e.startFail == o.startFail &&
+		e.total == o.total &&
+		e.fail == o.fail
+}
+
+// update sets the numbers of blocks in the term with counters of ps at
+// blockHeight.
+func (e *PRepTermStatsEntry) update(ps *PRepStatusState, blockHeight int64) {
+	e.total = ps.GetVTotal(blockHeight) - e.startTotal
+	e.fail = ps.GetVFail(blockHeight) - e.startFail
+}
+
+func (e *PRepTermStatsEntry) RLPEncodeSelf(encoder codec.Encoder) error {
+	return encoder.EncodeListOf(
+		e.owner, int(e.grade), e.power, e.startTotal, e.startFail, e.total, e.fail,
+	)
+}
+
+func (e *PRepTermStatsEntry) RLPDecodeSelf(decoder codec.Decoder) error {
+	var grade int
+	if err := decoder.DecodeListOf(
+		&e.owner, &grade, &e.power, &e.startTotal, &e.startFail, &e.total, &e.fail,
+	); err != nil {
+		return err
+	}
+	e.grade = Grade(grade)
+	return nil
+}
+
+func (e *PRepTermStatsEntry) ToJSON(penalties int) map[string]interface{} {
+	return map[string]interface{}{
+		"address":   e.owner,
+		"grade":     int(e.grade),
+		"power":     e.power,
+		"total":     e.total,
+		"fail":      e.fail,
+		"penalties": penalties,
+	}
+}
+
+func NewPRepTermStatsEntry(owner module.Address, grade Grade, power *big.Int, ps *PRepStatusState, blockHeight int64) *PRepTermStatsEntry {
+	return &PRepTermStatsEntry{
+		owner:      common.AddressToPtr(owner),
+		grade:      grade,
+		power:      power,
+		startTotal: ps.GetVTotal(blockHeight),
+		startFail:  ps.GetVFail(blockHeight),
+	}
+}
+
+// PRepTermStats is the history of elected PReps for a term.
+type PRepTermStats struct {
+	icobject.NoDatabase
+
+	sequence    int
+	startHeight int64
+	period      int64
+	entries     []*PRepTermStatsEntry
+}
+
+func NewPRepTermStatsWithTag(_ icobject.Tag) *PRepTermStats {
+	return new(PRepTermStats)
+}
+
+func (s *PRepTermStats) Version() int {
+	return 0
+}
+
+func (s *PRepTermStats) Sequence() int {
+	return s.sequence
+}
+
+func (s *PRepTermStats) StartHeight() int64 {
+	return s.startHeight
+}
+
+func (s *PRepTermStats) EndHeight() int64 {
+	return s.startHeight + s.period - 1
+}
+
+func (s *PRepTermStats) Entries() []*PRepTermStatsEntry {
+	return s.entries
+}
+
+func (s *PRepTermStats) clone() *PRepTermStats {
+	entries := make([]*PRepTermStatsEntry, len(s.entries))
+	for i, e := range s.entries {
+		ne := *e
+		entries[i] = &ne
+	}
+	return &PRepTermStats{
+		sequence:    s.sequence,
+		startHeight: s.startHeight,
+		period:      s.period,
+		entries:     entries,
+	}
+}
+
+func (s *PRepTermStats) RLPDecodeFields(decoder codec.Decoder) error {
+	return decoder.DecodeAll(
+		&s.sequence,
+		&s.startHeight,
+		&s.period,
+		&s.entries,
+	)
+}
+
+func (s *PRepTermStats) RLPEncodeFields(encoder codec.Encoder) error {
+	return encoder.EncodeMulti(
+		s.sequence,
+		s.startHeight,
+		s.period,
+		s.entries,
+	)
+}
+
+func (s *PRepTermStats) Equal(o icobject.Impl) bool {
+	s2, ok := o.(*PRepTermStats)
+	if !ok {
+		return false
+	}
+	if s.sequence != s2.sequence ||
+		s.startHeight != s2.startHeight ||
+		s.period != s2.period ||
+		len(s.entries) != len(s2.entries) {
+		return false
+	}
+	for i, e := range s.entries {
+		if !e.equal(s2.entries[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *PRepTermStats) Format(f fmt.State, c rune) {
+	switch c {
+	case 'v':
+		if f.Flag('+') {
+			fmt.Fprintf(f, "PRepTermStats{sequence=%d startHeight=%d period=%d entries=%d}",
+				s.sequence, s.startHeight, s.period, len(s.entries))
+		} else {
+			fmt.Fprintf(f, "PRepTermStats{%d %d %d %d}",
+				s.sequence, s.startHeight, s.period, len(s.entries))
+		}
+	}
+}
+
+func NewPRepTermStats(term *TermSnapshot, entries []*PRepTermStatsEntry) *PRepTermStats {
+	return &PRepTermStats{
+		sequence:    term.Sequence(),
+		startHeight: term.StartHeight(),
+		period:      term.Period(),
+		entries:     entries,
+	}
+}
+
+// PenaltyRecord is a penalty imposed on a PRep. slashed is the amount of
+// stake slashed from bonders by the penalty.
+type PenaltyRecord struct {
+	icobject.NoDatabase
+
+	height      int64
+	penaltyType icmodule.PenaltyType
+	slashed     *big.Int
+}
+
+func NewPenaltyRecordWithTag(_ icobject.Tag) *PenaltyRecord {
+	return new(PenaltyRecord)
+}
+
+func NewPenaltyRecord(height int64, pt icmodule.PenaltyType, slashed *big.Int) *PenaltyRecord {
+	return &PenaltyRecord{
+		height:      height,
+		penaltyType: pt,
+		slashed:     slashed,
+	}
+}
+
+func (r *PenaltyRecord) Version() int {
+	return 0
+}
+
+func (r *PenaltyRecord) Height() int64 {
+	return r.height
+}
+
+func (r *PenaltyRecord) Type() icmodule.PenaltyType {
+	return r.penaltyType
+}
+
+func (r *PenaltyRecord) Slashed() *big.Int {
+	return r.slashed
+}
+
+func (r *PenaltyRecord) RLPDecodeFields(decoder codec.Decoder) error {
+	var pt int
+	if err := decoder.DecodeAll(&r.height, &pt, &r.slashed); err != nil {
+		return err
+	}
+	r.penaltyType = icmodule.PenaltyType(pt)
+	return nil
+}
+
+func (r *PenaltyRecord) RLPEncodeFields(encoder codec.Encoder) error {
+	return encoder.EncodeMulti(r.height, int(r.penaltyType), r.slashed)
+}
+
+func (r *PenaltyRecord) Equal(o icobject.Impl) bool {
+	if r2, ok := o.(*PenaltyRecord); ok {
+		return r.height == r2.height &&
+			r.penaltyType == r2.penaltyType &&
+			r.slashed.Cmp(r2.slashed) == 0
+	}
+	return false
+}
+
+func (r *PenaltyRecord) Format(f fmt.State, c rune) {
+	switch c {
+	case 'v':
+		if f.Flag('+') {
+			fmt.Fprintf(f, "PenaltyRecord{height=%d type=%d slashed=%s}",
+				r.height, r.penaltyType, r.slashed)
+		} else {
+			fmt.Fprintf(f, "PenaltyRecord{%d %d %s}", r.height, r.penaltyType, r.slashed)
+		}
+	}
+}
+
+func (r *PenaltyRecord) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"blockHeight": r.height,
+		"type":        int(r.penaltyType),
+		"slashed":     r.slashed,
+	}
+}
+
+func (s *State) GetPRepTermStats(sequence int) *PRepTermStats {
+	dict := containerdb.NewDictDB(s.store, 1, prepTermStatsDictPrefix)
+	obj := dict.Get(sequence)
+	if obj == nil {
+		return nil
+	}
+	return ToPRepTermStats(obj.Object())
+}
+
+func (s *State) setPRepTermStats(stats *PRepTermStats) error {
+	dict := containerdb.NewDictDB(s.store, 1, prepTermStatsDictPrefix)
+	return dict.Set(stats.sequence, icobject.New(TypePRepTermStats, stats))
+}
+
+// OnPRepTermStart records elected PReps of the term with their counters
+// at blockHeight, which is the last block of the previous term.
+func (s *State) OnPRepTermStart(term *TermSnapshot, blockHeight int64) error {
+	size := term.GetPRepSnapshotCount()
+	mainPRepCount := term.MainPRepCount()
+	entries := make([]*PRepTermStatsEntry, 0, size)
+	for i := 0; i < size; i++ {
+		pss := term.GetPRepSnapshotByIndex(i)
+		ps := s.GetPRepStatusByOwner(pss.Owner(), false)
+		if ps == nil {
+			continue
+		}
+		grade := GradeSub
+		if i < mainPRepCount {
+			grade = GradeMain
+		}
+		entries = append(entries, NewPRepTermStatsEntry(pss.Owner(), grade, pss.Power(), ps, blockHeight))
+	}
+	return s.setPRepTermStats(NewPRepTermStats(term, entries))
+}
+
+// OnPRepTermEnd fixes the numbers of blocks of PReps for the term with their
+// counters at blockHeight, which is the last block of the term.
+func (s *State) OnPRepTermEnd(term *TermSnapshot, blockHeight int64) error {
+	stats := s.GetPRepTermStats(term.Sequence())
+	if stats == nil || stats.startHeight != term.StartHeight() {
+		// the term started before the history is enabled
+		return nil
+	}
+	stats = stats.clone()
+	for _, e := range stats.entries {
+		if ps := s.GetPRepStatusByOwner(e.owner, false); ps != nil {
+			e.update(ps, blockHeight)
+		}
+	}
+	return s.setPRepTermStats(stats)
+}
+
+// MaxPenaltyHistoryQuerySize is the maximum number of penalties returned
+// by a query of the penalty history.
+const MaxPenaltyHistoryQuerySize = 100
+
+func (s *State) getPenaltyHistoryDB(owner module.Address) *containerdb.ArrayDB {
+	return containerdb.NewArrayDB(s.store, penaltyHistoryArrayPrefix.Append(owner))
+}
+
+func (s *State) AddPenaltyRecord(owner module.Address, r *PenaltyRecord) error {
+	arr := s.getPenaltyHistoryDB(owner)
+	return arr.Put(icobject.New(TypePenaltyRecord, r))
+}
+
+// GetPenaltyCount returns the number of penalties imposed on the PRep.
+func (s *State) GetPenaltyCount(owner module.Address) int {
+	return s.getPenaltyHistoryDB(owner).Size()
+}
+
+// GetPenaltyHistory returns penalties of the PRep in the order of imposition.
+// It returns records with index in [start, end).
+func (s *State) GetPenaltyHistory(owner module.Address, start, end int) []*PenaltyRecord {
+	arr := s.getPenaltyHistoryDB(owner)
+	if size := arr.Size(); end > size {
+		end = size
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start >= end {
+		return []*PenaltyRecord{}
+	}
+	records := make([]*PenaltyRecord, end-start)
+	for i := start; i < end; i++ {
+		records[i-start] = ToPenaltyRecord(arr.Get(i).Object())
+	}
+	return records
+}
+
+// countPenalties returns the number of penalties imposed in [from, to].
+// Records are in the order of block height, so it scans from the latest one.
+func (s *State) countPenalties(owner module.Address, from, to int64) int {
+	arr := s.getPenaltyHistoryDB(owner)
+	count := 0
+	for i := arr.Size() - 1; i >= 0; i-- {
+		r := ToPenaltyRecord(arr.Get(i).Object())
+		if r.height < from {
+			break
+		}
+		if r.height <= to {
+			count += 1
+		}
+	}
+	return count
+}
+
+// GetPRepTermStatsInJSON returns stats of PReps for the term. If owner is not
+// nil, it returns the stats of the PRep only. Numbers of blocks of the
+// current term are counted until blockHeight.
+func (s *State) GetPRepTermStatsInJSON(sequence int, owner module.Address, blockHeight int64) (map[string]interface{}, error) {
+	stats := s.GetPRepTermStats(sequence)
+	if stats == nil {
+		return nil, errors.NotFoundError.Errorf("NoPRepTermStats(sequence=%d)", sequence)
+	}
+	term := s.GetTermSnapshot()
+	current := term != nil && term.Sequence() == stats.sequence && term.StartHeight() == stats.startHeight
+
+	to := stats.EndHeight()
+	if current {
+		to = blockHeight
+	}
+	preps := make([]interface{}, 0, len(stats.entries))
+	for _, e := range stats.entries {
+		if owner != nil && !e.owner.Equal(owner) {
+			continue
+		}
+		if current {
+			ne := *e
+			if ps := s.GetPRepStatusByOwner(e.owner, false); ps != nil {
+				ne.update(ps, blockHeight)
+			}
+			e = &ne
+		}
+		preps = append(preps, e.ToJSON(s.countPenalties(e.owner, stats.startHeight, to)))
+	}
+	if owner != nil && len(preps) == 0 {
+		return nil, errors.NotFoundError.Errorf("NotElectedPRep(sequence=%d,address=%s)", sequence, owner)
+	}
+
+	jso := make(map[string]interface{})
+	jso["sequence"] = stats.sequence
+	jso["startBlockHeight"] = stats.startHeight
+	jso["endBlockHeight"] = stats.EndHeight()
+	jso["preps"] = preps
+	return jso, nil
+}
+
+// GetPenaltyHistoryInJSON returns penalties of the PRep from start-th to
+// end-th ones (1-based, inclusive). Zero start or end means the first one
+// or the last one of the page, and a page has MaxPenaltyHistoryQuerySize
+// penalties at most.
+func (s *State) GetPenaltyHistoryInJSON(owner module.Address, start, end int) (map[string]interface{}, error) {
+	if start < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("start(%d) < 0", start)
+	}
+	if end < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("end(%d) < 0", end)
+	}
+	if start == 0 {
+		start = 1
+	}
+	if end == 0 {
+		end = start + MaxPenaltyHistoryQuerySize - 1
+	}
+	if start > end {
+		return nil, errors.IllegalArgumentError.Errorf("start(%d) > end(%d)", start, end)
+	}
+	if end-start >= MaxPenaltyHistoryQuerySize {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"TooManyPenalties(start=%d,end=%d,max=%d)", start, end, MaxPenaltyHistoryQuerySize)
+	}
+	total := s.GetPenaltyCount(owner)
+	if start > total && total > 0 {
+		return nil, errors.IllegalArgumentError.Errorf("start(%d) > # of penalties(%d)", start, total)
+	}
+
+	records := s.GetPenaltyHistory(owner, start-1, end)
+	penalties := make([]interface{}, len(records))
+	for i, r := range records {
+		penalties[i] = r.ToJSON()
+	}
+	return map[string]interface{}{
+		"address":   owner,
+		"start":     start,
+		"total":     total,
+		"penalties": penalties,
+	}, nil
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package icstate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/icon/icmodule"
+	"github.com/icon-project/goloop/icon/iiss/icobject"
+	"github.com/icon-project/goloop/module"
+)
+
+func newTestTerm(sequence int, startHeight, period int64, mainPRepCount int, owners ...module.Address) *TermSnapshot {
+	pss := make(PRepSnapshots, len(owners))
+	for i, owner := range owners {
+		pss[i] = NewPRepSnapshot(owner, big.NewInt(int64(100-i)))
+	}
+	term := newTermState(sequence, period)
+	term.startHeight = startHeight
+	term.isDecentralized = true
+	term.mainPRepCount = mainPRepCount
+	term.prepSnapshots = pss
+	return term.GetSnapshot()
+}
+
+func statsOf(t *testing.T, jso map[string]interface{}, owner module.Address) map[string]interface{} {
+	for _, e := range jso["preps"].([]interface{}) {
+		entry := e.(map[string]interface{})
+		if owner.Equal(entry["address"].(module.Address)) {
+			return entry
+		}
+	}
+	t.Fatalf("NoStatsFor(%s)", owner)
+	return nil
+}
+
+func TestPRepTermStats_Bytes(t *testing.T) {
+	database := icobject.AttachObjectFactory(db.NewMapDB(), NewObjectImpl)
+	term := newTestTerm(1, 100, 10, 1, newDummyAddress(1), newDummyAddress(2))
+	stats := NewPRepTermStats(term, []*PRepTermStatsEntry{
+		{owner: newDummyAddress(1).(*common.Address), grade: GradeMain, power: big.NewInt(10), startTotal: 5, total: 10, fail: 1},
+		{owner: newDummyAddress(2).(*common.Address), grade: GradeSub, power: big.NewInt(5)},
+	})
+
+	for _, o1 := range []*icobject.Object{
+		icobject.New(TypePRepTermStats, stats),
+		icobject.New(TypePenaltyRecord, NewPenaltyRecord(105, icmodule.PenaltyBlockValidation, big.NewInt(100))),
+	} {
+		serialized := o1.Bytes()
+		o2 := new(icobject.Object)
+		assert.NoError(t, o2.Reset(database, serialized))
+		assert.Equal(t, serialized, o2.Bytes())
+		assert.True(t, o1.Equal(o2))
+	}
+}
+
+func TestState_PRepTermStats(t *testing.T) {
+	s := newDummyState(false)
+	owners := make([]module.Address, 3)
+	for i := range owners {
+		owners[i] = newDummyAddress(i + 1)
+		assert.NoError(t, s.RegisterPRep(owners[i], newDummyPRepInfo(i), icmodule.BigIntInitialIRep, 0))
+	}
+	term := newTestTerm(3, 100, 10, 1, owners[0], owners[1])
+	assert.NoError(t, s.SetTermSnapshot(term))
+	assert.NoError(t, s.OnPRepTermStart(term, 99))
+
+	ps0 := s.GetPRepStatusByOwner(owners[0], false)
+	assert.NoError(t, ps0.OnBlockVote(100, true))
+	assert.NoError(t, ps0.OnBlockVote(105, false))
+	ps1 := s.GetPRepStatusByOwner(owners[1], false)
+	assert.NoError(t, ps1.OnBlockVote(100, true))
+
+	assert.NoError(t, s.AddPenaltyRecord(owners[0], NewPenaltyRecord(50, icmodule.PenaltyBlockValidation, new(big.Int))))
+	assert.NoError(t, s.AddPenaltyRecord(owners[0], NewPenaltyRecord(107, icmodule.PenaltyBlockValidation, big.NewInt(10))))
+
+	// counters of the current term are counted until the block height
+	jso, err := s.GetPRepTermStatsInJSON(3, nil, 106)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), jso["startBlockHeight"])
+	assert.Equal(t, int64(109), jso["endBlockHeight"])
+	assert.Equal(t, 2, len(jso["preps"].([]interface{})))
+	entry := statsOf(t, jso, owners[0])
+	assert.Equal(t, int64(7), entry["total"])
+	assert.Equal(t, int64(2), entry["fail"])
+	assert.Equal(t, 0, entry["penalties"])
+	assert.Equal(t, int(GradeMain), entry["grade"])
+
+	// counters are fixed at the end of the term
+	assert.NoError(t, s.OnPRepTermEnd(term, 109))
+	assert.NoError(t, s.SetTermSnapshot(newTestTerm(4, 110, 10, 1, owners[1], owners[0])))
+	s = flushAndNewState(s, false)
+	jso, err = s.GetPRepTermStatsInJSON(3, nil, 200)
+	assert.NoError(t, err)
+	entry = statsOf(t, jso, owners[0])
+	assert.Equal(t, int64(10), entry["total"])
+	assert.Equal(t, int64(5), entry["fail"])
+	assert.Equal(t, 1, entry["penalties"])
+	entry = statsOf(t, jso, owners[1])
+	assert.Equal(t, int64(10), entry["total"])
+	assert.Equal(t, int64(0), entry["fail"])
+	assert.Equal(t, int(GradeSub), entry["grade"])
+
+	jso, err = s.GetPRepTermStatsInJSON(3, owners[1], 200)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(jso["preps"].([]interface{})))
+
+	_, err = s.GetPRepTermStatsInJSON(3, owners[2], 200)
+	assert.True(t, errors.NotFoundError.Equals(err))
+	_, err = s.GetPRepTermStatsInJSON(4, nil, 200)
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	// the term started before the history is enabled
+	assert.NoError(t, s.OnPRepTermEnd(newTestTerm(4, 110, 10, 1, owners[1]), 119))
+	assert.Nil(t, s.GetPRepTermStats(4))
+}
+
+func TestState_PenaltyHistory(t *testing.T) {
+	s := newDummyState(false)
+	owner := newDummyAddress(1)
+	assert.Zero(t, len(s.GetPenaltyHistory(owner, 0, MaxPenaltyHistoryQuerySize)))
+
+	records := []*PenaltyRecord{
+		NewPenaltyRecord(10, icmodule.PenaltyBlockValidation, new(big.Int)),
+		NewPenaltyRecord(20, icmodule.PenaltyPRepDisqualification, big.NewInt(100)),
+		NewPenaltyRecord(30, icmodule.PenaltyNonVote, big.NewInt(10)),
+	}
+	for _, r := range records {
+		assert.NoError(t, s.AddPenaltyRecord(owner, r))
+	}
+	s = flushAndNewState(s, false)
+
+	assert.Equal(t, len(records), s.GetPenaltyCount(owner))
+	history := s.GetPenaltyHistory(owner, 0, MaxPenaltyHistoryQuerySize)
+	assert.Equal(t, len(records), len(history))
+	for i, r := range history {
+		assert.True(t, records[i].Equal(r))
+	}
+	history = s.GetPenaltyHistory(owner, 1, 2)
+	assert.Equal(t, 1, len(history))
+	assert.True(t, records[1].Equal(history[0]))
+	assert.Zero(t, len(s.GetPenaltyHistory(newDummyAddress(2), 0, MaxPenaltyHistoryQuerySize)))
+	assert.Equal(t, 2, s.countPenalties(owner, 15, 30))
+	assert.Equal(t, 1, s.countPenalties(owner, 0, 19))
+
+	jso, err := s.GetPenaltyHistoryInJSON(owner, 0, 0)
+	assert.NoError(t, err)
+	assert.True(t, owner.Equal(jso["address"].(module.Address)))
+	assert.Equal(t, len(records), jso["total"])
+	penalties := jso["penalties"].([]interface{})
+	assert.Equal(t, len(records), len(penalties))
+	assert.Equal(t, int64(20), penalties[1].(map[string]interface{})["blockHeight"])
+	assert.Equal(t, int(icmodule.PenaltyPRepDisqualification), penalties[1].(map[string]interface{})["type"])
+
+	jso, err = s.GetPenaltyHistoryInJSON(owner, 2, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, jso["start"])
+	penalties = jso["penalties"].([]interface{})
+	assert.Equal(t, 2, len(penalties))
+	assert.Equal(t, int64(30), penalties[1].(map[string]interface{})["blockHeight"])
+
+	for _, r := range [][2]int{{-1, 0}, {3, 2}, {4, 0}, {1, MaxPenaltyHistoryQuerySize + 1}} {
+		_, err = s.GetPenaltyHistoryInJSON(owner, r[0], r[1])
+		assert.Error(t, err, "start=%d end=%d", r[0], r[1])
+	}
+}
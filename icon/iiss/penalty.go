@@ -23,6 +23,7 @@ import (
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/icon/icmodule"
 	"github.com/icon-project/goloop/icon/iiss/icstage"
+	"github.com/icon-project/goloop/icon/iiss/icstate"
 	"github.com/icon-project/goloop/icon/iiss/icutils"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/service/state"
@@ -59,20 +60,34 @@ func (es *ExtensionStateImpl) handlePenalty(cc icmodule.CallContext, owner modul
 
 	// Slashing
 	revision := cc.Revision().Value()
+	slashed := new(big.Int)
 	if es.State.CheckConsistentValidationPenalty(revision, ps) {
 		slashRatio := es.State.GetConsistentValidationPenaltySlashRatio()
-		if err = es.slash(cc, owner, slashRatio); err != nil {
+		if slashed, err = es.slash(cc, owner, slashRatio); err != nil {
 			return err
 		}
 	}
+	if err = es.addPenaltyRecord(cc, owner, icmodule.PenaltyBlockValidation, slashed); err != nil {
+		return err
+	}
 
 	// Record event for reward calculation
 	return es.addEventEnable(blockHeight, owner, icstage.ESDisableTemp)
 }
 
-func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Address, ratio int) error {
+// addPenaltyRecord records the penalty to the history of the PRep.
+func (es *ExtensionStateImpl) addPenaltyRecord(
+	cc icmodule.CallContext, owner module.Address, pt icmodule.PenaltyType, slashed *big.Int) error {
+	if cc.Revision().Value() < icmodule.RevisionPRepHistory {
+		return nil
+	}
+	return es.State.AddPenaltyRecord(owner, icstate.NewPenaltyRecord(cc.BlockHeight(), pt, slashed))
+}
+
+// slash slashes bonds of the PRep by ratio and returns the amount of slashed stake.
+func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Address, ratio int) (*big.Int, error) {
 	if ratio < 0 || 100 < ratio {
-		return errors.Errorf("Invalid slash ratio %d", ratio)
+		return nil, errors.Errorf("Invalid slash ratio %d", ratio)
 	}
 
 	logger := cc.FrameLogger()
@@ -80,7 +95,7 @@ func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Addres
 
 	pb := es.State.GetPRepBaseByOwner(owner, false)
 	if pb == nil {
-		return errors.Errorf("PRep not found: %s", owner)
+		return nil, errors.Errorf("PRep not found: %s", owner)
 	}
 	bonders := pb.BonderList()
 	slashedBondSum := new(big.Int)
@@ -108,14 +123,14 @@ func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Addres
 				if timer != nil {
 					timer.Delete(owner)
 				} else {
-					return errors.Errorf("timer doesn't exist for height %d", expire)
+					return nil, errors.Errorf("timer doesn't exist for height %d", expire)
 				}
 			}
 
 			// stake
 			slashedStake.Add(slashedBond, slashedUnbond)
 			if err := account.SlashStake(slashedStake); err != nil {
-				return err
+				return nil, err
 			}
 			slashedStakeSum.Add(slashedStakeSum, slashedStake)
 
@@ -124,7 +139,7 @@ func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Addres
 				icutils.ToKey(owner): new(big.Int).Neg(slashedBond),
 			}
 			if err := es.AddEventBond(cc.BlockHeight(), bonder, delta); err != nil {
-				return err
+				return nil, err
 			}
 		}
 
@@ -145,10 +160,10 @@ func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Addres
 	oldTotalStake := es.State.GetTotalStake()
 	newTotalStake := new(big.Int).Sub(oldTotalStake, slashedStakeSum)
 	if err := es.State.SetTotalStake(newTotalStake); err != nil {
-		return err
+		return nil, err
 	}
 	if err := es.State.ReducePRepBonded(owner, slashedBondSum); err != nil {
-		return err
+		return nil, err
 	}
 	err := cc.HandleBurn(state.SystemAddress, slashedStakeSum)
 
@@ -156,5 +171,5 @@ func (es *ExtensionStateImpl) slash(cc icmodule.CallContext, owner module.Addres
 		"IISS slash end owner=%s slashedBondSum=%v slashedStakeSum=%v oldTotalStake=%v newTotalStake=%v",
 		owner, slashedBondSum, slashedStakeSum, oldTotalStake, newTotalStake,
 	)
-	return err
+	return slashedStakeSum, err
 }
@@ -270,6 +270,10 @@ func (sm *ServiceManager) WaitTransactionResult(id []byte) (<-chan interface{},
 	return nil, errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) WatchTransactionPool(w module.TransactionPoolWatcher) func() {
+	return func() {}
+}
+
 func (sm *ServiceManager) ExportResult(result []byte, vh []byte, dst db.Database) error {
 	return errors.ErrInvalidState
 }
@@ -282,6 +286,10 @@ func (sm *ServiceManager) ExecuteTransaction(result []byte, vh []byte, js []byte
 	return nil, errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) SimulateTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo, ti *module.TraceInfo) (module.Receipt, error) {
+	return nil, errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) AddSyncRequest(id db.BucketID, key []byte) error {
 	return errors.ErrInvalidState
 }
@@ -377,6 +385,14 @@ func (sm *ServiceManager) GetSCOREStatus(result []byte, addr module.Address) (mo
 	return nil, common.ErrInvalidState
 }
 
+func (sm *ServiceManager) GetProofForAccount(result []byte, addr module.Address) (module.StateProof, error) {
+	return nil, common.ErrInvalidState
+}
+
+func (sm *ServiceManager) GetProofForStorage(result []byte, addr module.Address, key []byte) (module.StateProof, error) {
+	return nil, common.ErrInvalidState
+}
+
 func NewServiceManagerWithExecutor(chain module.Chain, ex *Executor, ps BlockV1ProofStorage, vs []*common.Address, cb ImportCallback) (*ServiceManager, error) {
 	logger := chain.Logger()
 	dbase := chain.Database()
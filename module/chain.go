@@ -31,6 +31,12 @@ type Chain interface {
 	ConcurrencyLevel() int
 	NormalTxPoolSize() int
 	PatchTxPoolSize() int
+	// TxPoolSenderLimit returns the maximum number of transactions from
+	// a sender in the normal transaction pool. 0 means no limit.
+	TxPoolSenderLimit() int
+	// FastSyncWindow returns the number of blocks fetched ahead in fast
+	// sync. 0 means the default of fast sync.
+	FastSyncWindow() int
 	MaxBlockTxBytes() int
 	DefaultWaitTimeout() time.Duration
 	MaxWaitTimeout() time.Duration
@@ -38,6 +44,7 @@ type Chain interface {
 	ChildrenLimit() int
 	NephewsLimit() int
 	ValidateTxOnSend() bool
+	EventIndex() bool
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
 	CommitVoteSetDecoder() CommitVoteSetDecoder
@@ -55,6 +62,14 @@ type Chain interface {
 	Import(src string, height int64) error
 	Prune(gs string, dbt string, height int64) error
 	Backup(file string, extra []string) error
+	// BackupIncremental makes the backup including only changes after
+	// the previous backup.
+	BackupIncremental(file string, extra []string) error
+	// Export writes blocks from the height from to the height to in the
+	// portable archive. flags are the flags of the archive.
+	Export(file string, from, to int64, flags int) error
+	// ImportArchive imports blocks in the archive after verifying them.
+	ImportArchive(file string) error
 	RunTask(task string, params json.RawMessage) error
 	Term() error
 	State() (string, int64, error)
@@ -68,6 +83,13 @@ type Chain interface {
 	// height and the function cleans up database and file systems for the chain
 	// and prepare pruned genesis block of the height.
 	Reset(gs string, height int64, blockHash []byte) error
+	// Bootstrap resets chain to the trusted block of the height like Reset,
+	// then starts the chain to sync following blocks. The trusted block is
+	// kept in the configuration until the chain starts, so it could be
+	// called again after restart to resume bootstrapping.
+	// height shall be greater than 1 and blockHash shall be the hash of the
+	// block with the height.
+	Bootstrap(gs string, height int64, blockHash []byte) error
 	Verify() error
 
 	MetricContext() context.Context
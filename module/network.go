@@ -1,6 +1,9 @@
 package module
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type NetworkManager interface {
 	Start() error
@@ -21,6 +24,17 @@ type NetworkManager interface {
 
 	SetTrustSeeds(seeds string)
 	SetInitialRoles(roles ...Role)
+
+	BanPeer(id PeerID, duration time.Duration, reason string) error
+	UnbanPeer(id PeerID) error
+	GetBannedPeers() []*BannedPeer
+}
+
+// BannedPeer is a peer not allowed to connect until the time.
+type BannedPeer struct {
+	ID     PeerID
+	Reason string
+	Until  time.Time
 }
 
 type Reactor interface {
@@ -35,6 +49,40 @@ type ProtocolHandler interface {
 	Multicast(pi ProtocolInfo, b []byte, role Role) error
 	Unicast(pi ProtocolInfo, b []byte, id PeerID) error
 	GetPeers() []PeerID
+	// ReportPeer reports misbehavior of the peer. The peer is banned
+	// if it's reported repeatedly.
+	ReportPeer(id PeerID, reason PenaltyReason)
+}
+
+type PenaltyReason byte
+
+const (
+	PenaltyInvalidPacket PenaltyReason = iota
+	PenaltyInvalidMessage
+	PenaltyInvalidBlockPart
+	PenaltyFastSyncFailure
+	PenaltyInvalidSyncData
+	PenaltyInvalidTransaction
+	PenaltyReserved
+)
+
+func (r PenaltyReason) String() string {
+	switch r {
+	case PenaltyInvalidPacket:
+		return "InvalidPacket"
+	case PenaltyInvalidMessage:
+		return "InvalidMessage"
+	case PenaltyInvalidBlockPart:
+		return "InvalidBlockPart"
+	case PenaltyFastSyncFailure:
+		return "FastSyncFailure"
+	case PenaltyInvalidSyncData:
+		return "InvalidSyncData"
+	case PenaltyInvalidTransaction:
+		return "InvalidTransaction"
+	default:
+		return fmt.Sprintf("Penalty(%d)", r)
+	}
 }
 
 type BroadcastType byte
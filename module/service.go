@@ -242,6 +242,10 @@ type SCOREStatus interface {
 	ToJSON(height int64, version JSONVersion) (interface{}, error)
 }
 
+type StateProof interface {
+	ToJSON(version JSONVersion) (interface{}, error)
+}
+
 // Options for finalize
 const (
 	FinalizeNormalTransaction = 1 << iota
@@ -297,6 +301,19 @@ type TransitionManager interface {
 	WaitForTransaction(parent Transition, bi BlockInfo, cb func()) bool
 }
 
+// TransactionPoolWatcher is notified of changes of the transaction pool.
+// It's called after the pool is unlocked, but it may be called
+// concurrently and it must not block.
+type TransactionPoolWatcher interface {
+	// OnTransactionAdd is called when the transaction is accepted
+	// into the pool.
+	OnTransactionAdd(tx Transaction)
+
+	// OnTransactionDrop is called when the transaction is dropped from
+	// the pool without being included in a block.
+	OnTransactionDrop(tx Transaction, reason error)
+}
+
 type ServiceManager interface {
 	TransitionManager
 
@@ -353,6 +370,13 @@ type ServiceManager interface {
 	// GetSCOREStatus returns status of the contract
 	GetSCOREStatus(result []byte, addr Address) (SCOREStatus, error)
 
+	// GetProofForAccount returns the proof of the account in the state
+	GetProofForAccount(result []byte, addr Address) (StateProof, error)
+
+	// GetProofForStorage returns the proof of the account and the proof
+	// of the value for the key in the storage of the account
+	GetProofForStorage(result []byte, addr Address, key []byte) (StateProof, error)
+
 	// GetMembers returns network member list
 	GetMembers(result []byte) (MemberList, error)
 
@@ -389,6 +413,10 @@ type ServiceManager interface {
 	// WaitTransactionResult return channel for result.
 	WaitTransactionResult(id []byte) (<-chan interface{}, error)
 
+	// WatchTransactionPool registers the watcher for changes of
+	// the transaction pool. It returns the function to cancel it.
+	WatchTransactionPool(w TransactionPoolWatcher) func()
+
 	// ExportResult exports all related entries related with the result
 	// should be exported to the database
 	ExportResult(result []byte, vh []byte, dst db.Database) error
@@ -402,6 +430,13 @@ type ServiceManager interface {
 	// It ignores supplied step limit.
 	ExecuteTransaction(result []byte, vh []byte, js []byte, bi BlockInfo) (Receipt, error)
 
+	// SimulateTransaction executes the transaction on the specified state
+	// as if it's included in the block with the block info. Unlike
+	// ExecuteTransaction, it applies the step limit of the transaction
+	// and checks the balance of the sender. If ti is not nil, the execution
+	// is traced with it.
+	SimulateTransaction(result []byte, vh []byte, js []byte, bi BlockInfo, ti *TraceInfo) (Receipt, error)
+
 	// AddSyncRequest add sync request for specified data.
 	AddSyncRequest(id db.BucketID, key []byte) error
 }
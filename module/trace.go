@@ -41,6 +41,7 @@ const (
 	TraceModeNone TraceMode = iota
 	TraceModeInvoke
 	TraceModeBalanceChange
+	TraceModeStateDiff
 )
 
 type OpType int
@@ -63,6 +64,24 @@ const (
 	RegPRep
 )
 
+type StateChangeType int
+
+const (
+	// StateChangeStorage is a change of the value for the key in the
+	// storage of the account.
+	StateChangeStorage StateChangeType = iota
+	// StateChangeBalance is a change of the balance. Values are
+	// the balances encoded by intconv.BigIntToBytes.
+	StateChangeBalance
+	// StateChangeCode is a change of the code hash of the contract.
+	// Key is either "current" or "next".
+	StateChangeCode
+	// StateChangeStatus is a change of the status of the account.
+	// Key is either "disabled", "blocked" or "contract", and values are
+	// string representations of the status.
+	StateChangeStatus
+)
+
 type ExecutionPhase int
 
 const (
@@ -80,4 +99,5 @@ type TraceCallback interface {
 	OnFrameEnter() error
 	OnFrameExit(success bool) error
 	OnBalanceChange(opType OpType, from, to Address, amount *big.Int) error
+	OnStateChange(ct StateChangeType, addr Address, key, oldValue, newValue []byte) error
 }
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -52,6 +53,9 @@ func NewManager(c module.Chain, nt module.NetworkTransport, trustSeeds string, r
 		m.mtr,
 		m.logger)
 
+	m.p2p.rep = newPeerReputation(c.Database(), m.p2p.logger)
+	m.p2p.rep.onBan = m.p2p.onBan
+
 	m.SetInitialRoles(roles...)
 	m.SetTrustSeeds(trustSeeds)
 
@@ -250,6 +254,18 @@ func (m *manager) SetInitialRoles(roles ...module.Role) {
 	m.p2p.setRole(NewPeerRoleFlag(roles...))
 }
 
+func (m *manager) BanPeer(id module.PeerID, duration time.Duration, reason string) error {
+	return m.p2p.rep.ban(id, duration, reason)
+}
+
+func (m *manager) UnbanPeer(id module.PeerID) error {
+	return m.p2p.rep.unban(id)
+}
+
+func (m *manager) GetBannedPeers() []*module.BannedPeer {
+	return m.p2p.rep.bannedPeers()
+}
+
 func ChannelOfNetID(id int) string {
 	return strconv.FormatInt(int64(id), 16)
 }
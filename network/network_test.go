@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 )
@@ -297,6 +298,7 @@ func (c *dummyChain) MetricContext() context.Context        { return c.metricCtx
 func (c *dummyChain) ChildrenLimit() int                    { return -1 }
 func (c *dummyChain) NephewsLimit() int                     { return -1 }
 func (c *dummyChain) NetworkManager() module.NetworkManager { return c.nm }
+func (c *dummyChain) Database() db.Database                 { return nil }
 
 type dummyReactor struct{}
 
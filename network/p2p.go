@@ -104,6 +104,9 @@ type PeerToPeer struct {
 	//monitor
 	mtr *metric.NetworkMetric
 
+	//reputation
+	rep *peerReputation
+
 	stopCh chan bool
 	run    bool
 	mtx    sync.RWMutex
@@ -282,6 +285,10 @@ func (p2p *PeerToPeer) onPeer(p *Peer) {
 		p.CloseByError(fmt.Errorf("onPeer not allowed connection"))
 		return
 	}
+	if p2p.rep != nil && p2p.rep.isBanned(p.ID()) {
+		p.CloseByError(fmt.Errorf("onPeer banned peer"))
+		return
+	}
 	if p2p.isTrustSeed(p) {
 		p2p.trustSeeds.SetAndRemoveByData(p.DialNetAddress(), string(p.NetAddress()))
 	}
@@ -290,6 +297,26 @@ func (p2p *PeerToPeer) onPeer(p *Peer) {
 	}
 }
 
+// reportPeer penalizes the peer for the reason. Validators are not banned
+// automatically.
+func (p2p *PeerToPeer) reportPeer(id module.PeerID, reason module.PenaltyReason) {
+	if p2p.rep == nil {
+		return
+	}
+	p2p.logger.Infoln("reportPeer", id, reason)
+	p2p.rep.penalize(id, reason, p2p.allowedRoots.Contains(id))
+}
+
+//callback from peerReputation on ban
+func (p2p *PeerToPeer) onBan(id module.PeerID, reason string) {
+	ps := p2p.findPeers(func(p *Peer) bool {
+		return p.ID().Equal(id)
+	})
+	for _, p := range ps {
+		p.CloseByError(fmt.Errorf("banned by %s", reason))
+	}
+}
+
 func (p2p *PeerToPeer) onClose(p *Peer) {
 	p2p.connMtx.Lock()
 	defer p2p.connMtx.Unlock()
@@ -354,6 +381,7 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 	//	return
 	//}
 	if !p.ProtocolInfos().Exists(pkt.protocol) {
+		p2p.reportPeer(p.ID(), module.PenaltyInvalidPacket)
 		p.CloseByError(ErrNotRegisteredProtocol)
 		return
 	}
@@ -374,6 +402,7 @@ func (p2p *PeerToPeer) onPacket(pkt *Packet, p *Peer) {
 			case p2pProtoConnResp:
 				p2p.handleP2PConnectionResponse(pkt, p)
 			default:
+				p2p.reportPeer(p.ID(), module.PenaltyInvalidPacket)
 				p.CloseByError(ErrNotRegisteredProtocol)
 			}
 		default:
@@ -164,6 +164,7 @@ func (ph *protocolHandler) onPacket(pkt *Packet, p *Peer) {
 		case module.NotRegisteredProtocolPolicyClose:
 			fallthrough
 		default:
+			ph.m.p2p.reportPeer(p.ID(), module.PenaltyInvalidPacket)
 			p.CloseByError(ErrNotRegisteredProtocol)
 			ph.logger.Infoln("onPacket", "not registered protocol", ph.name, pkt.protocol, pkt.subProtocol, p.ID())
 		}
@@ -271,3 +272,7 @@ func (ph *protocolHandler) Broadcast(pi module.ProtocolInfo, b []byte, bt module
 func (ph *protocolHandler) GetPeers() []module.PeerID {
 	return ph.m.getPeersByProtocol(ph.protocol)
 }
+
+func (ph *protocolHandler) ReportPeer(id module.PeerID, reason module.PenaltyReason) {
+	ph.m.p2p.reportPeer(id, reason)
+}
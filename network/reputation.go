@@ -0,0 +1,248 @@
+package network
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	DefaultPeerBanThreshold = 100
+	DefaultPeerBanDuration  = 1 * time.Hour
+	DefaultPeerScoreDecay   = 10 // per minute
+	peerScoresSweepSize     = 1000
+	peerBansKey             = "network.bans"
+)
+
+var penaltyScores = map[module.PenaltyReason]int{
+	module.PenaltyInvalidPacket:      20,
+	module.PenaltyInvalidMessage:     20,
+	module.PenaltyInvalidBlockPart:   25,
+	module.PenaltyFastSyncFailure:    25,
+	module.PenaltyInvalidSyncData:    25,
+	module.PenaltyInvalidTransaction: 10,
+}
+
+type peerScore struct {
+	score   int
+	updated time.Time
+}
+
+// decay reduces the score as time goes, so that only repeated
+// misbehaviors in short time lead to ban.
+func (s *peerScore) decay(now time.Time) {
+	minutes := now.Sub(s.updated) / time.Minute
+	if minutes > 0 {
+		s.score -= int(minutes) * DefaultPeerScoreDecay
+		if s.score < 0 {
+			s.score = 0
+		}
+		s.updated = s.updated.Add(minutes * time.Minute)
+	}
+}
+
+type peerBan struct {
+	ID     []byte
+	Reason string
+	Until  int64
+}
+
+// peerReputation scores misbehaviors of peers and bans the peers over
+// the threshold. Bans are stored in the database of the chain.
+type peerReputation struct {
+	mtx    sync.Mutex
+	scores map[string]*peerScore
+	bans   map[string]*peerBan
+	bk     db.Bucket
+	logger log.Logger
+	onBan  func(id module.PeerID, reason string)
+}
+
+func newPeerReputation(database db.Database, l log.Logger) *peerReputation {
+	r := &peerReputation{
+		scores: make(map[string]*peerScore),
+		bans:   make(map[string]*peerBan),
+		logger: l,
+	}
+	if database != nil {
+		bk, err := database.GetBucket(db.ChainProperty)
+		if err != nil {
+			l.Warnf("fail to get bucket for bans err=%+v", err)
+		} else {
+			r.bk = bk
+			r.load()
+		}
+	}
+	return r
+}
+
+func (r *peerReputation) load() {
+	bs, err := r.bk.Get([]byte(peerBansKey))
+	if err != nil || bs == nil {
+		return
+	}
+	var bans []*peerBan
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &bans); err != nil {
+		r.logger.Warnf("fail to load bans err=%+v", err)
+		return
+	}
+	now := time.Now().UnixNano()
+	for _, b := range bans {
+		if b.Until > now {
+			r.bans[string(b.ID)] = b
+		}
+	}
+}
+
+func (r *peerReputation) _store() {
+	if r.bk == nil {
+		return
+	}
+	bans := make([]*peerBan, 0, len(r.bans))
+	for _, b := range r.bans {
+		bans = append(bans, b)
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return string(bans[i].ID) < string(bans[j].ID)
+	})
+	if err := r.bk.Set([]byte(peerBansKey), codec.BC.MustMarshalToBytes(bans)); err != nil {
+		r.logger.Warnf("fail to store bans err=%+v", err)
+	}
+}
+
+func (r *peerReputation) _expire(now time.Time) {
+	expired := false
+	for k, b := range r.bans {
+		if b.Until <= now.UnixNano() {
+			delete(r.bans, k)
+			expired = true
+		}
+	}
+	if expired {
+		r._store()
+	}
+}
+
+func (r *peerReputation) isBanned(id module.PeerID) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	b, ok := r.bans[string(id.Bytes())]
+	return ok && b.Until > time.Now().UnixNano()
+}
+
+func (r *peerReputation) ban(id module.PeerID, d time.Duration, reason string) error {
+	if d <= 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidDuration(d=%s)", d)
+	}
+	r.mtx.Lock()
+	r._ban(id, d, reason)
+	onBan := r.onBan
+	r.mtx.Unlock()
+
+	if onBan != nil {
+		onBan(id, reason)
+	}
+	return nil
+}
+
+func (r *peerReputation) _ban(id module.PeerID, d time.Duration, reason string) {
+	r.logger.Infof("ban peer=%s duration=%s reason=%s", id, d, reason)
+	r.bans[string(id.Bytes())] = &peerBan{
+		ID:     id.Bytes(),
+		Reason: reason,
+		Until:  time.Now().Add(d).UnixNano(),
+	}
+	delete(r.scores, string(id.Bytes()))
+	r._store()
+}
+
+func (r *peerReputation) unban(id module.PeerID) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	k := string(id.Bytes())
+	if _, ok := r.bans[k]; !ok {
+		return errors.NotFoundError.Errorf("NotBanned(id=%s)", id)
+	}
+	r.logger.Infof("unban peer=%s", id)
+	delete(r.bans, k)
+	r._store()
+	return nil
+}
+
+func (r *peerReputation) bannedPeers() []*module.BannedPeer {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r._expire(time.Now())
+	l := make([]*module.BannedPeer, 0, len(r.bans))
+	for _, b := range r.bans {
+		l = append(l, &module.BannedPeer{
+			ID:     NewPeerID(b.ID),
+			Reason: b.Reason,
+			Until:  time.Unix(0, b.Until),
+		})
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Until.Before(l[j].Until)
+	})
+	return l
+}
+
+// penalize adds the score of the reason to the peer. If protected is true,
+// the peer is not banned even if it's over the threshold.
+// It returns true if the peer is banned.
+func (r *peerReputation) penalize(id module.PeerID, reason module.PenaltyReason, protected bool) bool {
+	r.mtx.Lock()
+	k := string(id.Bytes())
+	now := time.Now()
+	s, ok := r.scores[k]
+	if !ok {
+		if len(r.scores) >= peerScoresSweepSize {
+			r._sweep(now)
+		}
+		s = &peerScore{updated: now}
+		r.scores[k] = s
+	}
+	s.decay(now)
+	s.score += penaltyScores[reason]
+	r.logger.Debugf("penalize peer=%s reason=%s score=%d", id, reason, s.score)
+	if s.score < DefaultPeerBanThreshold || protected {
+		r.mtx.Unlock()
+		return false
+	}
+	r._ban(id, DefaultPeerBanDuration, reason.String())
+	onBan := r.onBan
+	r.mtx.Unlock()
+
+	if onBan != nil {
+		onBan(id, reason.String())
+	}
+	return true
+}
+
+func (r *peerReputation) _sweep(now time.Time) {
+	for k, s := range r.scores {
+		if s.decay(now); s.score == 0 {
+			delete(r.scores, k)
+		}
+	}
+}
+
+func (r *peerReputation) score(id module.PeerID) int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if s, ok := r.scores[string(id.Bytes())]; ok {
+		s.decay(time.Now())
+		return s.score
+	}
+	return 0
+}
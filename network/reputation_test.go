@@ -0,0 +1,76 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+func Test_reputation_PenalizeAndBan(t *testing.T) {
+	r := newPeerReputation(nil, testLogger())
+	var banned []module.PeerID
+	r.onBan = func(id module.PeerID, reason string) {
+		banned = append(banned, id)
+	}
+
+	id := generatePeerID()
+	for i := 0; i < 4; i++ {
+		assert.False(t, r.penalize(id, module.PenaltyInvalidMessage, false))
+	}
+	assert.Equal(t, 80, r.score(id))
+	assert.True(t, r.penalize(id, module.PenaltyInvalidMessage, false))
+	assert.True(t, r.isBanned(id))
+	assert.Equal(t, 0, r.score(id))
+	assert.Equal(t, []module.PeerID{id}, banned)
+
+	// protected peer isn't banned
+	vid := generatePeerID()
+	for i := 0; i < 10; i++ {
+		assert.False(t, r.penalize(vid, module.PenaltyInvalidMessage, true))
+	}
+	assert.False(t, r.isBanned(vid))
+
+	l := r.bannedPeers()
+	assert.Len(t, l, 1)
+	assert.True(t, id.Equal(l[0].ID))
+	assert.Equal(t, module.PenaltyInvalidMessage.String(), l[0].Reason)
+
+	assert.NoError(t, r.unban(id))
+	assert.False(t, r.isBanned(id))
+	assert.True(t, errors.NotFoundError.Equals(r.unban(id)))
+}
+
+func Test_reputation_Decay(t *testing.T) {
+	now := time.Now()
+	s := &peerScore{score: 50, updated: now.Add(-3*time.Minute - time.Second)}
+	s.decay(now)
+	assert.Equal(t, 50-3*DefaultPeerScoreDecay, s.score)
+	assert.Equal(t, now.Add(-time.Second), s.updated)
+
+	s.decay(now.Add(time.Hour))
+	assert.Equal(t, 0, s.score)
+}
+
+func Test_reputation_Persistence(t *testing.T) {
+	database := db.NewMapDB()
+	r := newPeerReputation(database, testLogger())
+
+	id1 := generatePeerID()
+	id2 := generatePeerID()
+	assert.Error(t, r.ban(id1, 0, "test"))
+	assert.NoError(t, r.ban(id1, time.Hour, "test"))
+	assert.NoError(t, r.ban(id2, time.Hour, "test"))
+	assert.NoError(t, r.unban(id2))
+
+	r2 := newPeerReputation(database, testLogger())
+	assert.True(t, r2.isBanned(id1))
+	assert.False(t, r2.isBanned(id2))
+	l := r2.bannedPeers()
+	assert.Len(t, l, 1)
+	assert.Equal(t, "test", l[0].Reason)
+}
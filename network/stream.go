@@ -158,6 +158,10 @@ func (r *streamReactor) GetPeers() []module.PeerID {
 	return r.ph.GetPeers()
 }
 
+func (r *streamReactor) ReportPeer(id module.PeerID, reason module.PenaltyReason) {
+	r.ph.ReportPeer(id, reason)
+}
+
 func newStream(r *streamReactor, id module.PeerID) *stream {
 	return &stream{
 		r:  r,
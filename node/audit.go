@@ -0,0 +1,410 @@
+package node
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+const (
+	AuditSourceAdmin = "admin"
+	AuditSourceCLI   = "cli"
+
+	AuditUserLocal     = "local"
+	AuditUserAnonymous = "anonymous"
+
+	auditMaxParamSize    = 64 * 1024
+	auditMaxErrorLength  = 1024
+	auditDefaultLimit    = 100
+	auditMaxRecordLength = 1024 * 1024 * 4
+	auditMaxFileSize     = 64 * 1024 * 1024
+)
+
+// AuditRecord is a record of an administrative operation. Records are
+// chained by hashes, so modification of a record can be detected by
+// verifying hashes from the first record.
+type AuditRecord struct {
+	Seq    int64           `json:"seq"`
+	Time   string          `json:"time"`
+	Source string          `json:"source"`
+	User   string          `json:"user"`
+	Remote string          `json:"remote,omitempty"`
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Query  string          `json:"query,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	// Truncated is set if Params is dropped for its size.
+	Truncated bool            `json:"truncated,omitempty"`
+	Status    int             `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	Duration  string          `json:"duration"`
+	PrevHash  common.HexBytes `json:"prevHash,omitempty"`
+	Hash      common.HexBytes `json:"hash,omitempty"`
+}
+
+func (r *AuditRecord) calcHash() ([]byte, error) {
+	rc := *r
+	rc.Hash = nil
+	bs, err := json.Marshal(&rc)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.SHA3Sum256(bs), nil
+}
+
+type AuditQuery struct {
+	Limit  int
+	User   string
+	Since  time.Time
+	Verify bool
+}
+
+// AuditLog writes records of administrative operations to the file as
+// JSON lines. The file is only appended. If the file exceeds the maximum
+// size, it's rotated to the file with ".1" suffix replacing the previous
+// one, so records in the rotated file and the current file are kept.
+type AuditLog struct {
+	mtx      sync.Mutex
+	filePath string
+	f        *os.File
+	size     int64
+	maxSize  int64
+	seq      int64
+	lastHash []byte
+}
+
+func (l *AuditLog) rotatedPath() string {
+	return l.filePath + ".1"
+}
+
+func readAuditRecords(filePath string, cb func(r *AuditRecord) error) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 4096), auditMaxRecordLength)
+	for s.Scan() {
+		line := s.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		r := new(AuditRecord)
+		if err := json.Unmarshal(line, r); err != nil {
+			return errors.Wrapf(err, "invalid audit record line=%q", line)
+		}
+		if err := cb(r); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// readAll reads records of the rotated file and the current file in order.
+func (l *AuditLog) readAll(cb func(r *AuditRecord) error) error {
+	for _, fp := range []string{l.rotatedPath(), l.filePath} {
+		if err := readAuditRecords(fp, cb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *AuditLog) open() error {
+	f, err := os.OpenFile(l.filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.f = f
+	l.size = fi.Size()
+	return nil
+}
+
+func (l *AuditLog) rotate() error {
+	if err := l.f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(l.filePath, l.rotatedPath()); err != nil {
+		return err
+	}
+	return l.open()
+}
+
+func NewAuditLog(filePath string) (*AuditLog, error) {
+	l := &AuditLog{filePath: filePath, maxSize: auditMaxFileSize}
+	err := l.readAll(func(r *AuditRecord) error {
+		l.seq = r.Seq
+		l.lastHash = r.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *AuditLog) Append(r *AuditRecord) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	r.Seq = l.seq + 1
+	r.PrevHash = l.lastHash
+	hash, err := r.calcHash()
+	if err != nil {
+		return err
+	}
+	r.Hash = hash
+	bs, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	bs = append(bs, '\n')
+	if l.size > 0 && l.size+int64(len(bs)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err := l.f.Write(bs); err != nil {
+		return err
+	}
+	if err := l.f.Sync(); err != nil {
+		return err
+	}
+	l.size += int64(len(bs))
+	l.seq = r.Seq
+	l.lastHash = r.Hash
+	return nil
+}
+
+// Query returns the last records matching with the query. If Verify is set,
+// it verifies hashes of all records, and returns error on invalid record.
+// Records are verified from the first one remaining after rotation.
+func (l *AuditLog) Query(q *AuditQuery) ([]*AuditRecord, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = auditDefaultLimit
+	}
+	var prev *AuditRecord
+	records := make([]*AuditRecord, 0)
+	err := l.readAll(func(r *AuditRecord) error {
+		if q.Verify {
+			if prev == nil && r.Seq > 1 {
+				prev = &AuditRecord{Seq: r.Seq - 1, Hash: r.PrevHash}
+			}
+			if err := verifyAuditRecord(prev, r); err != nil {
+				return err
+			}
+			prev = r
+		}
+		if q.User != "" && r.User != q.User {
+			return nil
+		}
+		if !q.Since.IsZero() {
+			if t, err := time.Parse(time.RFC3339Nano, r.Time); err != nil || t.Before(q.Since) {
+				return nil
+			}
+		}
+		records = append(records, r)
+		if len(records) > limit {
+			records = records[1:]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func verifyAuditRecord(prev, r *AuditRecord) error {
+	var seq int64
+	var prevHash []byte
+	if prev != nil {
+		seq, prevHash = prev.Seq, prev.Hash
+	}
+	if r.Seq != seq+1 || !bytes.Equal(r.PrevHash, prevHash) {
+		return errors.InvalidStateError.Errorf(
+			"BrokenAuditChain(seq=%d,prev=%d)", r.Seq, seq)
+	}
+	hash, err := r.calcHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(hash, r.Hash) {
+		return errors.InvalidStateError.Errorf(
+			"InvalidAuditRecordHash(seq=%d)", r.Seq)
+	}
+	return nil
+}
+
+func (l *AuditLog) Close() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.f.Close()
+}
+
+// auditSecretKeys are keys of configurations having secrets as values.
+var auditSecretKeys = map[string]bool{
+	"rpcAPIKeys": true,
+}
+
+// redactAuditParams replaces the value of ConfigureParam for secrets.
+func redactAuditParams(b []byte) []byte {
+	var p map[string]interface{}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return b
+	}
+	if key, ok := p["key"].(string); !ok || !auditSecretKeys[key] {
+		return b
+	}
+	if _, ok := p["value"]; ok {
+		p["value"] = RedactedValue
+	}
+	bs, err := json.Marshal(p)
+	if err != nil {
+		return b
+	}
+	return bs
+}
+
+func auditParamsOf(b []byte) json.RawMessage {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return nil
+	}
+	if json.Valid(b) {
+		return redactAuditParams(b)
+	}
+	bs, _ := json.Marshal(string(b))
+	return bs
+}
+
+// auditBody keeps the request body read by the handler up to
+// auditMaxParamSize. It doesn't limit the body read by the handler.
+type auditBody struct {
+	io.ReadCloser
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *auditBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && !b.truncated {
+		if b.buf.Len()+n > auditMaxParamSize {
+			b.truncated = true
+			b.buf = bytes.Buffer{}
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	return n, err
+}
+
+// MiddlewareFunc returns the middleware recording requests changing
+// something. It should be placed before the middleware for authentication
+// to record the user and rejected requests. Only the method, the path and
+// the status are recorded for rejected requests, and their bodies are not
+// read.
+func (l *AuditLog) MiddlewareFunc(source string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			if req.Method == http.MethodGet || req.Method == http.MethodHead {
+				return next(ctx)
+			}
+			var body *auditBody
+			if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				body = &auditBody{ReadCloser: req.Body}
+				req.Body = body
+			}
+
+			start := time.Now()
+			err := next(ctx)
+			duration := time.Since(start)
+
+			r := &AuditRecord{
+				Time:     start.UTC().Format(time.RFC3339Nano),
+				Source:   source,
+				Remote:   ctx.RealIP(),
+				Method:   req.Method,
+				Path:     req.URL.Path,
+				Duration: duration.String(),
+			}
+			if user, ok := ctx.Get(ContextKeyUser).(string); ok {
+				r.User = user
+			} else if source == AuditSourceCLI {
+				r.User = AuditUserLocal
+			} else {
+				r.User = AuditUserAnonymous
+			}
+			if err != nil {
+				if he, ok := err.(*echo.HTTPError); ok {
+					r.Status = he.Code
+				} else {
+					r.Status = http.StatusInternalServerError
+				}
+			} else {
+				r.Status = ctx.Response().Status
+			}
+			if r.Status != http.StatusUnauthorized && r.Status != http.StatusForbidden {
+				r.Query = req.URL.RawQuery
+				if body != nil {
+					// the handler may not read whole body
+					_, _ = io.CopyN(io.Discard, body, int64(auditMaxParamSize-body.buf.Len()+1))
+					if body.truncated {
+						r.Truncated = true
+					} else {
+						r.Params = auditParamsOf(body.buf.Bytes())
+					}
+				} else if req.MultipartForm != nil {
+					if v := req.MultipartForm.Value["json"]; len(v) > 0 {
+						if len(v[0]) > auditMaxParamSize {
+							r.Truncated = true
+						} else {
+							r.Params = auditParamsOf([]byte(v[0]))
+						}
+					}
+				}
+				if err != nil {
+					r.Error = err.Error()
+					if len(r.Error) > auditMaxErrorLength {
+						r.Error = r.Error[:auditMaxErrorLength]
+					}
+				}
+			}
+			if aerr := l.Append(r); aerr != nil {
+				log.Errorf("fail to write audit record err=%+v", aerr)
+			}
+			return err
+		}
+	}
+}
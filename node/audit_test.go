@@ -0,0 +1,211 @@
+package node
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+func TestAuditLog_Basic(t *testing.T) {
+	file := path.Join(t.TempDir(), "audit.log")
+	au, err := NewAuditLog(file)
+	assert.NoError(t, err)
+
+	a := NewAuth("", "/admin")
+	e := echo.New()
+	g := e.Group("/admin/chain", au.MiddlewareFunc(AuditSourceAdmin), a.MiddlewareFunc())
+	g.POST(UrlChainRes+"/start", func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})
+	g.POST(UrlChainRes+"/reset", func(ctx echo.Context) error {
+		return echo.ErrBadRequest
+	})
+	g.GET(UrlChainRes, func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})
+	cg := e.Group("/chain", au.MiddlewareFunc(AuditSourceCLI))
+	cg.POST(UrlChainRes+"/stop", func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	})
+
+	admin, other := wallet.New(), wallet.New()
+	assert.NoError(t, a.AddUser(admin.Address().String(), nil))
+
+	assert.Equal(t, http.StatusOK,
+		doAuthRequest(e, admin, http.MethodPost, "/admin/chain/0x1/start"))
+	assert.Equal(t, http.StatusBadRequest,
+		doAuthRequest(e, admin, http.MethodPost, "/admin/chain/0x1/reset"))
+	assert.Equal(t, http.StatusUnauthorized,
+		doAuthRequest(e, other, http.MethodPost, "/admin/chain/0x1/start"))
+	// GET requests are not recorded
+	assert.Equal(t, http.StatusOK,
+		doAuthRequest(e, admin, http.MethodGet, "/admin/chain/0x1"))
+
+	req := httptest.NewRequest(http.MethodPost, "/chain/0x1/stop",
+		strings.NewReader(`{"reason":"test"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	records, err := au.Query(&AuditQuery{Verify: true})
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+	assert.Equal(t, admin.Address().String(), records[0].User)
+	assert.Equal(t, http.StatusOK, records[0].Status)
+	assert.Equal(t, http.StatusBadRequest, records[1].Status)
+	assert.NotEmpty(t, records[1].Error)
+	assert.Equal(t, AuditUserAnonymous, records[2].User)
+	assert.Equal(t, http.StatusUnauthorized, records[2].Status)
+	assert.Equal(t, AuditUserLocal, records[3].User)
+	assert.Equal(t, AuditSourceCLI, records[3].Source)
+	assert.JSONEq(t, `{"reason":"test"}`, string(records[3].Params))
+
+	records, err = au.Query(&AuditQuery{User: admin.Address().String(), Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.EqualValues(t, 2, records[0].Seq)
+	assert.NoError(t, au.Close())
+
+	// chain continues after reopen
+	au, err = NewAuditLog(file)
+	assert.NoError(t, err)
+	assert.NoError(t, au.Append(&AuditRecord{Source: AuditSourceCLI, User: AuditUserLocal}))
+	records, err = au.Query(&AuditQuery{Verify: true})
+	assert.NoError(t, err)
+	assert.Len(t, records, 5)
+	assert.NoError(t, au.Close())
+
+	// modification is detected
+	bs, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	bs = []byte(strings.Replace(string(bs), `"status":400`, `"status":200`, 1))
+	assert.NoError(t, ioutil.WriteFile(file, bs, 0600))
+	au, err = NewAuditLog(file)
+	assert.NoError(t, err)
+	_, err = au.Query(&AuditQuery{Verify: true})
+	assert.Error(t, err)
+	_, err = au.Query(&AuditQuery{})
+	assert.NoError(t, err)
+	assert.NoError(t, au.Close())
+}
+
+func TestAuditLog_Params(t *testing.T) {
+	au, err := NewAuditLog(path.Join(t.TempDir(), "audit.log"))
+	assert.NoError(t, err)
+	defer au.Close()
+
+	a := NewAuth("", "/admin")
+	e := echo.New()
+	var received int
+	handler := func(ctx echo.Context) error {
+		bs, err := ioutil.ReadAll(ctx.Request().Body)
+		if err != nil {
+			return err
+		}
+		received = len(bs)
+		return ctx.String(http.StatusOK, "OK")
+	}
+	e.Group("/admin/chain", au.MiddlewareFunc(AuditSourceAdmin), a.MiddlewareFunc()).
+		POST(UrlChainRes+"/configure", handler)
+	e.Group("/chain", au.MiddlewareFunc(AuditSourceCLI)).
+		POST(UrlChainRes+"/configure", handler)
+	assert.NoError(t, a.AddUser(wallet.New().Address().String(), nil))
+
+	doPost := func(url, body string) int {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// body of the rejected request is not recorded
+	assert.Equal(t, http.StatusUnauthorized,
+		doPost("/admin/chain/0x1/configure", `{"key":"k","value":"v"}`))
+
+	// large body is passed to the handler, but it's not recorded
+	large := `{"value":"` + strings.Repeat("a", auditMaxParamSize) + `"}`
+	assert.Equal(t, http.StatusOK, doPost("/chain/0x1/configure", large))
+	assert.Equal(t, len(large), received)
+
+	records, err := au.Query(&AuditQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, http.StatusUnauthorized, records[0].Status)
+	assert.Empty(t, records[0].Params)
+	assert.Empty(t, records[0].Error)
+	assert.Equal(t, http.StatusOK, records[1].Status)
+	assert.Empty(t, records[1].Params)
+	assert.True(t, records[1].Truncated)
+}
+
+func TestAuditLog_Rotate(t *testing.T) {
+	file := path.Join(t.TempDir(), "audit.log")
+	au, err := NewAuditLog(file)
+	assert.NoError(t, err)
+	au.maxSize = 1024
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, au.Append(&AuditRecord{Source: AuditSourceCLI, User: AuditUserLocal}))
+	}
+	fi, err := os.Stat(file)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, fi.Size(), int64(1024))
+	fi, err = os.Stat(file + ".1")
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, fi.Size(), int64(1024))
+
+	records, err := au.Query(&AuditQuery{Verify: true, Limit: 100})
+	assert.NoError(t, err)
+	assert.Less(t, len(records), 20)
+	assert.EqualValues(t, 20, records[len(records)-1].Seq)
+	assert.NoError(t, au.Close())
+
+	// chain continues after reopen
+	au, err = NewAuditLog(file)
+	assert.NoError(t, err)
+	assert.NoError(t, au.Append(&AuditRecord{Source: AuditSourceCLI, User: AuditUserLocal}))
+	records, err = au.Query(&AuditQuery{Verify: true, Limit: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 21, records[0].Seq)
+	assert.NoError(t, au.Close())
+}
+
+func TestAuditLog_RedactSecrets(t *testing.T) {
+	au, err := NewAuditLog(path.Join(t.TempDir(), "audit.log"))
+	assert.NoError(t, err)
+	defer au.Close()
+
+	e := echo.New()
+	e.Group("/system", au.MiddlewareFunc(AuditSourceCLI)).
+		POST("/configure", func(ctx echo.Context) error {
+			return ctx.String(http.StatusOK, "OK")
+		})
+	for _, body := range []string{
+		`{"key":"rpcAPIKeys","value":"secret1,secret2"}`,
+		`{"key":"rpcBatchLimit","value":"10"}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/system/configure", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	records, err := au.Query(&AuditQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.JSONEq(t, `{"key":"rpcAPIKeys","value":"`+RedactedValue+`"}`, string(records[0].Params))
+	assert.JSONEq(t, `{"key":"rpcBatchLimit","value":"10"}`, string(records[1].Params))
+
+	rcfg := &RuntimeConfig{RPCAPIKeys: "secret1", RPCBatchLimit: 10}
+	assert.Equal(t, RedactedValue, rcfg.redacted().RPCAPIKeys)
+	assert.Equal(t, 10, rcfg.redacted().RPCBatchLimit)
+	assert.Equal(t, "secret1", rcfg.RPCAPIKeys)
+}
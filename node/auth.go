@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,15 +23,106 @@ import (
 
 const (
 	AuthScheme = "goloop"
+
+	// ContextKeyUser is the key of the user ID of the authenticated request
+	// in echo.Context.
+	ContextKeyUser = "user"
+)
+
+// Role is the role of an user for the admin API. A role is allowed to do
+// anything that lower roles can do.
+type Role int
+
+const (
+	RoleNone Role = iota
+	RoleViewer
+	RoleOperator
+	RoleAdmin
 )
 
+var roleNames = map[Role]string{
+	RoleNone:     "none",
+	RoleViewer:   "viewer",
+	RoleOperator: "operator",
+	RoleAdmin:    "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("invalid(%d)", int(r))
+}
+
+func ParseRole(s string) (Role, error) {
+	for r, name := range roleNames {
+		if name == s {
+			return r, nil
+		}
+	}
+	return RoleNone, errors.IllegalArgumentError.Errorf("InvalidRole(role=%s)", s)
+}
+
+func (r Role) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+func (r *Role) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	role, err := ParseRole(s)
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
+
+// UserRoles is the roles of an user. Role is applied to all chains and
+// system APIs, and Chains has the roles for specific chains overriding it.
+// Keys of Chains are CIDs formatted in "0x%x".
+type UserRoles struct {
+	Role   Role            `json:"role"`
+	Chains map[string]Role `json:"chains,omitempty"`
+}
+
+func (ur *UserRoles) roleFor(chain string) Role {
+	if chain != "" {
+		if role, ok := ur.Chains[chain]; ok {
+			return role
+		}
+	}
+	return ur.Role
+}
+
+type UserView struct {
+	ID string `json:"id"`
+	UserRoles
+}
+
+// ChainKeyOf returns the key of chain roles for CID in string.
+func ChainKeyOf(cid string) (string, error) {
+	v, err := strconv.ParseUint(cid, 0, 32)
+	if err != nil {
+		return "", errors.IllegalArgumentError.Errorf("InvalidCID(cid=%s)", cid)
+	}
+	return fmt.Sprintf("%#x", v), nil
+}
+
 type Auth struct {
 	skips map[string]map[string]bool
+	perms map[string]map[string]Role
 	users map[string]int64
+	roles map[string]*UserRoles
 	addrs map[string]string
 	filePath string
 	prefix string
 	SkipIfEmptyUsers bool
+	// ChainKey returns the key of chain roles for the chain selector in
+	// the request. It returns empty string if there is no matching chain.
+	ChainKey func(selector string) string
 	mtx   sync.Mutex
 }
 
@@ -51,13 +143,17 @@ func (a *Auth) MiddlewareFunc() echo.MiddlewareFunc {
 			if err != nil {
 				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
 			}
-			valid, err := a.validator(key, ctx)
+			id, err := a.validator(key, ctx)
 			if err != nil {
 				return err
-			} else if valid {
-				return next(ctx)
+			} else if id == "" {
+				return echo.ErrUnauthorized
 			}
-			return echo.ErrUnauthorized
+			ctx.Set(ContextKeyUser, id)
+			if !a.permitted(id, ctx) {
+				return echo.ErrForbidden
+			}
+			return next(ctx)
 		}
 	}
 }
@@ -71,6 +167,51 @@ func (a *Auth) SetSkip(r *echo.Route, skip bool) {
 	m[r.Path] = skip
 }
 
+// SetRole sets the role required for the route. Without it, the route
+// requires RoleViewer for GET method and RoleAdmin for others.
+func (a *Auth) SetRole(r *echo.Route, role Role) {
+	m, ok := a.perms[r.Method]
+	if !ok {
+		m = make(map[string]Role)
+		a.perms[r.Method] = m
+	}
+	m[r.Path] = role
+}
+
+func (a *Auth) requiredRole(ctx echo.Context) Role {
+	method := ctx.Request().Method
+	if m, ok := a.perms[method]; ok {
+		if role, has := m[ctx.Path()]; has {
+			return role
+		}
+	}
+	if method == http.MethodGet {
+		return RoleViewer
+	}
+	return RoleAdmin
+}
+
+func (a *Auth) permitted(id string, ctx echo.Context) bool {
+	var chain string
+	if sel := ctx.Param(ParamCID); sel != "" && a.ChainKey != nil {
+		chain = a.ChainKey(sel)
+	}
+	required := a.requiredRole(ctx)
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	ur, ok := a.roles[id]
+	if !ok {
+		return false
+	}
+	role := ur.roleFor(chain)
+	if role < required {
+		log.Tracef("not permitted user=%s role=%s required=%s", id, role, required)
+		return false
+	}
+	return true
+}
+
 func (a *Auth) skipper(ctx echo.Context) bool {
 	if a.SkipIfEmptyUsers && a.IsEmptyUsers() {
 		return true
@@ -114,7 +255,7 @@ func parse(s string) map[string]string {
 	return m
 }
 
-func (a *Auth) validator(s string, ctx echo.Context) (b bool, err error) {
+func (a *Auth) validator(s string, ctx echo.Context) (uid string, err error) {
 	log.Traceln("validator:", s)
 	m := parse(s)
 	var timestamp int64
@@ -146,16 +287,16 @@ func (a *Auth) validator(s string, ctx echo.Context) (b bool, err error) {
 		if ts := a.users[id]; ts < timestamp {
 			a.users[id] = timestamp
 			log.Traceln("valid signature", ts, timestamp)
-			return true, nil
+			return id, nil
 		}
 		log.Traceln("old signature", a.users[id], timestamp)
-		return false, nil
+		return "", nil
 	}
 	log.Traceln("not found user", addr)
-	return false, nil
+	return "", nil
 }
 
-func (a *Auth) AddUser(id string) error {
+func (a *Auth) AddUser(id string, roles *UserRoles) error {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -172,7 +313,11 @@ func (a *Auth) AddUser(id string) error {
 		return errors.Wrapf(ErrAlreadyExists, "User(addr=%s) already exists", addr.String())
 	}
 
+	if roles == nil {
+		roles = &UserRoles{Role: RoleAdmin}
+	}
 	a.users[id] = time.Now().Unix()
+	a.roles[id] = roles
 	a.addrs[addr.String()] = id
 	if err := a._export(); err != nil {
 		panic(err)
@@ -189,6 +334,7 @@ func (a *Auth) RemoveUser(id string) error {
 	}
 
 	delete(a.users, id)
+	delete(a.roles, id)
 	var addr string
 	for k, v := range a.addrs {
 		if v == id {
@@ -203,11 +349,38 @@ func (a *Auth) RemoveUser(id string) error {
 	return nil
 }
 
-func (a *Auth) _users() []string {
-	users := make([]string, 0)
+// SetUserRole sets the role of the user. If chain is empty, it sets the role
+// for all chains and system APIs. Otherwise, it sets the role for the chain,
+// and RoleNone removes the role for the chain.
+func (a *Auth) SetUserRole(id string, chain string, role Role) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ur, ok := a.roles[id]
+	if !ok {
+		return errors.Wrapf(ErrNotExists, "User(id=%s) not exists", id)
+	}
+	if chain == "" {
+		ur.Role = role
+	} else if role == RoleNone {
+		delete(ur.Chains, chain)
+	} else {
+		if ur.Chains == nil {
+			ur.Chains = make(map[string]Role)
+		}
+		ur.Chains[chain] = role
+	}
+	return a._export()
+}
+
+func (a *Auth) _users() []*UserView {
+	users := make([]*UserView, 0)
 	for user := range a.users {
-		users = append(users, user)
+		users = append(users, &UserView{ID: user, UserRoles: *a.roles[user]})
 	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
 	return users
 }
 
@@ -217,7 +390,7 @@ func (a *Auth) IsEmptyUsers() bool {
 	return len(a.users) == 0
 }
 
-func (a *Auth) GetUsers() []string {
+func (a *Auth) GetUsers() []*UserView {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -241,7 +414,9 @@ func (a *Auth) _export() error {
 func NewAuth(filePath, prefix string) *Auth {
 	a := &Auth{
 		skips: make(map[string]map[string]bool),
+		perms: make(map[string]map[string]Role),
 		users: make(map[string]int64),
+		roles: make(map[string]*UserRoles),
 		addrs: make(map[string]string),
 		filePath: filePath,
 		prefix: prefix,
@@ -257,12 +432,19 @@ func NewAuth(filePath, prefix string) *Auth {
 		if b, err := ioutil.ReadFile(filePath); err != nil {
 			panic(err)
 		} else {
-			var users []string
+			var users []json.RawMessage
 			if err = json.Unmarshal(b, &users); err != nil {
 				panic(err)
 			}
 			for _, user := range users {
-				if err = a.AddUser(user); err != nil {
+				var uv UserView
+				// users in the previous format have all permissions
+				if err = json.Unmarshal(user, &uv.ID); err == nil {
+					uv.Role = RoleAdmin
+				} else if err = json.Unmarshal(user, &uv); err != nil {
+					panic(err)
+				}
+				if err = a.AddUser(uv.ID, &uv.UserRoles); err != nil {
 					panic(err)
 				}
 			}
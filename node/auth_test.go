@@ -0,0 +1,112 @@
+package node
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func newAuthTestServer(a *Auth) *echo.Echo {
+	e := echo.New()
+	g := e.Group("/admin/chain", a.MiddlewareFunc())
+	ok := func(ctx echo.Context) error {
+		return ctx.String(http.StatusOK, "OK")
+	}
+	a.SetSkip(g.GET(UrlChainRes+"/genesis", ok), false)
+	a.SetRole(g.POST(UrlChainRes+"/start", ok), RoleOperator)
+	g.POST(UrlChainRes+"/reset", ok)
+	return e
+}
+
+func doAuthRequest(e *echo.Echo, w module.Wallet, method, url string) int {
+	req := httptest.NewRequest(method, url, nil)
+	ts := fmt.Sprint(time.Now().UnixNano())
+	serialized := fmt.Sprintf("Method=%s,Url=%s,Timestamp=%s",
+		method, url[len("/admin"):], ts)
+	sig, _ := w.Sign(crypto.SHA3Sum256([]byte(serialized)))
+	req.Header.Set(echo.HeaderAuthorization,
+		fmt.Sprintf("%s Timestamp=%s,Signature=%s", AuthScheme, ts, hex.EncodeToString(sig)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAuth_Roles(t *testing.T) {
+	a := NewAuth("", "/admin")
+	a.ChainKey = func(selector string) string {
+		if key, err := ChainKeyOf(selector); err == nil {
+			return key
+		}
+		return ""
+	}
+	e := newAuthTestServer(a)
+
+	viewer, operator, admin, other := wallet.New(), wallet.New(), wallet.New(), wallet.New()
+	assert.NoError(t, a.AddUser(viewer.Address().String(), &UserRoles{Role: RoleViewer}))
+	assert.NoError(t, a.AddUser(operator.Address().String(), &UserRoles{
+		Role:   RoleViewer,
+		Chains: map[string]Role{"0x1": RoleOperator},
+	}))
+	assert.NoError(t, a.AddUser(admin.Address().String(), nil))
+
+	cases := []struct {
+		w      module.Wallet
+		method string
+		url    string
+		code   int
+	}{
+		{viewer, http.MethodGet, "/admin/chain/0x1/genesis", http.StatusOK},
+		{viewer, http.MethodPost, "/admin/chain/0x1/start", http.StatusForbidden},
+		{operator, http.MethodPost, "/admin/chain/0x1/start", http.StatusOK},
+		{operator, http.MethodPost, "/admin/chain/0x2/start", http.StatusForbidden},
+		{operator, http.MethodPost, "/admin/chain/0x1/reset", http.StatusForbidden},
+		{admin, http.MethodPost, "/admin/chain/0x1/reset", http.StatusOK},
+		{other, http.MethodGet, "/admin/chain/0x1/genesis", http.StatusUnauthorized},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.code, doAuthRequest(e, c.w, c.method, c.url), "case %d", i)
+	}
+
+	assert.NoError(t, a.SetUserRole(viewer.Address().String(), "0x2", RoleAdmin))
+	assert.Equal(t, http.StatusOK,
+		doAuthRequest(e, viewer, http.MethodPost, "/admin/chain/0x2/reset"))
+	assert.NoError(t, a.SetUserRole(viewer.Address().String(), "0x2", RoleNone))
+	assert.Equal(t, http.StatusForbidden,
+		doAuthRequest(e, viewer, http.MethodPost, "/admin/chain/0x2/reset"))
+}
+
+func TestAuth_LoadLegacyUsers(t *testing.T) {
+	file := path.Join(t.TempDir(), "auth.json")
+	w1, w2 := wallet.New(), wallet.New()
+	legacy := fmt.Sprintf("[%q]", w1.Address().String())
+	assert.NoError(t, ioutil.WriteFile(file, []byte(legacy), 0644))
+
+	a := NewAuth(file, "/admin")
+	assert.NoError(t, a.AddUser(w2.Address().String(), &UserRoles{Role: RoleOperator}))
+
+	a2 := NewAuth(file, "/admin")
+	users := a2.GetUsers()
+	assert.Len(t, users, 2)
+	for _, u := range users {
+		switch u.ID {
+		case w1.Address().String():
+			assert.Equal(t, RoleAdmin, u.Role)
+		case w2.Address().String():
+			assert.Equal(t, RoleOperator, u.Role)
+		default:
+			t.Errorf("unknown user %s", u.ID)
+		}
+	}
+}
@@ -113,10 +113,25 @@ type RuntimeConfig struct {
 	RPCRosetta        bool   `json:"rpcRosetta"`
 	RPCBatchLimit     int    `json:"rpcBatchLimit"`
 	WSMaxSession      int    `json:"wsMaxSession"`
+	RPCRateLimit      string `json:"rpcRateLimit"`
+	RPCAPIKeys        string `json:"rpcAPIKeys"`
 
 	FilePath string `json:"-"` // absolute path
 }
 
+// RedactedValue replaces secrets of configurations shown to users.
+const RedactedValue = "<redacted>"
+
+// redacted returns the copy of the configuration with secrets replaced
+// by RedactedValue.
+func (c *RuntimeConfig) redacted() *RuntimeConfig {
+	rc := *c
+	if rc.RPCAPIKeys != "" {
+		rc.RPCAPIKeys = RedactedValue
+	}
+	return &rc
+}
+
 func (c *RuntimeConfig) load() error {
 	log.Println("load ", c.FilePath)
 	if _, err := os.Stat(c.FilePath); err != nil {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
@@ -320,7 +321,12 @@ func (n *Node) Start() {
 
 	go func() {
 		for channel, chain := range n.chains {
-			if chain.cfg.AutoStart {
+			if tb := chain.cfg.TrustedBlock; tb != nil {
+				if err := chain.Bootstrap("", tb.Height, tb.BlockHash); err != nil {
+					n.logger.Warnf("fail to resume bootstrap channel=%s err=%+v",
+						channel, err)
+				}
+			} else if chain.cfg.AutoStart {
 				if err := chain.Start(); err != nil {
 					n.logger.Warnf("fail to start chain channel=%s err=%+v",
 						channel, err)
@@ -376,6 +382,13 @@ func (n *Node) JoinChain(
 		return nil, errors.Wrap(err, "fail to get NID for genesis")
 	}
 
+	if tb := p.TrustedBlock; tb != nil {
+		if tb.Height <= 1 || len(tb.BlockHash) != crypto.HashLen {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidTrustedBlock(height=%d,hash=%#x)", tb.Height, tb.BlockHash)
+		}
+	}
+
 	channel := chain.GetChannel(p.Channel, nid)
 
 	if err := n._canAdd(cid, nid, channel, false); err != nil {
@@ -391,29 +404,40 @@ func (n *Node) JoinChain(
 	cfgFile, _ := filepath.Abs(path.Join(chainDir, ChainConfigFileName))
 
 	cfg := &chain.Config{
-		NID:              nid,
-		DBType:           p.DBType,
-		Platform:         p.Platform,
-		Channel:          channel,
-		SecureSuites:     p.SecureSuites,
-		SecureAeads:      p.SecureAeads,
-		SeedAddr:         p.SeedAddr,
-		Role:             p.Role,
-		GenesisStorage:   genesisStorage,
-		ConcurrencyLevel: p.ConcurrencyLevel,
-		NormalTxPoolSize: p.NormalTxPoolSize,
-		PatchTxPoolSize:  p.PatchTxPoolSize,
-		MaxBlockTxBytes:  p.MaxBlockTxBytes,
-		NodeCache:        p.NodeCache,
-		DefWaitTimeout:   p.DefWaitTimeout,
-		MaxWaitTimeout:   p.MaxWaitTimeout,
-		TxTimeout:        p.TxTimeout,
-		AutoStart:        p.AutoStart,
-		FilePath:         cfgFile,
-		NIDForP2P:        n.cfg.NIDForP2P,
-		ChildrenLimit:    p.ChildrenLimit,
-		NephewsLimit:     p.NephewsLimit,
-		ValidateTxOnSend: p.ValidateTxOnSend,
+		NID:               nid,
+		DBType:            p.DBType,
+		Platform:          p.Platform,
+		Channel:           channel,
+		SecureSuites:      p.SecureSuites,
+		SecureAeads:       p.SecureAeads,
+		SeedAddr:          p.SeedAddr,
+		Role:              p.Role,
+		GenesisStorage:    genesisStorage,
+		ConcurrencyLevel:  p.ConcurrencyLevel,
+		NormalTxPoolSize:  p.NormalTxPoolSize,
+		PatchTxPoolSize:   p.PatchTxPoolSize,
+		MaxBlockTxBytes:   p.MaxBlockTxBytes,
+		NodeCache:         p.NodeCache,
+		DefWaitTimeout:    p.DefWaitTimeout,
+		MaxWaitTimeout:    p.MaxWaitTimeout,
+		TxTimeout:         p.TxTimeout,
+		AutoStart:         p.AutoStart,
+		FilePath:          cfgFile,
+		NIDForP2P:         n.cfg.NIDForP2P,
+		ChildrenLimit:     p.ChildrenLimit,
+		NephewsLimit:      p.NephewsLimit,
+		ValidateTxOnSend:  p.ValidateTxOnSend,
+		EventIndex:        p.EventIndex,
+		PruneKeepBlocks:   p.PruneKeepBlocks,
+		PruneInterval:     p.PruneInterval,
+		TxPoolSenderLimit: p.TxPoolSenderLimit,
+		FastSyncWindow:    p.FastSyncWindow,
+	}
+	if tb := p.TrustedBlock; tb != nil {
+		cfg.TrustedBlock = &chain.TrustedBlock{
+			Height:    tb.Height,
+			BlockHash: tb.BlockHash,
+		}
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -432,6 +456,14 @@ func (n *Node) JoinChain(
 		_ = os.RemoveAll(chainDir)
 		return nil, err
 	}
+
+	if tb := p.TrustedBlock; tb != nil {
+		if err := c.Bootstrap(gsFile, tb.Height, tb.BlockHash); err != nil {
+			_ = n._remove(c)
+			_ = os.RemoveAll(chainDir)
+			return nil, errors.Wrap(err, "fail to bootstrap")
+		}
+	}
 	return c, nil
 }
 
@@ -517,6 +549,28 @@ func (n *Node) ImportChain(cid int, s string, height int64) error {
 	return c.Import(s, height)
 }
 
+func (n *Node) ExportChain(cid int, file string, from, to int64, flags int) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.Export(file, from, to, flags)
+}
+
+func (n *Node) ImportChainArchive(cid int, file string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	return c.ImportArchive(file)
+}
+
 func (n *Node) PruneChain(cid int, dbt string, height int64) error {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -530,7 +584,7 @@ func (n *Node) PruneChain(cid int, dbt string, height int64) error {
 	return c.Prune(gs, dbt, height)
 }
 
-func (n *Node) BackupChain(cid int, manual bool) (string, error) {
+func (n *Node) BackupChain(cid int, manual, incremental bool) (string, error) {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
 
@@ -548,10 +602,62 @@ func (n *Node) BackupChain(cid int, manual bool) (string, error) {
 			"Fail to make backup directory=%s", backupDir)
 	}
 	now := time.Now()
+	extra := []string{ChainGenesisZipFileName, ChainConfigFileName}
+	if incremental {
+		name := fmt.Sprintf("%#x_%#x_%s_%s_inc.zip", c.CID(), c.NID(), c.Channel(),
+			now.Format("20060102-150405"))
+		file := path.Join(backupDir, name)
+		return name, c.BackupIncremental(file, extra)
+	}
 	name := fmt.Sprintf("%#x_%#x_%s_%s.zip", c.CID(), c.NID(), c.Channel(),
 		now.Format("20060102-150405"))
 	file := path.Join(backupDir, name)
-	return name, c.Backup(file, []string{ChainGenesisZipFileName, ChainConfigFileName})
+	return name, c.Backup(file, extra)
+}
+
+func (n *Node) _networkManager(cid int) (module.NetworkManager, error) {
+	c, err := n._get(cid)
+	if err != nil {
+		return nil, err
+	}
+	nm := c.NetworkManager()
+	if nm == nil {
+		return nil, errors.InvalidStateError.Errorf("NetworkNotReady(cid=%#x)", cid)
+	}
+	return nm, nil
+}
+
+func (n *Node) BanPeer(cid int, id module.PeerID, d time.Duration, reason string) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	nm, err := n._networkManager(cid)
+	if err != nil {
+		return err
+	}
+	return nm.BanPeer(id, d, reason)
+}
+
+func (n *Node) UnbanPeer(cid int, id module.PeerID) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	nm, err := n._networkManager(cid)
+	if err != nil {
+		return err
+	}
+	return nm.UnbanPeer(id)
+}
+
+func (n *Node) GetBannedPeers(cid int) ([]*module.BannedPeer, error) {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	nm, err := n._networkManager(cid)
+	if err != nil {
+		return nil, err
+	}
+	return nm.GetBannedPeers(), nil
 }
 
 type BackupInfo struct {
@@ -767,6 +873,38 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.ValidateTxOnSend = bc
 			}
+		case "eventIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.EventIndex = bc
+			}
+		case "pruneKeepBlocks":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=int,val=%s)", value)
+			} else {
+				c.cfg.PruneKeepBlocks = intVal
+			}
+		case "pruneInterval":
+			if intVal, err := strconv.ParseInt(value, 0, 64); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=int,val=%s)", value)
+			} else {
+				c.cfg.PruneInterval = intVal
+			}
+		case "txPoolSenderLimit":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=int,val=%s)", value)
+			} else {
+				c.cfg.TxPoolSenderLimit = intVal
+			}
+		case "fastSyncWindow":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=int,val=%s)", value)
+			} else if intVal < 0 {
+				return errors.IllegalArgumentError.Errorf("InvalidValue(val=%d)", intVal)
+			} else {
+				c.cfg.FastSyncWindow = intVal
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
@@ -893,6 +1031,14 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.WSMaxSession = intVal
 		}
 		n.srv.SetWSMaxSession(n.rcfg.WSMaxSession)
+	case "rpcRateLimit":
+		if err := n.srv.SetRateLimit(value); err != nil {
+			return errors.Wrapf(err, "invalid value")
+		}
+		n.rcfg.RPCRateLimit = value
+	case "rpcAPIKeys":
+		n.rcfg.RPCAPIKeys = value
+		n.srv.SetAPIKeys(n.rcfg.RPCAPIKeys)
 	default:
 		return errors.Errorf("not found key")
 	}
@@ -932,6 +1078,8 @@ func NewNode(
 		JSONRPCDefaultChannel: rcfg.RPCDefaultChannel,
 		JSONRPCBatchLimit:     rcfg.RPCBatchLimit,
 		WSMaxSession:          rcfg.WSMaxSession,
+		RateLimit:             rcfg.RPCRateLimit,
+		APIKeys:               rcfg.RPCAPIKeys,
 	}
 	srv := server.NewManager(config, w, l)
 
@@ -17,9 +17,12 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/chain/archive"
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -44,8 +47,9 @@ const (
 )
 
 type Rest struct {
-	n *Node
-	a *Auth
+	n  *Node
+	a  *Auth
+	au *AuditLog
 }
 
 type SystemView struct {
@@ -84,25 +88,32 @@ type ChainInspectView struct {
 }
 
 type ChainConfig struct {
-	DBType           string `json:"dbType"`
-	Platform         string `json:"platform"`
-	SeedAddr         string `json:"seedAddress"`
-	Role             uint   `json:"role"`
-	ConcurrencyLevel int    `json:"concurrencyLevel,omitempty"`
-	NormalTxPoolSize int    `json:"normalTxPool,omitempty"`
-	PatchTxPoolSize  int    `json:"patchTxPool,omitempty"`
-	MaxBlockTxBytes  int    `json:"maxBlockTxBytes,omitempty"`
-	NodeCache        string `json:"nodeCache,omitempty"`
-	Channel          string `json:"channel"`
-	SecureSuites     string `json:"secureSuites"`
-	SecureAeads      string `json:"secureAeads"`
-	DefWaitTimeout   int64  `json:"defaultWaitTimeout"`
-	MaxWaitTimeout   int64  `json:"maxWaitTimeout"`
-	TxTimeout        int64  `json:"txTimeout"`
-	AutoStart        bool   `json:"autoStart"`
-	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
-	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
-	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	DBType            string `json:"dbType"`
+	Platform          string `json:"platform"`
+	SeedAddr          string `json:"seedAddress"`
+	Role              uint   `json:"role"`
+	ConcurrencyLevel  int    `json:"concurrencyLevel,omitempty"`
+	NormalTxPoolSize  int    `json:"normalTxPool,omitempty"`
+	PatchTxPoolSize   int    `json:"patchTxPool,omitempty"`
+	MaxBlockTxBytes   int    `json:"maxBlockTxBytes,omitempty"`
+	NodeCache         string `json:"nodeCache,omitempty"`
+	Channel           string `json:"channel"`
+	SecureSuites      string `json:"secureSuites"`
+	SecureAeads       string `json:"secureAeads"`
+	DefWaitTimeout    int64  `json:"defaultWaitTimeout"`
+	MaxWaitTimeout    int64  `json:"maxWaitTimeout"`
+	TxTimeout         int64  `json:"txTimeout"`
+	AutoStart         bool   `json:"autoStart"`
+	ChildrenLimit     *int   `json:"childrenLimit,omitempty"`
+	NephewsLimit      *int   `json:"nephewsLimit,omitempty"`
+	ValidateTxOnSend  bool   `json:"validateTxOnSend,omitempty"`
+	EventIndex        bool   `json:"eventIndex,omitempty"`
+	PruneKeepBlocks   int64  `json:"pruneKeepBlocks,omitempty"`
+	PruneInterval     int64  `json:"pruneInterval,omitempty"`
+	TxPoolSenderLimit int    `json:"txPoolSenderLimit,omitempty"`
+	FastSyncWindow    int    `json:"fastSyncWindow,omitempty"`
+
+	TrustedBlock *ChainResetParam `json:"trustedBlock,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -115,13 +126,43 @@ type ChainImportParam struct {
 	Height int64  `json:"height"`
 }
 
+type ChainExportParam struct {
+	File     string `json:"file"`
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+	Receipts bool   `json:"receipts,omitempty"`
+	State    bool   `json:"state,omitempty"`
+}
+
+type ChainImportArchiveParam struct {
+	File string `json:"file"`
+}
+
 type ChainPruneParam struct {
 	DBType string `json:"dbType,omitempty"`
 	Height int64  `json:"height"`
 }
 
 type ChainBackupParam struct {
-	Manual bool `json:"manual,omitempty"`
+	Manual      bool `json:"manual,omitempty"`
+	Incremental bool `json:"incremental,omitempty"`
+}
+
+type UserRoleParam struct {
+	Role  Role   `json:"role"`
+	Chain string `json:"chain,omitempty"`
+}
+
+type BanPeerParam struct {
+	ID       string `json:"id"`
+	Duration string `json:"duration,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type BannedPeerView struct {
+	ID     string    `json:"id"`
+	Reason string    `json:"reason"`
+	Until  time.Time `json:"until"`
 }
 
 type ConfigureParam struct {
@@ -166,25 +207,30 @@ func NewChainInspectView(c *Chain) *ChainInspectView {
 
 func NewChainConfig(cfg *chain.Config) *ChainConfig {
 	v := &ChainConfig{
-		DBType:           cfg.DBType,
-		Platform:         cfg.Platform,
-		SeedAddr:         cfg.SeedAddr,
-		Role:             cfg.Role,
-		ConcurrencyLevel: cfg.ConcurrencyLevel,
-		NormalTxPoolSize: cfg.NormalTxPoolSize,
-		PatchTxPoolSize:  cfg.PatchTxPoolSize,
-		MaxBlockTxBytes:  cfg.MaxBlockTxBytes,
-		NodeCache:        cfg.NodeCache,
-		Channel:          cfg.Channel,
-		SecureSuites:     cfg.SecureSuites,
-		SecureAeads:      cfg.SecureAeads,
-		DefWaitTimeout:   cfg.DefWaitTimeout,
-		MaxWaitTimeout:   cfg.MaxWaitTimeout,
-		TxTimeout:        cfg.TxTimeout,
-		AutoStart:        cfg.AutoStart,
-		ChildrenLimit:    cfg.ChildrenLimit,
-		NephewsLimit:     cfg.NephewsLimit,
-		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		DBType:            cfg.DBType,
+		Platform:          cfg.Platform,
+		SeedAddr:          cfg.SeedAddr,
+		Role:              cfg.Role,
+		ConcurrencyLevel:  cfg.ConcurrencyLevel,
+		NormalTxPoolSize:  cfg.NormalTxPoolSize,
+		PatchTxPoolSize:   cfg.PatchTxPoolSize,
+		MaxBlockTxBytes:   cfg.MaxBlockTxBytes,
+		NodeCache:         cfg.NodeCache,
+		Channel:           cfg.Channel,
+		SecureSuites:      cfg.SecureSuites,
+		SecureAeads:       cfg.SecureAeads,
+		DefWaitTimeout:    cfg.DefWaitTimeout,
+		MaxWaitTimeout:    cfg.MaxWaitTimeout,
+		TxTimeout:         cfg.TxTimeout,
+		AutoStart:         cfg.AutoStart,
+		ChildrenLimit:     cfg.ChildrenLimit,
+		NephewsLimit:      cfg.NephewsLimit,
+		ValidateTxOnSend:  cfg.ValidateTxOnSend,
+		EventIndex:        cfg.EventIndex,
+		PruneKeepBlocks:   cfg.PruneKeepBlocks,
+		PruneInterval:     cfg.PruneInterval,
+		TxPoolSenderLimit: cfg.TxPoolSenderLimit,
+		FastSyncWindow:    cfg.FastSyncWindow,
 	}
 	return v
 }
@@ -197,25 +243,46 @@ func RegisterInspectFunc(name string, f InspectFunc) error {
 	return nil
 }
 
+func inspectPruner(c module.Chain, informal bool) map[string]interface{} {
+	if nc, ok := c.(*Chain); ok {
+		return chain.InspectPruner(nc.Chain, informal)
+	}
+	return nil
+}
+
 func RegisterRest(n *Node) {
 	r := Rest{
 		n: n,
 		a: NewAuth(path.Join(n.cfg.ResolveAbsolute(n.cfg.BaseDir), "auth.json"), server.UrlAdmin),
 	}
 	r.a.SkipIfEmptyUsers = n.cfg.AuthSkipIfEmptyUsers
-	ag := n.srv.AdminEchoGroup(r.a.MiddlewareFunc())
+	au, err := NewAuditLog(path.Join(n.cfg.ResolveAbsolute(n.cfg.BaseDir), "audit.log"))
+	if err != nil {
+		log.Panicf("fail to open audit log err=%+v", err)
+	}
+	r.au = au
+	r.a.ChainKey = func(selector string) string {
+		if c := n.GetChainBySelector(selector); c != nil {
+			return fmt.Sprintf("%#x", c.CID())
+		}
+		return ""
+	}
+	ag := n.srv.AdminEchoGroup(r.au.MiddlewareFunc(AuditSourceAdmin), r.a.MiddlewareFunc())
 	r.RegisterChainHandlers(ag.Group(UrlChain))
 	r.RegisterSystemHandlers(ag.Group(UrlSystem))
 
-	r.RegisterChainHandlers(n.cliSrv.e.Group(UrlChain))
-	r.RegisterSystemHandlers(n.cliSrv.e.Group(UrlSystem))
-	r.RegisterUserHandlers(n.cliSrv.e.Group(UrlUser))
+	cliAudit := r.au.MiddlewareFunc(AuditSourceCLI)
+	r.RegisterChainHandlers(n.cliSrv.e.Group(UrlChain, cliAudit))
+	r.RegisterSystemHandlers(n.cliSrv.e.Group(UrlSystem, cliAudit))
+	r.RegisterUserHandlers(n.cliSrv.e.Group(UrlUser, cliAudit))
 	r.RegisterStatsHandlers(n.cliSrv.e.Group(UrlStats))
 	r.RegisterDBHandlers(n.cliSrv.e.Group(UrlDB))
 
 	_ = RegisterInspectFunc("metrics", metric.Inspect)
 	_ = RegisterInspectFunc("network", network.Inspect)
 	_ = RegisterInspectFunc("service", service.Inspect)
+	_ = RegisterInspectFunc("pruner", inspectPruner)
+	_ = RegisterInspectFunc("consensus", consensus.Inspect)
 
 	// json rpc
 	n.srv.RegisterAPIHandler(n.cliSrv.e.Group("/api"))
@@ -230,22 +297,33 @@ func (r *Rest) RegisterChainHandlers(g *echo.Group) {
 
 	g.GET(UrlChainRes, r.GetChain, r.ChainInjector)
 	g.DELETE(UrlChainRes, r.LeaveChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/start", r.StartChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/stop", r.StopChain, r.ChainInjector)
+	r.setRole(g.POST(UrlChainRes+"/start", r.StartChain, r.ChainInjector), RoleOperator)
+	r.setRole(g.POST(UrlChainRes+"/stop", r.StopChain, r.ChainInjector), RoleOperator)
 	g.POST(UrlChainRes+"/reset", r.ResetChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/verify", r.VerifyChain, r.ChainInjector)
+	r.setRole(g.POST(UrlChainRes+"/verify", r.VerifyChain, r.ChainInjector), RoleOperator)
 	g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector)
+	g.POST(UrlChainRes+"/export", r.ExportChain, r.ChainInjector)
+	g.POST(UrlChainRes+"/import-archive", r.ImportChainArchive, r.ChainInjector)
 	g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector)
-	g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector)
+	r.setRole(g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector), RoleOperator)
 	route := g.GET(UrlChainRes+"/genesis", r.GetChainGenesis, r.ChainInjector)
 	if r.a != nil {
 		r.a.SetSkip(route, false)
 	}
 	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector)
 	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector)
+	g.GET(UrlChainRes+"/ban", r.GetBannedPeers, r.ChainInjector)
+	r.setRole(g.POST(UrlChainRes+"/ban", r.BanPeer, r.ChainInjector), RoleOperator)
+	r.setRole(g.DELETE(UrlChainRes+"/ban/:"+ParamID, r.UnbanPeer, r.ChainInjector), RoleOperator)
 	g.POST(UrlChainRes+"/:"+TaskID, r.RunChainTask, r.ChainInjector)
 }
 
+func (r *Rest) setRole(route *echo.Route, role Role) {
+	if r.a != nil {
+		r.a.SetRole(route, role)
+	}
+}
+
 func (r *Rest) ChainInjector(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
 		p := ctx.Param(ParamCID)
@@ -400,6 +478,43 @@ func (r *Rest) ImportChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) ExportChain(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainExportParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if len(param.File) == 0 || param.From < 1 || param.To < param.From {
+		return echo.ErrBadRequest
+	}
+	var flags int
+	if param.Receipts {
+		flags |= archive.FlagReceipts
+	}
+	if param.State {
+		flags |= archive.FlagState
+	}
+	if err := r.n.ExportChain(c.CID(), param.File, param.From, param.To, flags); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) ImportChainArchive(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainImportArchiveParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if len(param.File) == 0 {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.ImportChainArchive(c.CID(), param.File); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) PruneChain(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	param := &ChainPruneParam{}
@@ -421,7 +536,7 @@ func (r *Rest) BackupChain(ctx echo.Context) error {
 	if err := ctx.Bind(param); err != nil {
 		return echo.ErrBadRequest
 	}
-	if name, err := r.n.BackupChain(c.CID(), param.Manual); err != nil {
+	if name, err := r.n.BackupChain(c.CID(), param.Manual, param.Incremental); err != nil {
 		return err
 	} else {
 		return ctx.String(http.StatusOK, name)
@@ -451,6 +566,72 @@ func (r *Rest) ConfigureChain(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func peerIDFromString(s string) (module.PeerID, error) {
+	addr, err := common.NewAddressFromString(s)
+	if err != nil || addr.IsContract() {
+		return nil, echo.ErrBadRequest
+	}
+	return network.NewPeerIDFromAddress(addr), nil
+}
+
+func (r *Rest) GetBannedPeers(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	l, err := r.n.GetBannedPeers(c.CID())
+	if err != nil {
+		return err
+	}
+	v := make([]*BannedPeerView, len(l))
+	for i, b := range l {
+		v[i] = &BannedPeerView{
+			ID:     b.ID.String(),
+			Reason: b.Reason,
+			Until:  b.Until,
+		}
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+func (r *Rest) BanPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &BanPeerParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	id, err := peerIDFromString(param.ID)
+	if err != nil {
+		return err
+	}
+	d := network.DefaultPeerBanDuration
+	if len(param.Duration) > 0 {
+		if d, err = time.ParseDuration(param.Duration); err != nil || d <= 0 {
+			return echo.ErrBadRequest
+		}
+	}
+	reason := param.Reason
+	if len(reason) == 0 {
+		reason = "manual"
+	}
+	if err := r.n.BanPeer(c.CID(), id, d, reason); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) UnbanPeer(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	id, err := peerIDFromString(ctx.Param(ParamID))
+	if err != nil {
+		return err
+	}
+	if err := r.n.UnbanPeer(c.CID(), id); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, fmt.Sprintf("%+v", err))
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RunChainTask(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	task := ctx.Param(TaskID)
@@ -474,6 +655,37 @@ func (r *Rest) RegisterSystemHandlers(g *echo.Group) {
 	g.POST("/configure", r.ConfigureSystem)
 	r.RegistryBackupHandlers(g.Group("/backup"))
 	r.RegistryRestoreHandlers(g.Group("/restore"))
+	route := g.GET("/audit", r.GetAuditRecords)
+	if r.a != nil {
+		r.a.SetSkip(route, false)
+	}
+	r.setRole(route, RoleAdmin)
+}
+
+func (r *Rest) GetAuditRecords(ctx echo.Context) error {
+	q := &AuditQuery{
+		User: ctx.QueryParam("user"),
+	}
+	if s := ctx.QueryParam("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil {
+			return echo.ErrBadRequest
+		}
+		q.Limit = limit
+	}
+	if s := ctx.QueryParam("since"); s != "" {
+		since, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return echo.ErrBadRequest
+		}
+		q.Since = since
+	}
+	q.Verify, _ = strconv.ParseBool(ctx.QueryParam("verify"))
+	records, err := r.au.Query(q)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, records)
 }
 
 func (r *Rest) GetSystem(ctx echo.Context) error {
@@ -486,7 +698,7 @@ func (r *Rest) GetSystem(ctx echo.Context) error {
 	v.Setting.P2PListenAddr = r.n.nt.GetListenAddress()
 	v.Setting.RPCAddr = r.n.cfg.RPCAddr
 	v.Setting.RPCDump = r.n.cfg.RPCDump
-	v.Config = r.n.rcfg
+	v.Config = r.n.rcfg.redacted()
 
 	format := ctx.QueryParam("format")
 	if format != "" {
@@ -496,7 +708,7 @@ func (r *Rest) GetSystem(ctx echo.Context) error {
 }
 
 func (r *Rest) GetSystemConfig(ctx echo.Context) error {
-	return ctx.JSON(http.StatusOK, r.n.rcfg)
+	return ctx.JSON(http.StatusOK, r.n.rcfg.redacted())
 }
 
 func (r *Rest) ConfigureSystem(ctx echo.Context) error {
@@ -556,6 +768,7 @@ func (r *Rest) RegisterUserHandlers(g *echo.Group) {
 	g.GET("", r.Users)
 	g.POST("", r.AddUser)
 	g.DELETE(UrlUserRes, r.RemoveUser)
+	g.POST(UrlUserRes+"/role", r.SetUserRole)
 }
 
 func (r *Rest) Users(ctx echo.Context) error {
@@ -564,12 +777,28 @@ func (r *Rest) Users(ctx echo.Context) error {
 
 func (r *Rest) AddUser(ctx echo.Context) error {
 	param := struct {
-		Id string `json:"id"`
+		Id     string          `json:"id"`
+		Role   *Role           `json:"role,omitempty"`
+		Chains map[string]Role `json:"chains,omitempty"`
 	}{}
 	if err := ctx.Bind(&param); err != nil {
 		return echo.ErrBadRequest
 	}
-	if err := r.a.AddUser(param.Id); err != nil {
+	roles := &UserRoles{Role: RoleAdmin}
+	if param.Role != nil {
+		roles.Role = *param.Role
+	}
+	for cid, role := range param.Chains {
+		key, err := ChainKeyOf(cid)
+		if err != nil {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		if roles.Chains == nil {
+			roles.Chains = make(map[string]Role)
+		}
+		roles.Chains[key] = role
+	}
+	if err := r.a.AddUser(param.Id, roles); err != nil {
 		if we, ok := err.(errors.Unwrapper); ok {
 			switch we.Unwrap() {
 			case ErrAlreadyExists:
@@ -592,6 +821,29 @@ func (r *Rest) RemoveUser(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) SetUserRole(ctx echo.Context) error {
+	p := ctx.Param(ParamID)
+	param := &UserRoleParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	var chain string
+	if param.Chain != "" {
+		key, err := ChainKeyOf(param.Chain)
+		if err != nil {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		chain = key
+	}
+	if err := r.a.SetUserRole(p, chain, param.Role); err != nil {
+		if we, ok := err.(errors.Unwrapper); ok && we.Unwrap() == ErrNotExists {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RegisterStatsHandlers(g *echo.Group) {
 	g.GET("", r.StreamStats)
 }
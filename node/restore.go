@@ -23,15 +23,20 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 )
 
 const (
 	RestoreDirectoryPrefix = ".restore"
+
+	maxIncrementalBackups = 1000
 )
 
 type RestoreState int
@@ -103,35 +108,27 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 		}
 	}()
 
-	zr, err := zip.OpenReader(file)
+	zrs, info, err := openBackups(file)
 	if err != nil {
-		return errors.IllegalArgumentError.Wrapf(err,
-			"ZipOpenFailure(backup=%s)", file)
+		return err
 	}
 	defer func() {
 		if ret != nil {
-			zr.Close()
+			closeBackups(zrs)
 		}
 	}()
 
-	info, err := chain.ReadBackupInfo(&zr.Reader)
-	if err != nil {
-		return errors.IllegalArgumentError.Wrap(err,
-			"InvalidBackupInfo")
-	}
-
-	if info.Codec != codec.BC.Name() {
-		return errors.IllegalArgumentError.Errorf(
-			"IncompatibleCodec(backup=%s,system=%s)",
-			info.Codec, codec.BC.Name())
-	}
-
 	if err := node.CanAdd(int(info.CID.Value), int(info.NID.Value), info.Channel, overwrite); err != nil {
 		return err
 	}
 
+	total := 0
+	for _, zr := range zrs {
+		total += len(zr.File)
+	}
+
 	go func() {
-		if err := m._restore(node, zr, tmpDir, overwrite); err != nil {
+		if err := m._restore(node, zrs, tmpDir, overwrite); err != nil {
 			node.logger.Debugf("Restore failed err=%+v", err)
 			if errors.InterruptedError.Equals(err) {
 				m._setState(RestoreNone, nil)
@@ -147,10 +144,84 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 	m.overwrite = overwrite
 	m.state = RestoreStarted
 	m.current = 0
-	m.total = len(zr.File)
+	m.total = total
 	return nil
 }
 
+func openBackup(file string) (*zip.ReadCloser, *chain.BackupInfo, error) {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, nil, errors.IllegalArgumentError.Wrapf(err,
+			"ZipOpenFailure(backup=%s)", file)
+	}
+	info, err := chain.ReadBackupInfo(&zr.Reader)
+	if err != nil {
+		zr.Close()
+		return nil, nil, errors.IllegalArgumentError.Wrap(err,
+			"InvalidBackupInfo")
+	}
+	if info.Codec != codec.BC.Name() {
+		zr.Close()
+		return nil, nil, errors.IllegalArgumentError.Errorf(
+			"IncompatibleCodec(backup=%s,system=%s)",
+			info.Codec, codec.BC.Name())
+	}
+	return zr, info, nil
+}
+
+// openBackups opens the backup and its bases in the same directory. It
+// returns the backups in the order to restore, which starts with the full
+// backup and ends with the given backup.
+func openBackups(file string) (zrs []*zip.ReadCloser, last *chain.BackupInfo, ret error) {
+	defer func() {
+		if ret != nil {
+			closeBackups(zrs)
+		}
+	}()
+
+	dir := path.Dir(file)
+	for {
+		zr, info, err := openBackup(file)
+		if err != nil {
+			return zrs, nil, err
+		}
+		zrs = append([]*zip.ReadCloser{zr}, zrs...)
+		if last == nil {
+			last = info
+		} else if info.CID != last.CID || info.NID != last.NID {
+			return zrs, nil, errors.IllegalArgumentError.Errorf(
+				"InvalidBaseBackup(backup=%s,cid=%s,nid=%s)",
+				path.Base(file), info.CID, info.NID)
+		}
+		if !info.IsIncremental() {
+			return zrs, last, nil
+		}
+		if len(zrs) > maxIncrementalBackups {
+			return zrs, nil, errors.IllegalArgumentError.Errorf(
+				"TooManyIncrementalBackups(backup=%s)", path.Base(file))
+		}
+		base := path.Join(dir, path.Base(info.Base))
+		zr2, info2, err := openBackup(base)
+		if err != nil {
+			return zrs, nil, errors.NotFoundError.Wrapf(err,
+				"BaseBackupFailure(backup=%s,base=%s)", path.Base(file), info.Base)
+		}
+		zr2.Close()
+		if info2.Height != info.BaseHeight {
+			return zrs, nil, errors.IllegalArgumentError.Errorf(
+				"BaseHeightMismatch(backup=%s,base=%s,height=%d,expected=%d)",
+				path.Base(file), info.Base, info2.Height, info.BaseHeight)
+		}
+		file = base
+	}
+}
+
+func closeBackups(zrs []*zip.ReadCloser) {
+	for _, zr := range zrs {
+		zr.Close()
+	}
+}
+
 func (m *RestoreManager) _onRestored(idx int) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -235,24 +306,98 @@ func zipExtract(file *zip.File, tmpDir string) (ret error) {
 	return err
 }
 
-func (m *RestoreManager) _restore(node *Node, zr *zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
+func (m *RestoreManager) _restore(node *Node, zrs []*zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
 	defer func() {
 		if ret != nil {
 			os.RemoveAll(tmpDir)
 		}
 	}()
-	defer zr.Close()
+	defer closeBackups(zrs)
+
+	idx := 0
+	for i, zr := range zrs {
+		if i == 0 {
+			for _, file := range zr.File {
+				if err := zipExtract(file, tmpDir); err != nil {
+					return err
+				}
+				if err := m._onRestored(idx); err != nil {
+					return err
+				}
+				idx += 1
+			}
+		} else {
+			if err := m._restoreIncremental(node, &zr.Reader, tmpDir, &idx); err != nil {
+				return err
+			}
+		}
+	}
 
-	for idx, file := range zr.File {
-		if err := zipExtract(file, tmpDir); err != nil {
-			return err
+	return node.restoreChain(tmpDir, overwrite)
+}
+
+// _restoreIncremental applies the incremental backup to the chain directory
+// restored from its bases. WAL is replaced, and other files are
+// overwritten.
+func (m *RestoreManager) _restoreIncremental(node *Node, zr *zip.Reader, chainDir string, idx *int) error {
+	if err := os.RemoveAll(path.Join(chainDir, chain.DefaultWALDir)); err != nil {
+		return err
+	}
+	var dbChanges *zip.File
+	for _, file := range zr.File {
+		if file.Name == chain.IncrementalDBFile {
+			dbChanges = file
+		} else {
+			target := path.Join(chainDir, file.Name)
+			if !strings.HasPrefix(target, chainDir+"/") {
+				return errors.IllegalArgumentError.Errorf(
+					"InvalidEntryName(name=%s)", file.Name)
+			}
+			if file.Mode().IsRegular() {
+				if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+			if err := zipExtract(file, chainDir); err != nil {
+				return err
+			}
 		}
-		if err := m._onRestored(idx); err != nil {
+		if err := m._onRestored(*idx); err != nil {
 			return err
 		}
+		*idx += 1
+	}
+	if dbChanges == nil {
+		return nil
+	}
+	cfg, err := node.loadChainConfig(chainDir)
+	if err != nil {
+		return err
 	}
+	return applyDBChanges(dbChanges, chainDir, cfg)
+}
 
-	return node.restoreChain(tmpDir, overwrite)
+func applyDBChanges(file *zip.File, chainDir string, cfg *chain.Config) error {
+	dbType := cfg.DBType
+	if dbType == "" {
+		dbType = string(db.GoLevelDBBackend)
+	}
+	dbDir := path.Join(chainDir, chain.DefaultDBDir)
+	if err := os.MkdirAll(dbDir, 0700); err != nil {
+		return err
+	}
+	database, err := db.Open(dbDir, dbType, strconv.FormatInt(int64(cfg.NID), 16))
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return chain.ApplyDBChanges(database, rc)
 }
 
 func (m *RestoreManager) Stop() error {
@@ -72,6 +72,8 @@ func (c ErrorCode) String() string {
 		return "Timeout"
 	case ErrorCodeSystemTimeout:
 		return "SystemTimeout"
+	case ErrorCodeRateLimit:
+		return "RateLimit"
 	default:
 		switch {
 		case c < ErrorCodeServer && c > ErrorCodeServer-1000:
@@ -104,6 +106,7 @@ const (
 	ErrorLackOfResource     ErrorCode = -31005
 	ErrorCodeTimeout        ErrorCode = -31006
 	ErrorCodeSystemTimeout  ErrorCode = -31007
+	ErrorCodeRateLimit      ErrorCode = -31008
 )
 
 type Error struct {
@@ -137,6 +140,10 @@ func ErrMethodNotFound(message ...interface{}) *Error {
 	return ErrorCodeMethodNotFound.NewWithData(firstOf(message...))
 }
 
+func ErrRateLimit(message ...interface{}) *Error {
+	return ErrorCodeRateLimit.NewWithData(firstOf(message...))
+}
+
 func ErrInvalidParams(message ...interface{}) *Error {
 	return ErrorCodeInvalidParams.NewWithData(firstOf(message...))
 }
@@ -161,6 +168,9 @@ func ErrorHandler(re *Error, c echo.Context) {
 		Error:   re,
 	}
 	status = http.StatusBadRequest
+	if re.Code == ErrorCodeRateLimit {
+		status = http.StatusTooManyRequests
+	}
 
 	// Send response
 	if !c.Response().Committed {
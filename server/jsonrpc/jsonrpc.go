@@ -142,6 +142,18 @@ func (ctx *Context) BatchLimit() int {
 	return batchLimit
 }
 
+// Limiter decides whether the client of the context may call the method.
+type Limiter interface {
+	Allow(c echo.Context, method string) bool
+}
+
+func (ctx *Context) Allow(method string) bool {
+	if limiter, ok := ctx.Get("rateLimiter").(Limiter); ok && limiter != nil {
+		return limiter.Allow(ctx.Context, method)
+	}
+	return true
+}
+
 func (ctx *Context) GetTimeout(t time.Duration) time.Duration {
 	if v, err := ctx.opts.GetInt(IconOptionsTimeout); err != nil {
 		return t
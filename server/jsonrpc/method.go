@@ -100,6 +100,11 @@ func (mr *MethodRepository) handle(ctx *Context, raw json.RawMessage) *Response
 		return resp
 	}
 
+	if !ctx.Allow(*req.Method) {
+		resp.Error = ErrRateLimit(*req.Method)
+		return resp
+	}
+
 	if req.ID == nil && !mr.IsAllowedNotification(*req.Method) {
 		//Ignore not-allowed notification request
 		resp.Error = ErrorCodeInvalidRequest.Wrap(
@@ -175,6 +180,9 @@ func (mr *MethodRepository) Handle(c echo.Context) error {
 		resp := mr.handle(ctx, raw)
 		if resp != nil {
 			if resp.Error != nil {
+				if resp.Error.Code == ErrorCodeRateLimit {
+					return c.JSON(http.StatusTooManyRequests, resp)
+				}
 				return c.JSON(http.StatusBadRequest, resp)
 			} else {
 				return c.JSON(http.StatusOK, resp)
@@ -214,3 +214,31 @@ func noArgs(ctx *Context, params *Params) (interface{}, error) {
 	}
 	return "noArgs", nil
 }
+
+type denyHelloLimiter struct{}
+
+func (denyHelloLimiter) Allow(c echo.Context, method string) bool {
+	return method != "hello"
+}
+
+func TestMethodRepository_RateLimit(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.RegisterMethod("hello", hello)
+	mr.RegisterMethod("noArgs", noArgs)
+
+	req := `{"jsonrpc":"2.0","method":"hello","params":{"name":"icon"},"id":"1001"}`
+	c, rec, err := prepare(req)
+	assert.NoError(t, err)
+	c.Set("rateLimiter", denyHelloLimiter{})
+	assert.NoError(t, mr.Handle(c))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-31008,"message":"RateLimit","data":"hello"},"id":"1001"}`+"\n", rec.Body.String())
+
+	req = `{"jsonrpc":"2.0","method":"noArgs","id":"1001"}`
+	c, rec, err = prepare(req)
+	assert.NoError(t, err)
+	c.Set("rateLimiter", denyHelloLimiter{})
+	assert.NoError(t, mr.Handle(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
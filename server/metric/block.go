@@ -0,0 +1,57 @@
+package metric
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// boundaries of latency histograms in seconds
+	blockLatencyBuckets = []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+	pmBlockImport = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNamespace,
+		Name:      "block_import_duration_seconds",
+		Help:      "Block import latency until validation",
+		Buckets:   blockLatencyBuckets,
+	}, chainLabelNames)
+	pmBlockExecute = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNamespace,
+		Name:      "block_execute_duration_seconds",
+		Help:      "Execution latency of transactions in imported blocks",
+		Buckets:   blockLatencyBuckets,
+	}, chainLabelNames)
+)
+
+func RegisterBlock() {
+	registerCollectors(pmBlockImport, pmBlockExecute)
+}
+
+// BlockMetric records latencies of block processing. It implements
+// block.MetricRecorder.
+type BlockMetric struct {
+	imports  prometheus.Observer
+	executes prometheus.Observer
+}
+
+// OnImport records the duration from the start of the import to the
+// validation of the block.
+func (m *BlockMetric) OnImport(d time.Duration) {
+	m.imports.Observe(d.Seconds())
+}
+
+// OnExecute records the duration for executing the transactions of
+// the previous block included in the block.
+func (m *BlockMetric) OnExecute(d time.Duration) {
+	m.executes.Observe(d.Seconds())
+}
+
+func NewBlockMetric(ctx context.Context) *BlockMetric {
+	lvs := chainLabelValuesOf(ctx)
+	return &BlockMetric{
+		imports:  pmBlockImport.WithLabelValues(lvs...),
+		executes: pmBlockExecute.WithLabelValues(lvs...),
+	}
+}
@@ -0,0 +1,37 @@
+package metric
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opencensus.io/tag"
+)
+
+const promNamespace = "goloop"
+
+var (
+	// promRegistry is shared with the OpenCensus exporter, so metrics of
+	// native collectors are served at the same endpoint.
+	promRegistry = prometheus.NewRegistry()
+
+	chainLabelNames = []string{"channel", "nid"}
+)
+
+// chainLabelValuesOf returns values for chainLabelNames from the tags of
+// the metric context.
+func chainLabelValuesOf(ctx context.Context) []string {
+	m := tag.FromContext(ctx)
+	channel, _ := m.Value(MetricKeyChain)
+	nid, _ := m.Value(MetricKeyNID)
+	return []string{channel, nid}
+}
+
+func registerCollectors(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := promRegistry.Register(c); err != nil {
+			if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+				panic(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package metric
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+var (
+	pdDBRead = prometheus.NewDesc(
+		prometheus.BuildFQName(promNamespace, "", "db_read_total"),
+		"Reads of the database bucket",
+		append(chainLabelNames, "bucket"), nil)
+	pdDBWrite = prometheus.NewDesc(
+		prometheus.BuildFQName(promNamespace, "", "db_write_total"),
+		"Writes of the database bucket",
+		append(chainLabelNames, "bucket"), nil)
+
+	bucketNames = map[db.BucketID]string{
+		db.MerkleTrie:               "merkle_trie",
+		db.BytesByHash:              "bytes_by_hash",
+		db.TransactionLocatorByHash: "tx_locator",
+		db.BlockHeaderHashByHeight:  "block_header_hash",
+		db.ChainProperty:            "chain_property",
+		db.EventIndex:               "event_index",
+	}
+
+	dbCollector = &databaseCollector{
+		metrics: make(map[string]*DatabaseMetric),
+	}
+)
+
+func RegisterDatabase() {
+	registerCollectors(dbCollector)
+}
+
+func bucketNameOf(id db.BucketID) string {
+	if name, ok := bucketNames[id]; ok {
+		return name
+	}
+	return hex.EncodeToString([]byte(id))
+}
+
+// databaseCollector exports counts of DatabaseMetric of the chains when
+// it's scraped.
+type databaseCollector struct {
+	lock    sync.Mutex
+	metrics map[string]*DatabaseMetric
+}
+
+func (c *databaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- pdDBRead
+	ch <- pdDBWrite
+}
+
+func (c *databaseCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, m := range c.metrics {
+		m.collect(ch)
+	}
+}
+
+func (c *databaseCollector) add(m *DatabaseMetric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.metrics[m.channel] = m
+}
+
+func (c *databaseCollector) remove(channel string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.metrics, channel)
+}
+
+type bucketCounts struct {
+	reads  uint64
+	writes uint64
+}
+
+// DatabaseMetric counts reads and writes of the buckets. It implements
+// db.Monitor. Counts are aggregated in memory and exported on scraping.
+type DatabaseMetric struct {
+	channel string
+	nid     string
+	buckets sync.Map
+}
+
+func (m *DatabaseMetric) countsOf(id db.BucketID) *bucketCounts {
+	if c, ok := m.buckets.Load(id); ok {
+		return c.(*bucketCounts)
+	}
+	c, _ := m.buckets.LoadOrStore(id, new(bucketCounts))
+	return c.(*bucketCounts)
+}
+
+func (m *DatabaseMetric) OnRead(id db.BucketID) {
+	atomic.AddUint64(&m.countsOf(id).reads, 1)
+}
+
+func (m *DatabaseMetric) OnWrite(id db.BucketID) {
+	atomic.AddUint64(&m.countsOf(id).writes, 1)
+}
+
+func (m *DatabaseMetric) collect(ch chan<- prometheus.Metric) {
+	m.buckets.Range(func(key, value interface{}) bool {
+		bucket := bucketNameOf(key.(db.BucketID))
+		c := value.(*bucketCounts)
+		ch <- prometheus.MustNewConstMetric(pdDBRead, prometheus.CounterValue,
+			float64(atomic.LoadUint64(&c.reads)), m.channel, m.nid, bucket)
+		ch <- prometheus.MustNewConstMetric(pdDBWrite, prometheus.CounterValue,
+			float64(atomic.LoadUint64(&c.writes)), m.channel, m.nid, bucket)
+		return true
+	})
+}
+
+// NewDatabaseMetric returns a new metric for the chain of the context.
+// It replaces the metric previously created for the same chain.
+func NewDatabaseMetric(ctx context.Context) *DatabaseMetric {
+	lvs := chainLabelValuesOf(ctx)
+	m := &DatabaseMetric{
+		channel: lvs[0],
+		nid:     lvs[1],
+	}
+	dbCollector.add(m)
+	return m
+}
@@ -0,0 +1,37 @@
+package metric
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pmEEAssigned = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "eeproxy_assigned",
+		Help:      "Assigned executors",
+	}, []string{"priority"})
+	pmEEWaiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "eeproxy_waiting",
+		Help:      "Requests waiting for executors",
+	}, []string{"priority"})
+)
+
+func RegisterEEProxy() {
+	registerCollectors(pmEEAssigned, pmEEWaiting)
+}
+
+// EEProxyMetric records the states of executor queues. Executors are shared
+// by the chains, so it's not labelled with the chain.
+type EEProxyMetric struct{}
+
+// OnExecutorState records the number of assigned executors and
+// the number of requests waiting for executors.
+func (m *EEProxyMetric) OnExecutorState(priority string, assigned, waiting int) {
+	pmEEAssigned.WithLabelValues(priority).Set(float64(assigned))
+	pmEEWaiting.WithLabelValues(priority).Set(float64(waiting))
+}
+
+func NewEEProxyMetric() *EEProxyMetric {
+	return &EEProxyMetric{}
+}
@@ -22,8 +22,10 @@ var (
 		msAvg: stats.Int64("jsonrpc_retrieve_avg", "moving average of jsonrpc retrieve methods", "ns"),
 		mks:   []tag.Key{mkMethod},
 	}
-	emptyMks = []tag.Key{}
-	msMap    = map[string]*measure{
+	mkGroup     = NewMetricKey("group")
+	msRateLimit = stats.Int64("jsonrpc_rate_limit", "jsonrpc requests rejected by rate limit", "")
+	emptyMks    = []tag.Key{}
+	msMap       = map[string]*measure{
 		"icx_getLastBlock":     msRetrieve,
 		"icx_getBlockByHeight": msRetrieve,
 		"icx_getBlockByHash":   msRetrieve,
@@ -57,6 +59,8 @@ var (
 		"icx_getVotesByHeight":       msRetrieve,
 		"icx_getProofForResult":      msRetrieve,
 		"icx_getProofForEvents":      msRetrieve,
+		"icx_getProofForAccount":     msRetrieve,
+		"icx_getProofForStorage":     msRetrieve,
 		"icx_getScoreStatus":         msRetrieve,
 		"btp_getNetworkInfo":         msRetrieve,
 		"btp_getNetworkTypeInfo":     msRetrieve,
@@ -74,6 +78,11 @@ var (
 			stats.Int64("jsonrpc_estimate_step_avg", "moving average of jsonrpc debug_estimateStep method", "ns"),
 			emptyMks,
 		},
+		"debug_simulateTransaction": {
+			stats.Int64("jsonrpc_simulate_transaction", "jsonrpc debug_simulateTransaction method", "ns"),
+			stats.Int64("jsonrpc_simulate_transaction_avg", "moving average of jsonrpc debug_simulateTransaction method", "ns"),
+			emptyMks,
+		},
 		"rosetta_getTrace": {
 			stats.Int64("jsonrpc_rosetta_trace_", "jsonrpc rosetta_getTrace method", "ns"),
 			stats.Int64("jsonrpc_rosetta_trace_avg", "moving average of jsonrpc rosetta_getTTrace method", "ns"),
@@ -100,6 +109,7 @@ func RegisterJsonrpc() {
 	RegisterMetricView(msFailure.msAvg, view.LastValue(), emptyMks)
 	RegisterMetricView(msRetrieve.ms, view.Count(), msRetrieve.mks)
 	RegisterMetricView(msRetrieve.msAvg, view.LastValue(), emptyMks)
+	RegisterMetricView(msRateLimit, view.Count(), []tag.Key{mkGroup})
 	for _, v := range msMap {
 		if v != msRetrieve {
 			RegisterMetricView(v.ms, view.Count(), v.mks)
@@ -197,6 +207,12 @@ func (m *JsonrpcMetric) OnHandle(ctx context.Context, method string, ts time.Tim
 	jm.RemoveAndRecord(ctx, ts, m.expire)
 }
 
+// OnRateLimit records the request of the group rejected by rate limit.
+func (m *JsonrpcMetric) OnRateLimit(ctx context.Context, group string) {
+	ctx = GetMetricContext(ctx, &mkGroup, group)
+	stats.Record(ctx, msRateLimit.M(1))
+}
+
 func NewJsonrpcMetric(expire time.Duration, durationsSize int, useDefault bool) *JsonrpcMetric {
 	jmsMtx.Lock()
 	defer jmsMtx.Unlock()
@@ -21,7 +21,8 @@ import (
 var (
 	MetricKeyHostname = NewMetricKey("hostname")
 	MetricKeyChain    = NewMetricKey("channel")
-	mKeys             = []tag.Key{MetricKeyHostname, MetricKeyChain}
+	MetricKeyNID      = NewMetricKey("nid")
+	mKeys             = []tag.Key{MetricKeyHostname, MetricKeyChain, MetricKeyNID}
 	mTags             = make(map[*tag.Key]map[string]tag.Mutator)
 	mViews            = make(map[string]*view.View)
 	mViewMtx          sync.RWMutex
@@ -131,6 +132,13 @@ func GetMetricContextByCID(cid int) context.Context {
 	return ctx
 }
 
+// GetMetricContextByChain returns the metric context for the chain, which is
+// labelled with both of the channel and the network ID.
+func GetMetricContextByChain(cid, nid int) context.Context {
+	return GetMetricContext(GetMetricContextByCID(cid), &MetricKeyNID,
+		strconv.FormatInt(int64(nid), 16))
+}
+
 func RemoveMetricContextByCID(cid int) {
 	chainMetricMtx.Lock()
 	defer chainMetricMtx.Unlock()
@@ -139,6 +147,7 @@ func RemoveMetricContextByCID(cid int) {
 	if _, ok := chainMetricCtxs[chainID]; ok {
 		delete(chainMetricCtxs, chainID)
 		RemoveMetricContext(&MetricKeyChain, chainID)
+		dbCollector.remove(chainID)
 	}
 }
 
@@ -174,7 +183,8 @@ func Initialize(w module.Wallet) {
 func PrometheusExporter() *prometheus.Exporter {
 	// prometheus
 	pe, err := prometheus.NewExporter(prometheus.Options{
-		Namespace: "goloop",
+		Namespace: promNamespace,
+		Registry:  promRegistry,
 	})
 
 	if err != nil {
@@ -189,6 +199,10 @@ func PrometheusExporter() *prometheus.Exporter {
 	RegisterNetwork()
 	RegisterTransaction()
 	RegisterJsonrpc()
+	RegisterBlock()
+	RegisterSync()
+	RegisterEEProxy()
+	RegisterDatabase()
 	return pe
 }
 
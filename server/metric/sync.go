@@ -0,0 +1,42 @@
+package metric
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	pmSyncResolved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "statesync_resolved",
+		Help:      "Resolved state sync requests",
+	}, chainLabelNames)
+	pmSyncUnresolved = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "statesync_unresolved",
+		Help:      "Unresolved state sync requests",
+	}, chainLabelNames)
+)
+
+func RegisterSync() {
+	registerCollectors(pmSyncResolved, pmSyncUnresolved)
+}
+
+type SyncMetric struct {
+	resolved   prometheus.Gauge
+	unresolved prometheus.Gauge
+}
+
+func (m *SyncMetric) OnProgress(resolved, unresolved int) {
+	m.resolved.Set(float64(resolved))
+	m.unresolved.Set(float64(unresolved))
+}
+
+func NewSyncMetric(ctx context.Context) *SyncMetric {
+	lvs := chainLabelValuesOf(ctx)
+	return &SyncMetric{
+		resolved:   pmSyncResolved.WithLabelValues(lvs...),
+		unresolved: pmSyncUnresolved.WithLabelValues(lvs...),
+	}
+}
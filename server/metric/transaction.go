@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
@@ -26,6 +27,17 @@ var (
 	msCommitLatency = stats.Int64("txlatency_commit", "Commit Transaction Latency", stats.UnitMilliseconds)
 	mkTxType        = NewMetricKey("tx_type")
 	txPoolMks       = []tag.Key{mkTxType}
+
+	pmPoolSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "txpool_size",
+		Help:      "Capacity of the transaction pool",
+	}, append(chainLabelNames, "tx_type"))
+	pmPoolUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespace,
+		Name:      "txpool_used",
+		Help:      "Transactions in the pool",
+	}, append(chainLabelNames, "tx_type"))
 )
 
 func RegisterTransaction() {
@@ -43,6 +55,7 @@ func RegisterTransaction() {
 	RegisterMetricView(msDropUserTx, view.Sum(), txPoolMks)
 	RegisterMetricView(msFinLatency, view.LastValue(), txPoolMks)
 	RegisterMetricView(msCommitLatency, view.LastValue(), txPoolMks)
+	registerCollectors(pmPoolSize, pmPoolUsed)
 }
 
 type commitRecord struct {
@@ -54,6 +67,8 @@ type TxMetric struct {
 	lock    sync.Mutex
 	context context.Context
 	commits map[string]*commitRecord
+	size    prometheus.Gauge
+	used    prometheus.Gauge
 }
 
 func (c *TxMetric) OnAddTx(n int, user bool) {
@@ -77,6 +92,11 @@ func (c *TxMetric) OnDropTx(n int, user bool) {
 	}
 }
 
+func (c *TxMetric) OnPoolCapacityUpdated(size, used int) {
+	c.size.Set(float64(size))
+	c.used.Set(float64(used))
+}
+
 func (c *TxMetric) OnFinalize(hash []byte, ts time.Time) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -106,8 +126,11 @@ func (c *TxMetric) OnCommit(hash []byte, ts time.Time, d time.Duration) {
 }
 
 func NewTransactionMetric(ctx context.Context, t string) *TxMetric {
+	lvs := append(chainLabelValuesOf(ctx), t)
 	return &TxMetric{
 		context: GetMetricContext(ctx, &mkTxType, t),
 		commits: make(map[string]*commitRecord),
+		size:    pmPoolSize.WithLabelValues(lvs...),
+		used:    pmPoolUsed.WithLabelValues(lvs...),
 	}
 }
@@ -0,0 +1,238 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+const (
+	RateLimitGroupICX       = "icx"
+	RateLimitGroupDebug     = "debug"
+	RateLimitGroupBTP       = "btp"
+	RateLimitGroupRosetta   = "rosetta"
+	RateLimitGroupWebSocket = "ws"
+
+	HeaderKeyAPIKey = "X-Api-Key"
+
+	rateClientExpire = 10 * time.Minute
+)
+
+var rateLimitGroups = map[string]bool{
+	RateLimitGroupICX:       true,
+	RateLimitGroupDebug:     true,
+	RateLimitGroupBTP:       true,
+	RateLimitGroupRosetta:   true,
+	RateLimitGroupWebSocket: true,
+}
+
+// RateLimit is the budget of a client for a group. Rate is the number of
+// requests per second and Burst is the maximum number of requests at once.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimits is the budgets for the groups. Groups without budget are not
+// limited.
+type RateLimits map[string]RateLimit
+
+// ParseRateLimits parses the specification of rate limits.
+// The format is comma separated "<group>=<rate>[:<burst>]"
+// (ex. "icx=100:200,debug=1,ws=0.1:5"). If burst is omitted, it uses
+// the rate rounded up.
+func ParseRateLimits(s string) (RateLimits, error) {
+	limits := make(RateLimits)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rate limit %q", item)
+		}
+		group := strings.TrimSpace(kv[0])
+		if !rateLimitGroups[group] {
+			return nil, fmt.Errorf("unknown rate limit group %q", group)
+		}
+		var limit RateLimit
+		values := strings.SplitN(kv[1], ":", 2)
+		r, err := strconv.ParseFloat(strings.TrimSpace(values[0]), 64)
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("invalid rate %q for %s", values[0], group)
+		}
+		limit.Rate = r
+		if len(values) > 1 {
+			b, err := strconv.Atoi(strings.TrimSpace(values[1]))
+			if err != nil || b <= 0 {
+				return nil, fmt.Errorf("invalid burst %q for %s", values[1], group)
+			}
+			limit.Burst = b
+		} else {
+			limit.Burst = int(math.Ceil(r))
+		}
+		limits[group] = limit
+	}
+	return limits, nil
+}
+
+func (l RateLimits) String() string {
+	groups := make([]string, 0, len(l))
+	for group := range l {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	items := make([]string, len(groups))
+	for i, group := range groups {
+		limit := l[group]
+		items[i] = fmt.Sprintf("%s=%s:%d", group,
+			strconv.FormatFloat(limit.Rate, 'f', -1, 64), limit.Burst)
+	}
+	return strings.Join(items, ",")
+}
+
+// MethodGroupOf returns the rate limit group of the JSON-RPC method.
+func MethodGroupOf(method string) string {
+	if idx := strings.Index(method, "_"); idx > 0 {
+		return method[:idx]
+	}
+	return ""
+}
+
+type rateClient struct {
+	limiters map[string]*rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter applies token bucket rate limits to each client. Clients
+// with registered API key are identified by the key, and others are
+// identified by the remote IP address.
+type rateLimiter struct {
+	mtx     sync.Mutex
+	limits  RateLimits
+	apiKeys map[string]bool
+	clients map[string]*rateClient
+	swept   time.Time
+	mtr     *metric.JsonrpcMetric
+}
+
+func newRateLimiter(mtr *metric.JsonrpcMetric) *rateLimiter {
+	return &rateLimiter{
+		limits:  make(RateLimits),
+		apiKeys: make(map[string]bool),
+		clients: make(map[string]*rateClient),
+		mtr:     mtr,
+	}
+}
+
+func (rl *rateLimiter) SetLimits(limits RateLimits) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	rl.limits = limits
+	rl.clients = make(map[string]*rateClient)
+}
+
+func (rl *rateLimiter) SetAPIKeys(keys []string) {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	rl.apiKeys = make(map[string]bool)
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if len(key) > 0 {
+			rl.apiKeys[key] = true
+		}
+	}
+	rl.clients = make(map[string]*rateClient)
+}
+
+func (rl *rateLimiter) clientOf(c echo.Context) string {
+	if key := c.Request().Header.Get(HeaderKeyAPIKey); len(key) > 0 {
+		if rl.apiKeys[key] {
+			return "key:" + key
+		}
+	}
+	return "ip:" + echo.ExtractIPDirect()(c.Request())
+}
+
+func (rl *rateLimiter) sweepInLock(now time.Time) {
+	if now.Sub(rl.swept) < time.Minute {
+		return
+	}
+	rl.swept = now
+	for id, client := range rl.clients {
+		if now.Sub(client.lastSeen) > rateClientExpire {
+			delete(rl.clients, id)
+		}
+	}
+}
+
+func (rl *rateLimiter) allowGroup(c echo.Context, group string) bool {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+
+	limit, ok := rl.limits[group]
+	if !ok {
+		return true
+	}
+	now := time.Now()
+	rl.sweepInLock(now)
+
+	id := rl.clientOf(c)
+	client, ok := rl.clients[id]
+	if !ok {
+		client = &rateClient{
+			limiters: make(map[string]*rate.Limiter),
+		}
+		rl.clients[id] = client
+	}
+	client.lastSeen = now
+	limiter, ok := client.limiters[group]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+		client.limiters[group] = limiter
+	}
+	if limiter.AllowN(now, 1) {
+		return true
+	}
+	rl.mtr.OnRateLimit(metricContextOf(c), group)
+	return false
+}
+
+// Allow implements jsonrpc.Limiter.
+func (rl *rateLimiter) Allow(c echo.Context, method string) bool {
+	return rl.allowGroup(c, MethodGroupOf(method))
+}
+
+// SessionLimiter returns the middleware limiting websocket sessions.
+func (rl *rateLimiter) SessionLimiter() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if !rl.allowGroup(ctx, RateLimitGroupWebSocket) {
+				return jsonrpc.ErrRateLimit(RateLimitGroupWebSocket)
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func metricContextOf(c echo.Context) context.Context {
+	if chain, ok := c.Get("chain").(module.Chain); ok && chain != nil {
+		return chain.MetricContext()
+	}
+	return metric.DefaultMetricContext()
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/server/metric"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits("icx=100:200, debug=0.5,ws=2")
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimits{
+		RateLimitGroupICX:       {Rate: 100, Burst: 200},
+		RateLimitGroupDebug:     {Rate: 0.5, Burst: 1},
+		RateLimitGroupWebSocket: {Rate: 2, Burst: 2},
+	}, limits)
+	assert.Equal(t, "debug=0.5:1,icx=100:200,ws=2:2", limits.String())
+
+	limits, err = ParseRateLimits("")
+	assert.NoError(t, err)
+	assert.Len(t, limits, 0)
+
+	for _, spec := range []string{"icx", "foo=1", "icx=0", "icx=a", "icx=1:0", "icx=1:b"} {
+		_, err = ParseRateLimits(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestMethodGroupOf(t *testing.T) {
+	assert.Equal(t, RateLimitGroupICX, MethodGroupOf("icx_call"))
+	assert.Equal(t, RateLimitGroupDebug, MethodGroupOf("debug_getTrace"))
+	assert.Equal(t, RateLimitGroupRosetta, MethodGroupOf("rosetta_getTrace"))
+	assert.Equal(t, "", MethodGroupOf("hello"))
+}
+
+func newRateLimitContext(addr, key string) echo.Context {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = addr
+	if len(key) > 0 {
+		req.Header.Set(HeaderKeyAPIKey, key)
+	}
+	return e.NewContext(req, httptest.NewRecorder())
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	rl := newRateLimiter(mtr)
+	limits, err := ParseRateLimits("icx=0.001:2")
+	assert.NoError(t, err)
+	rl.SetLimits(limits)
+	rl.SetAPIKeys([]string{"key1"})
+
+	c1 := newRateLimitContext("10.0.0.1:1000", "")
+	assert.True(t, rl.Allow(c1, "icx_call"))
+	assert.True(t, rl.Allow(c1, "icx_getBalance"))
+	assert.False(t, rl.Allow(c1, "icx_call"))
+
+	// other groups are not limited
+	assert.True(t, rl.Allow(c1, "debug_getTrace"))
+
+	// same address with unknown key shares the budget
+	c2 := newRateLimitContext("10.0.0.1:1001", "unknown")
+	assert.False(t, rl.Allow(c2, "icx_call"))
+
+	// other address has its own budget
+	c3 := newRateLimitContext("10.0.0.2:1000", "")
+	assert.True(t, rl.Allow(c3, "icx_call"))
+
+	// registered key has its own budget
+	c4 := newRateLimitContext("10.0.0.1:1002", "key1")
+	assert.True(t, rl.Allow(c4, "icx_call"))
+	assert.True(t, rl.Allow(c4, "icx_call"))
+	assert.False(t, rl.Allow(c4, "icx_call"))
+
+	// updating limits resets budgets
+	rl.SetLimits(limits)
+	assert.True(t, rl.Allow(c1, "icx_call"))
+}
+
+func TestRateLimiter_SetAPIKeys(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	rl := newRateLimiter(mtr)
+	limits, err := ParseRateLimits("icx=0.001:1")
+	assert.NoError(t, err)
+	rl.SetLimits(limits)
+	rl.SetAPIKeys(strings.Split("key1, key2 ,", ","))
+
+	assert.True(t, rl.Allow(newRateLimitContext("10.0.0.1:1000", ""), "icx_call"))
+	assert.True(t, rl.Allow(newRateLimitContext("10.0.0.1:1001", "key1"), "icx_call"))
+	assert.True(t, rl.Allow(newRateLimitContext("10.0.0.1:1002", "key2"), "icx_call"))
+	assert.False(t, rl.Allow(newRateLimitContext("10.0.0.1:1003", " key2"), "icx_call"))
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -30,6 +31,8 @@ type Config struct {
 	JSONRPCDefaultChannel string
 	JSONRPCBatchLimit     int
 	WSMaxSession          int
+	RateLimit             string
+	APIKeys               string
 }
 
 type Manager struct {
@@ -47,6 +50,7 @@ type Manager struct {
 	logger                log.Logger
 	metricsHandler        echo.HandlerFunc
 	mtr                   *metric.JsonrpcMetric
+	limiter               *rateLimiter
 }
 
 func NewManager(
@@ -75,10 +79,15 @@ func NewManager(
 		logger:                logger,
 		metricsHandler:        echo.WrapHandler(metric.PrometheusExporter()),
 		mtr:                   mtr,
+		limiter:               newRateLimiter(mtr),
 	}
 	m.SetMessageDump(config.JSONRPCDump)
 	m.SetIncludeDebug(config.JSONRPCIncludeDebug)
 	m.SetRosetta(config.JSONRPCRosetta)
+	if err := m.SetRateLimit(config.RateLimit); err != nil {
+		logger.Warnf("Ignore invalid rate limit %q err=%+v", config.RateLimit, err)
+	}
+	m.SetAPIKeys(config.APIKeys)
 	return m
 }
 
@@ -179,6 +188,24 @@ func (srv *Manager) SetWSMaxSession(limit int) {
 	srv.wssm.SetMaxSession(limit)
 }
 
+// SetRateLimit sets the rate limits for each client. Refer ParseRateLimits
+// for the format.
+func (srv *Manager) SetRateLimit(spec string) error {
+	limits, err := ParseRateLimits(spec)
+	if err != nil {
+		return err
+	}
+	srv.limiter.SetLimits(limits)
+	return nil
+}
+
+// SetAPIKeys sets comma separated API keys. Clients with one of the keys
+// in HeaderKeyAPIKey get their own rate limits instead of limits of
+// the remote address.
+func (srv *Manager) SetAPIKeys(keys string) {
+	srv.limiter.SetAPIKeys(strings.Split(keys, ","))
+}
+
 func (srv *Manager) Start() error {
 	srv.logger.Infoln("starting the server")
 	// CORS middleware
@@ -211,6 +238,7 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 			ctx.Set("includeDebug", srv.IncludeDebug())
 			ctx.Set("batchLimit", srv.BatchLimit())
 			ctx.Set("rosetta", srv.Rosetta())
+			ctx.Set("rateLimiter", srv.limiter)
 			return next(ctx)
 		}
 	})
@@ -240,9 +268,11 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 
 	// group for websocket
 	ws := g.Group("")
-	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
-	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
-	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv))
+	wsl := srv.limiter.SessionLimiter()
+	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv), wsl)
+	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv), wsl)
+	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv), wsl)
+	ws.GET("/v3/:channel/txpool", srv.wssm.RunTxPoolSession, ChainInjector(srv), wsl)
 }
 
 func (srv *Manager) RegisterMetricsHandler(g *echo.Group) {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ import (
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/eventindex"
 	"github.com/icon-project/goloop/service/scoreresult"
 	"github.com/icon-project/goloop/service/trace"
 	"github.com/icon-project/goloop/service/txresult"
@@ -27,6 +29,14 @@ import (
 
 const (
 	ConfigShowPatchTransaction = false
+
+	// ConfigMaxLogsScan is the maximum number of blocks checked without
+	// the event index for a icx_getLogs request.
+	ConfigMaxLogsScan = 1000
+
+	// ConfigMaxLogsResults is the maximum number of results returned for
+	// a icx_getLogs request.
+	ConfigMaxLogsResults = 1000
 )
 
 func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
@@ -51,7 +61,10 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getVotesByHeight", getVotesByHeight)
 	mr.RegisterMethod("icx_getProofForResult", getProofForResult)
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
+	mr.RegisterMethod("icx_getProofForAccount", getProofForAccount)
+	mr.RegisterMethod("icx_getProofForStorage", getProofForStorage)
 	mr.RegisterMethod("icx_getScoreStatus", getScoreStatus)
+	mr.RegisterMethod("icx_getLogs", getLogs)
 
 	mr.RegisterMethod("btp_getNetworkInfo", getBTPNetworkInfo)
 	mr.RegisterMethod("btp_getNetworkTypeInfo", getBTPNetworkTypeInfo)
@@ -703,6 +716,66 @@ func getProofForEvents(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{
 	return proofs, nil
 }
 
+func getProofForAccount(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param ProofAccountParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	blk, err := c.GetBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := c.sm.GetProofForAccount(blk.Result(), param.Address.Address())
+	if errors.IllegalArgumentError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	} else if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	jso, err := proof.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return jso, nil
+}
+
+func getProofForStorage(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param ProofStorageParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	key, err := param.StorageKey.Bytes()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	blk, err := c.GetBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := c.sm.GetProofForStorage(blk.Result(), param.Address.Address(), key)
+	if errors.IllegalArgumentError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	} else if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	jso, err := proof.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return jso, nil
+}
+
 func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1112,20 +1185,31 @@ func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_simulateTransaction", simulateTransaction)
 
 	return mr
 }
 
+const (
+	TraceModeInvoke    = "invoke"
+	TraceModeStateDiff = "stateDiff"
+)
+
 func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
 		return nil, err
 	}
 
-	var param TransactionHashParam
+	var param TraceParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
+	switch param.Mode {
+	case "", TraceModeInvoke, TraceModeStateDiff:
+	default:
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidTraceMode(mode=%s)", param.Mode)
+	}
 
 	txInfo, err := c.bm.GetTransactionInfo(param.Hash.Bytes())
 	if errors.NotFoundError.Equals(err) {
@@ -1180,6 +1264,10 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 		Index:     txInfo.Index(),
 		Callback:  cb,
 	}
+	if param.Mode == TraceModeStateDiff {
+		cb.st = trace.NewStateDiffTracer(1)
+		ti.TraceMode = module.TraceModeStateDiff
+	}
 	canceller, err := tr2.ExecuteForTrace(ti)
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
@@ -1193,6 +1281,9 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
 				"Not enough time to get result of %x", param.Hash.Bytes())
 		case <-cb.channel:
+			if cb.st != nil {
+				return cb.stateDiffToJSON(), nil
+			}
 			return cb.invokeTraceToJSON(), nil
 		}
 	}
@@ -1216,12 +1307,7 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	}
 
 	// new block information based on the last
-	oldTS := blk.Timestamp()
-	newTS := common.UnixMicroFromTime(time.Now())
-	if newTS <= oldTS {
-		newTS = oldTS + 1
-	}
-	bi := common.NewBlockInfo(blk.Height()+1, newTS)
+	bi := nextBlockInfoOf(blk)
 
 	// execute transaction
 	rct, err := c.sm.ExecuteTransaction(
@@ -1231,6 +1317,9 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 		bi,
 	)
 	if err != nil {
+		if scoreresult.InvalidParameterError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
 	}
 	if status := rct.Status(); status != module.StatusSuccess {
@@ -1246,6 +1335,93 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	return steps, nil
 }
 
+// nextBlockInfoOf returns block information for the block following blk.
+func nextBlockInfoOf(blk module.Block) module.BlockInfo {
+	oldTS := blk.Timestamp()
+	newTS := common.UnixMicroFromTime(time.Now())
+	if newTS <= oldTS {
+		newTS = oldTS + 1
+	}
+	return common.NewBlockInfo(blk.Height()+1, newTS)
+}
+
+func simulateTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param SimulateTransactionParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	var raw struct {
+		Transaction json.RawMessage `json:"transaction"`
+	}
+	if err := json.Unmarshal(params.RawMessage(), &raw); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	withTrace := false
+	if param.Trace != "" {
+		if v, err := param.Trace.Bool(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		} else {
+			withTrace = v
+		}
+	}
+
+	blk, err := c.GetBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	bi := nextBlockInfoOf(blk)
+
+	var ti *module.TraceInfo
+	var cb *traceCallback
+	if withTrace {
+		cb = &traceCallback{
+			bt: trace.NewBalanceTracer(1, nil),
+		}
+		ti = &module.TraceInfo{
+			TraceMode: module.TraceModeBalanceChange,
+			Callback:  cb,
+		}
+	}
+
+	rct, err := c.sm.SimulateTransaction(
+		blk.Result(),
+		blk.NextValidators().Hash(),
+		raw.Transaction,
+		bi,
+		ti,
+	)
+	if err != nil {
+		if scoreresult.InvalidParameterError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+	}
+
+	res, err := rct.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	result := res.(map[string]interface{})
+	if rctex, ok := rct.(txresult.Receipt); ok && rct.Status() != module.StatusSuccess {
+		if reason := rctex.Reason(); reason != nil {
+			result["failure"] = map[string]interface{}{
+				"code":    fmt.Sprintf("%#x", int(rct.Status())),
+				"message": reason.Error(),
+			}
+		}
+	}
+	result["blockHeight"] = fmt.Sprintf("%#x", bi.Height())
+	if cb != nil {
+		result["balanceChanges"] = cb.bt.ToJSON(bi.Height())
+	}
+	return result, nil
+}
+
 type MissingTransactionInfo interface {
 	ReplaceID(height int64, id []byte) []byte
 	GetLocationOf(id []byte) (int64, int, bool)
@@ -1259,6 +1435,187 @@ func findMissingTransactionInfoOf(cid int) MissingTransactionInfo {
 	return nil
 }
 
+func (p *LogsParam) Compile() (eventindex.Filters, error) {
+	var filters eventindex.Filters
+	if len(p.Filters) > 0 {
+		if len(p.Signature) != 0 {
+			return nil, errors.IllegalArgumentError.New("both eventFilters and event is used")
+		}
+		filters = p.Filters
+	} else {
+		filters = eventindex.Filters{&p.Filter}
+	}
+	for idx, filter := range filters {
+		if filter == nil {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidFilter(idx=%d)", idx)
+		}
+		if err := filter.Compile(); err != nil {
+			return nil, err
+		}
+	}
+	return filters, nil
+}
+
+// logsCandidates returns heights in [from, to] to be checked for the filters.
+// It uses the event index for indexed heights and logs bloom of the block
+// for others. Blocks checked without the index are limited by
+// ConfigMaxLogsScan, and the last height covered by the returned heights
+// is returned along with them.
+func (c *contextWithSM) logsCandidates(
+	fs eventindex.Filters, from, to int64,
+) ([]int64, int64, error) {
+	var r *eventindex.Range
+	var candidates []int64
+	var err error
+	c.chain.DoDBTask(func(database db.Database) {
+		var idx *eventindex.Index
+		if idx, err = eventindex.New(database); err != nil {
+			return
+		}
+		if r, err = idx.Range(); err != nil || r == nil {
+			return
+		}
+		start, end := from, to
+		if start < r.First {
+			start = r.First
+		}
+		if end > r.Last {
+			end = r.Last
+		}
+		if start <= end {
+			candidates, err = idx.Candidates(fs, start, end)
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var heights []int64
+	scanned := 0
+	for h := from; h <= to; h++ {
+		if r.Contains(h) {
+			for len(candidates) > 0 && candidates[0] <= r.Last {
+				heights = append(heights, candidates[0])
+				candidates = candidates[1:]
+			}
+			h = r.Last
+			continue
+		}
+		if scanned >= ConfigMaxLogsScan {
+			return heights, h - 1, nil
+		}
+		scanned++
+		blk, err := c.bm.GetBlockByHeight(h)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, contained := fs.FilteredByLogBloom(blk.LogsBloom()); contained {
+			heights = append(heights, h)
+		}
+	}
+	return heights, to, nil
+}
+
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param LogsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	filters, err := param.Compile()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	from, err := param.From.Int64()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	if err = c.CheckBaseHeight(from); err != nil {
+		return nil, err
+	}
+	last, err := c.bm.GetLastBlock()
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	to := last.Height()
+	if param.To != "" {
+		if to, err = param.To.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if to > last.Height() {
+			return nil, jsonrpc.ErrorCodeNotFound.Errorf(
+				"NotFinalized(to=%d,last=%d)", to, last.Height())
+		}
+	}
+	if to < from {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidRange(from=%d,to=%d)", from, to)
+	}
+
+	heights, lastHeight, err := c.logsCandidates(filters, from, to)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+
+	result := &LogsResult{
+		Results: []*LogsEntry{},
+	}
+	result.LastHeight.Value = lastHeight
+	for _, h := range heights {
+		blk, err := c.bm.GetBlockByHeight(h)
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		rl, err := c.sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+		var txs module.TransactionList
+		index := int32(0)
+		for rit := rl.Iterator(); rit.Has(); rit.Next() {
+			r, err := rit.Get()
+			if err != nil {
+				return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+			}
+			es, el, err := filters.MatchEvents(r, true)
+			if err != nil {
+				return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+			}
+			if len(es) > 0 {
+				entry := &LogsEntry{
+					Hash:   blk.ID(),
+					Events: es,
+					Logs:   el,
+				}
+				entry.Height.Value = h
+				entry.Index.Value = index
+				if txs == nil {
+					if pblk, err := c.bm.GetBlockByHeight(h - 1); err == nil {
+						txs = pblk.NormalTransactions()
+					}
+				}
+				if txs != nil {
+					if tx, err := txs.Get(int(index)); err == nil {
+						entry.TxHash = tx.ID()
+					}
+				}
+				result.Results = append(result.Results, entry)
+			}
+			index++
+		}
+		if len(result.Results) >= ConfigMaxLogsResults {
+			result.LastHeight.Value = h
+			break
+		}
+	}
+	return result, nil
+}
+
 func getTraceForRosetta(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1,8 +1,12 @@
 package v3
 
 import (
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/eventindex"
 )
 
 const (
@@ -31,6 +35,8 @@ type CallParam struct {
 	DataType    string          `json:"dataType" validate:"required,call"`
 	Data        interface{}     `json:"data"`
 	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+
+	StateOverrides service.StateOverrides `json:"stateOverrides,omitempty"`
 }
 
 type AddressParam struct {
@@ -47,6 +53,11 @@ type TransactionHashParam struct {
 	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
 }
 
+type TraceParam struct {
+	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Mode string           `json:"mode,omitempty"`
+}
+
 type TransactionParamForEstimate struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -57,6 +68,28 @@ type TransactionParamForEstimate struct {
 	Nonce       jsonrpc.HexInt  `json:"nonce,omitempty" validate:"optional,t_int"`
 	DataType    string          `json:"dataType,omitempty" validate:"optional,call|deploy|message|deposit"`
 	Data        interface{}     `json:"data,omitempty"`
+
+	StateOverrides service.StateOverrides `json:"stateOverrides,omitempty"`
+}
+
+type TransactionParamForSimulate struct {
+	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
+	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
+	ToAddress   jsonrpc.Address `json:"to" validate:"required,t_addr"`
+	Value       jsonrpc.HexInt  `json:"value,omitempty" validate:"optional,t_int"`
+	StepLimit   jsonrpc.HexInt  `json:"stepLimit" validate:"required,t_int"`
+	Timestamp   jsonrpc.HexInt  `json:"timestamp" validate:"required,t_int"`
+	NetworkID   jsonrpc.HexInt  `json:"nid" validate:"required,t_int"`
+	Nonce       jsonrpc.HexInt  `json:"nonce,omitempty" validate:"optional,t_int"`
+	Signature   string          `json:"signature,omitempty" validate:"optional,t_sig"`
+	DataType    string          `json:"dataType,omitempty" validate:"optional,call|deploy|message|deposit"`
+	Data        interface{}     `json:"data,omitempty"`
+}
+
+type SimulateTransactionParam struct {
+	Transaction TransactionParamForSimulate `json:"transaction"`
+	Height      jsonrpc.HexInt              `json:"height,omitempty" validate:"optional,t_int"`
+	Trace       jsonrpc.HexBool             `json:"trace,omitempty" validate:"optional,t_bool"`
 }
 
 type TransactionParam struct {
@@ -82,12 +115,44 @@ type ProofResultParam struct {
 	Index     jsonrpc.HexInt   `json:"index" validate:"required,t_int"`
 }
 
+type ProofAccountParam struct {
+	Address jsonrpc.Address `json:"address" validate:"required,t_addr"`
+	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+}
+
+type ProofStorageParam struct {
+	Address jsonrpc.Address `json:"address" validate:"required,t_addr_score"`
+	Height  jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+	service.StorageKey
+}
+
 type ProofEventsParam struct {
 	BlockHash jsonrpc.HexBytes `json:"hash" validate:"required,t_hash"`
 	Index     jsonrpc.HexInt   `json:"index" validate:"required,t_int"`
 	Events    []jsonrpc.HexInt `json:"events" validate:"gt=0,dive,t_int"`
 }
 
+type LogsParam struct {
+	eventindex.Filter
+	From    jsonrpc.HexInt     `json:"from" validate:"required,t_int"`
+	To      jsonrpc.HexInt     `json:"to,omitempty" validate:"optional,t_int"`
+	Filters eventindex.Filters `json:"eventFilters,omitempty"`
+}
+
+type LogsEntry struct {
+	Hash   common.HexBytes   `json:"hash"`
+	Height common.HexInt64   `json:"height"`
+	Index  common.HexInt32   `json:"index"`
+	TxHash common.HexBytes   `json:"txHash,omitempty"`
+	Events []common.HexInt32 `json:"events"`
+	Logs   []module.EventLog `json:"logs"`
+}
+
+type LogsResult struct {
+	Results    []*LogsEntry    `json:"results"`
+	LastHeight common.HexInt64 `json:"lastHeight"`
+}
+
 type RosettaTraceParam struct {
 	Tx     jsonrpc.HexBytes `json:"tx,omitempty" validate:"optional,t_rhash"`
 	Block  jsonrpc.HexBytes `json:"block,omitempty" validate:"optional,t_hash"`
@@ -19,6 +19,7 @@ type traceCallback struct {
 	ts      time.Time
 	channel chan interface{}
 	bt      *trace.BalanceTracer
+	st      *trace.StateDiffTracer
 }
 
 type traceLog struct {
@@ -56,6 +57,22 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 	result := map[string]interface{}{
 		"logs": t.logs,
 	}
+	t.fillStatus(result)
+	return result
+}
+
+func (t *traceCallback) stateDiffToJSON() interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := map[string]interface{}{
+		"stateDiffs": t.st.ToJSON(),
+	}
+	t.fillStatus(result)
+	return result
+}
+
+func (t *traceCallback) fillStatus(result map[string]interface{}) {
 	if t.last == nil {
 		result["status"] = "0x1"
 	} else {
@@ -66,7 +83,6 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 			"message": t.last.Error(),
 		}
 	}
-	return result
 }
 
 func (t *traceCallback) balanceChangeToJSON(blk module.Block) interface{} {
@@ -94,6 +110,11 @@ func (t *traceCallback) OnTransactionStart(txIndex int, txHash []byte, isBlockTx
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionStart(txIndex, txHash, isBlockTx)
 	}
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnTransactionStart(txIndex, txHash, isBlockTx)
+	}
 	return nil
 }
 
@@ -105,6 +126,9 @@ func (t *traceCallback) OnTransactionReset() error {
 	if t.bt != nil {
 		return t.bt.OnTransactionReset()
 	}
+	if t.st != nil {
+		return t.st.OnTransactionReset()
+	}
 	return nil
 }
 
@@ -114,6 +138,11 @@ func (t *traceCallback) OnTransactionEnd(txIndex int, txHash []byte) error {
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionEnd(txIndex, txHash)
 	}
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnTransactionEnd(txIndex, txHash)
+	}
 	return nil
 }
 
@@ -123,6 +152,11 @@ func (t *traceCallback) OnFrameEnter() error {
 		defer t.lock.Unlock()
 		return t.bt.OnFrameEnter()
 	}
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnFrameEnter()
+	}
 	return nil
 }
 
@@ -132,6 +166,11 @@ func (t *traceCallback) OnFrameExit(success bool) error {
 		defer t.lock.Unlock()
 		return t.bt.OnFrameExit(success)
 	}
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnFrameExit(success)
+	}
 	return nil
 }
 
@@ -143,3 +182,14 @@ func (t *traceCallback) OnBalanceChange(opType module.OpType, from, to module.Ad
 	}
 	return nil
 }
+
+func (t *traceCallback) OnStateChange(
+	ct module.StateChangeType, addr module.Address, key, oldValue, newValue []byte,
+) error {
+	if t.st != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.st.OnStateChange(ct, addr, key, oldValue, newValue)
+	}
+	return nil
+}
@@ -86,3 +86,73 @@ func TestTransactionParamValidator(t *testing.T) {
 		assert.Fail(t, "validate fail", err.Error())
 	}
 }
+
+func TestSimulateTransactionParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	cases := []struct {
+		name  string
+		param string
+		valid bool
+	}{
+		{
+			"unsigned",
+			`{
+				"transaction": {
+					"version": "0x3",
+					"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+					"to": "hx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+					"value": "0x11",
+					"stepLimit": "0x12345",
+					"timestamp": "0x563a6cf330136",
+					"nid": "0x3"
+				},
+				"trace": "0x1"
+			}`,
+			true,
+		},
+		{
+			"no stepLimit",
+			`{
+				"transaction": {
+					"version": "0x3",
+					"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+					"to": "hx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+					"timestamp": "0x563a6cf330136",
+					"nid": "0x3"
+				}
+			}`,
+			false,
+		},
+		{
+			"invalid trace",
+			`{
+				"transaction": {
+					"version": "0x3",
+					"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+					"to": "hx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+					"stepLimit": "0x12345",
+					"timestamp": "0x563a6cf330136",
+					"nid": "0x3"
+				},
+				"height": "0x10",
+				"trace": "true"
+			}`,
+			false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var param SimulateTransactionParam
+			err := json.Unmarshal([]byte(c.param), &param)
+			assert.NoError(t, err)
+			err = validator.Validate(&param)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
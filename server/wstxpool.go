@@ -0,0 +1,162 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	TxPoolNotificationAdd  = "add"
+	TxPoolNotificationDrop = "drop"
+
+	txPoolNotificationBuffer = 1024
+)
+
+type TxPoolRequest struct {
+	From     *common.Address `json:"from,omitempty"`
+	To       *common.Address `json:"to,omitempty"`
+	DataType string          `json:"dataType,omitempty"`
+}
+
+// TxPoolNotification notifies a transaction accepted into the pool, or
+// a transaction dropped from the pool with the reason.
+type TxPoolNotification struct {
+	Type        string          `json:"type"`
+	Hash        common.HexBytes `json:"hash"`
+	Transaction interface{}     `json:"transaction,omitempty"`
+	Code        *int            `json:"code,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+}
+
+func (r *TxPoolRequest) match(tx module.Transaction, jso interface{}) bool {
+	if r.From != nil && !r.From.Equal(tx.From()) {
+		return false
+	}
+	if r.To != nil {
+		txTo, ok := tx.(interface{ To() module.Address })
+		if !ok || !r.To.Equal(txTo.To()) {
+			return false
+		}
+	}
+	if len(r.DataType) > 0 {
+		m, ok := jso.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if dt, _ := m["dataType"].(string); dt != r.DataType {
+			return false
+		}
+	}
+	return true
+}
+
+// txPoolWatcher queues notifications for the session. It never blocks,
+// and it closes the channel on overflow.
+type txPoolWatcher struct {
+	req  *TxPoolRequest
+	ch   chan *TxPoolNotification
+	lock sync.Mutex
+	lag  bool
+}
+
+func (w *txPoolWatcher) push(n *TxPoolNotification) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.lag {
+		return
+	}
+	select {
+	case w.ch <- n:
+	default:
+		w.lag = true
+		close(w.ch)
+	}
+}
+
+func (w *txPoolWatcher) OnTransactionAdd(tx module.Transaction) {
+	jso, err := tx.ToJSON(module.JSONVersionLast)
+	if err != nil || !w.req.match(tx, jso) {
+		return
+	}
+	w.push(&TxPoolNotification{
+		Type:        TxPoolNotificationAdd,
+		Hash:        tx.ID(),
+		Transaction: jso,
+	})
+}
+
+func (w *txPoolWatcher) OnTransactionDrop(tx module.Transaction, reason error) {
+	var jso interface{}
+	if len(w.req.DataType) > 0 {
+		var err error
+		if jso, err = tx.ToJSON(module.JSONVersionLast); err != nil {
+			return
+		}
+	}
+	if !w.req.match(tx, jso) {
+		return
+	}
+	n := &TxPoolNotification{
+		Type: TxPoolNotificationDrop,
+		Hash: tx.ID(),
+	}
+	if reason != nil {
+		code := int(errors.CodeOf(reason))
+		n.Code = &code
+		n.Reason = reason.Error()
+	}
+	w.push(n)
+}
+
+func (wm *wsSessionManager) RunTxPoolSession(ctx echo.Context) error {
+	var tr TxPoolRequest
+	wss, err := wm.initSession(ctx, &tr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	sm := wss.chain.ServiceManager()
+	if sm == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+
+	w := &txPoolWatcher{
+		req: &tr,
+		ch:  make(chan *TxPoolNotification, txPoolNotificationBuffer),
+	}
+	cancel := sm.WatchTransactionPool(w)
+	defer cancel()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case n, ok := <-w.ch:
+			if !ok {
+				_ = wss.response(int(jsonrpc.ErrorLackOfResource), "too many notifications")
+				err = errors.New("TooManyNotifications")
+				break loop
+			}
+			if err = wss.WriteJSON(n); err != nil {
+				wm.logger.Infof("fail to write json TxPoolNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
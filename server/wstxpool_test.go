@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+type testTxPoolServiceManager struct {
+	module.ServiceManager
+	watchers chan module.TransactionPoolWatcher
+	canceled chan bool
+}
+
+func (sm *testTxPoolServiceManager) WatchTransactionPool(w module.TransactionPoolWatcher) func() {
+	sm.watchers <- w
+	return func() {
+		sm.canceled <- true
+	}
+}
+
+type testPoolTransaction struct {
+	module.Transaction
+	id       []byte
+	from     module.Address
+	to       module.Address
+	dataType string
+}
+
+func (tx *testPoolTransaction) ID() []byte {
+	return tx.id
+}
+
+func (tx *testPoolTransaction) From() module.Address {
+	return tx.from
+}
+
+func (tx *testPoolTransaction) To() module.Address {
+	return tx.to
+}
+
+func (tx *testPoolTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
+	jso := map[string]interface{}{
+		"from":   tx.from,
+		"to":     tx.to,
+		"txHash": common.HexBytes(tx.id),
+	}
+	if len(tx.dataType) > 0 {
+		jso["dataType"] = tx.dataType
+	}
+	return jso, nil
+}
+
+func TestWSSessionManager_RunTxPoolSession(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000003")
+
+	var conn *testWebSocketConn
+	t1 := make(chan string, 1)
+	upgrader := newTestWebsocketUpgrader(func(ctx echo.Context, c *testWebSocketConn) {
+		conn = c
+		t1 <- "NEW"
+	})
+	wm := newWSSessionManagerWithUpgrader(logger, 2, upgrader)
+	sm := &testTxPoolServiceManager{
+		watchers: make(chan module.TransactionPoolWatcher, 1),
+		canceled: make(chan bool, 1),
+	}
+	chain := &testChain{sm: sm}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- wm.RunTxPoolSession(newTestContext(chain))
+	}()
+	assert.Equal(t, "NEW", <-t1)
+
+	err := conn.clientWriteJSON(map[string]interface{}{
+		"from":     addr1,
+		"dataType": "call",
+	})
+	assert.NoError(t, err)
+	w := <-sm.watchers
+
+	bs, err := conn.clientRead()
+	assert.NoError(t, err)
+	var res WSResponse
+	assert.NoError(t, json.Unmarshal(bs, &res))
+	assert.Equal(t, 0, res.Code)
+
+	// filtered out by from and dataType
+	w.OnTransactionAdd(&testPoolTransaction{id: []byte{1}, from: addr2, to: score, dataType: "call"})
+	w.OnTransactionAdd(&testPoolTransaction{id: []byte{2}, from: addr1, to: score})
+	w.OnTransactionAdd(&testPoolTransaction{id: []byte{3}, from: addr1, to: score, dataType: "call"})
+	w.OnTransactionDrop(&testPoolTransaction{id: []byte{4}, from: addr2, to: score, dataType: "call"}, nil)
+	w.OnTransactionDrop(&testPoolTransaction{id: []byte{5}, from: addr1, to: score, dataType: "call"},
+		errors.InvalidStateError.New("Expired"))
+
+	var n TxPoolNotification
+	bs, err = conn.clientRead()
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(bs, &n))
+	assert.Equal(t, TxPoolNotificationAdd, n.Type)
+	assert.Equal(t, common.HexBytes{3}, n.Hash)
+	assert.NotNil(t, n.Transaction)
+
+	n = TxPoolNotification{}
+	bs, err = conn.clientRead()
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(bs, &n))
+	assert.Equal(t, TxPoolNotificationDrop, n.Type)
+	assert.Equal(t, common.HexBytes{5}, n.Hash)
+	assert.Equal(t, int(errors.InvalidStateError), *n.Code)
+	assert.Contains(t, n.Reason, "Expired")
+
+	wm.StopAllSessions()
+	assert.NoError(t, <-done)
+	assert.True(t, <-sm.canceled)
+}
+
+func TestTxPoolWatcher_Overflow(t *testing.T) {
+	w := &txPoolWatcher{
+		req: &TxPoolRequest{},
+		ch:  make(chan *TxPoolNotification, 2),
+	}
+	for i := 0; i < 3; i++ {
+		w.OnTransactionDrop(&testPoolTransaction{id: []byte{byte(i)}}, nil)
+	}
+	assert.True(t, w.lag)
+	_, ok := <-w.ch
+	assert.True(t, ok)
+	_, ok = <-w.ch
+	assert.True(t, ok)
+	_, ok = <-w.ch
+	assert.False(t, ok)
+}
@@ -137,6 +137,24 @@ func (c *context) GetTraceLogger(phase module.ExecutionPhase) *trace.Logger {
 	return c.tlogDummy
 }
 
+// GetAccountState returns the account state of the world state. In
+// module.TraceModeStateDiff, the returned account state reports changes
+// to the trace logger.
+func (c *context) GetAccountState(id []byte) state.AccountState {
+	as := c.WorldContext.GetAccountState(id)
+	if c.ti == nil || c.ti.TraceMode != module.TraceModeStateDiff {
+		return as
+	}
+	phase := module.EPhaseExecutionEnd
+	if c.TransactionInfo() != nil {
+		phase = module.EPhaseTransaction
+	}
+	if tlog := c.GetTraceLogger(phase); tlog.TraceMode() == module.TraceModeStateDiff {
+		return newTracingAccountState(as, id, tlog)
+	}
+	return as
+}
+
 func (c *context) TraceInfo() *module.TraceInfo {
 	return c.ti
 }
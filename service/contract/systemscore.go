@@ -443,24 +443,28 @@ func Invoke(score SystemScore, method string, paramObj *codec.TypedObj) (status
 		params = ps.([]interface{})
 	}
 
-	if len(params) != mType.NumIn() {
+	if len(params) > mType.NumIn() {
 		return scoreresult.IllegalFormatError.Errorf(
-			"NotEnoughParameter(exp=%d,real=%d)",
+			"TooManyParameters(exp=%d,real=%d)",
 			mType.NumIn(), len(params)), nil, steps
 	}
 
-	objects := make([]reflect.Value, len(params))
-	for i, p := range params {
+	// Parameters added in later revisions are not given with the API of
+	// earlier revisions, so they are passed as zero values.
+	objects := make([]reflect.Value, mType.NumIn())
+	for i := range objects {
 		oType := mType.In(i)
 		oValue := reflect.New(oType).Elem()
-		if err := AssignParameter(oValue, p); err != nil {
-			return errors.Wrapf(
-					err,
-					"InCompatibleType(to=%s,with=%T)",
-					oType,
-					p,
-				),
-				nil, steps
+		if i < len(params) {
+			if err := AssignParameter(oValue, params[i]); err != nil {
+				return errors.Wrapf(
+						err,
+						"InCompatibleType(to=%s,with=%T)",
+						oType,
+						params[i],
+					),
+					nil, steps
+			}
 		}
 		objects[i] = oValue
 	}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/service/scoreapi"
 )
 
 func newHexInt(s string, base int) *common.HexInt {
@@ -65,3 +66,46 @@ func TestAssignHexInt(t *testing.T) {
 		})
 	}
 }
+
+type testSystemScore struct {
+	name string
+	dsa  string
+}
+
+func (s *testSystemScore) Install(param []byte) error { return nil }
+func (s *testSystemScore) Update(param []byte) error  { return nil }
+func (s *testSystemScore) GetAPI() *scoreapi.Info     { return nil }
+
+func (s *testSystemScore) Ex_setKey(name string, dsa string) error {
+	s.name, s.dsa = name, dsa
+	return nil
+}
+
+func TestInvoke(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []interface{}
+		wantErr bool
+		wantDSA string
+	}{
+		{"AllParams", []interface{}{"key", "eddsa/ed25519"}, false, "eddsa/ed25519"},
+		{"MissingParam", []interface{}{"key"}, false, ""},
+		{"TooManyParams", []interface{}{"key", "eddsa/ed25519", "extra"}, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := new(testSystemScore)
+			paramObj, err := common.EncodeAny(tt.params)
+			if err != nil {
+				t.Fatalf("EncodeAny() error = %v", err)
+			}
+			status, _, _ := Invoke(score, "setKey", paramObj)
+			if (status != nil) != tt.wantErr {
+				t.Fatalf("Invoke() status = %v, wantErr %v", status, tt.wantErr)
+			}
+			if !tt.wantErr && (score.name != "key" || score.dsa != tt.wantDSA) {
+				t.Errorf("Invoke() name = %q dsa = %q, want dsa %q", score.name, score.dsa, tt.wantDSA)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contract
+
+import (
+	"bytes"
+	"math/big"
+	"strconv"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/trace"
+)
+
+const (
+	codeKeyCurrent = "current"
+	codeKeyNext    = "next"
+
+	statusKeyDisabled = "disabled"
+	statusKeyBlocked  = "blocked"
+	statusKeyContract = "contract"
+)
+
+// tracingAccountState reports changes of the account state to the trace
+// logger in module.TraceModeStateDiff.
+type tracingAccountState struct {
+	state.AccountState
+	id   []byte
+	tlog *trace.Logger
+}
+
+func newTracingAccountState(as state.AccountState, id []byte, tlog *trace.Logger) state.AccountState {
+	return &tracingAccountState{
+		AccountState: as,
+		id:           id,
+		tlog:         tlog,
+	}
+}
+
+func (s *tracingAccountState) address() module.Address {
+	if s.AccountState.IsContract() {
+		return common.NewContractAddress(s.id)
+	}
+	return common.NewAccountAddress(s.id)
+}
+
+func codeHashOf(cs state.ContractSnapshot) []byte {
+	if cs == nil {
+		return nil
+	}
+	return cs.CodeHash()
+}
+
+func contractStatusOf(cs state.ContractSnapshot) []byte {
+	if cs == nil {
+		return nil
+	}
+	return []byte(cs.Status().String())
+}
+
+func (s *tracingAccountState) onChange(ct module.StateChangeType, key string, oldValue, newValue []byte) {
+	if bytes.Equal(oldValue, newValue) {
+		return
+	}
+	s.tlog.OnStateChange(ct, s.address(), []byte(key), oldValue, newValue)
+}
+
+func (s *tracingAccountState) onStatusChange(key string, old, new bool) {
+	if old != new {
+		s.onChange(module.StateChangeStatus, key,
+			[]byte(strconv.FormatBool(old)), []byte(strconv.FormatBool(new)))
+	}
+}
+
+func (s *tracingAccountState) SetBalance(v *big.Int) {
+	old := s.AccountState.GetBalance()
+	s.AccountState.SetBalance(v)
+	if old.Cmp(v) != 0 {
+		s.tlog.OnStateChange(module.StateChangeBalance, s.address(), nil,
+			intconv.BigIntToBytes(old), intconv.BigIntToBytes(v))
+	}
+}
+
+func (s *tracingAccountState) SetValue(k, v []byte) ([]byte, error) {
+	old, err := s.AccountState.SetValue(k, v)
+	if err == nil && !bytes.Equal(old, v) {
+		s.tlog.OnStateChange(module.StateChangeStorage, s.address(), k, old, v)
+	}
+	return old, err
+}
+
+func (s *tracingAccountState) DeleteValue(k []byte) ([]byte, error) {
+	old, err := s.AccountState.DeleteValue(k)
+	if err == nil && old != nil {
+		s.tlog.OnStateChange(module.StateChangeStorage, s.address(), k, old, nil)
+	}
+	return old, err
+}
+
+func (s *tracingAccountState) DeployContract(
+	code []byte, eeType state.EEType, contentType string, params []byte, txHash []byte,
+) ([]byte, error) {
+	old := codeHashOf(s.AccountState.NextContract())
+	ret, err := s.AccountState.DeployContract(code, eeType, contentType, params, txHash)
+	if err == nil {
+		s.onChange(module.StateChangeCode, codeKeyNext, old, codeHashOf(s.AccountState.NextContract()))
+	}
+	return ret, err
+}
+
+func (s *tracingAccountState) onContractChange(f func() error) error {
+	oldHash := codeHashOf(s.AccountState.ActiveContract())
+	oldStatus := contractStatusOf(s.AccountState.Contract())
+	if err := f(); err != nil {
+		return err
+	}
+	s.onChange(module.StateChangeCode, codeKeyCurrent, oldHash,
+		codeHashOf(s.AccountState.ActiveContract()))
+	s.onChange(module.StateChangeStatus, statusKeyContract, oldStatus,
+		contractStatusOf(s.AccountState.Contract()))
+	return nil
+}
+
+func (s *tracingAccountState) ActivateNextContract() error {
+	return s.onContractChange(s.AccountState.ActivateNextContract)
+}
+
+func (s *tracingAccountState) AcceptContract(txHash []byte, auditTxHash []byte) error {
+	return s.onContractChange(func() error {
+		return s.AccountState.AcceptContract(txHash, auditTxHash)
+	})
+}
+
+func (s *tracingAccountState) RejectContract(txHash []byte, auditTxHash []byte) error {
+	return s.onContractChange(func() error {
+		return s.AccountState.RejectContract(txHash, auditTxHash)
+	})
+}
+
+func (s *tracingAccountState) SetDisable(b bool) {
+	old := s.AccountState.IsDisabled()
+	s.AccountState.SetDisable(b)
+	s.onStatusChange(statusKeyDisabled, old, s.AccountState.IsDisabled())
+}
+
+func (s *tracingAccountState) SetBlock(b bool) {
+	old := s.AccountState.IsBlocked()
+	s.AccountState.SetBlock(b)
+	s.onStatusChange(statusKeyBlocked, old, s.AccountState.IsBlocked())
+}
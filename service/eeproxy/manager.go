@@ -7,6 +7,7 @@ import (
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/ipc"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/metric"
 )
 
 type RequestPriority int
@@ -19,6 +20,17 @@ const (
 	numberOfPriorities = 2
 )
 
+func (pr RequestPriority) String() string {
+	switch pr {
+	case ForTransaction:
+		return "transaction"
+	case ForQuery:
+		return "query"
+	default:
+		return "unknown"
+	}
+}
+
 const (
 	errorBase                  = errors.CodeService + 300
 	ScaleDownError errors.Code = iota + errorBase
@@ -97,6 +109,12 @@ type executorManager struct {
 	executorStates [numberOfPriorities]executorState
 
 	log log.Logger
+	mtr *metric.EEProxyMetric
+}
+
+func (em *executorManager) onExecutorStateInLock(pr RequestPriority) {
+	es := &em.executorStates[pr]
+	em.mtr.OnExecutorState(pr.String(), es.assigned, es.waiting)
 }
 
 func (em *executorManager) onReady(p *proxy) error {
@@ -210,6 +228,7 @@ func (em *executorManager) onRelease(pr RequestPriority, ex *Executor) {
 	defer em.lock.Unlock()
 
 	em.executorStates[pr].assigned -= 1
+	em.onExecutorStateInLock(pr)
 }
 
 func (em *executorManager) GetExecutor(pr RequestPriority) *Executor {
@@ -218,12 +237,14 @@ func (em *executorManager) GetExecutor(pr RequestPriority) *Executor {
 
 	es := &em.executorStates[pr]
 	es.waiting += 1
+	em.onExecutorStateInLock(pr)
 	for {
 		if es.assigned < es.limit {
 			e := em.createExecutorInLock(pr)
 			if e != nil {
 				es.assigned += 1
 				es.waiting -= 1
+				em.onExecutorStateInLock(pr)
 				return e
 			}
 		}
@@ -297,6 +318,7 @@ func NewManager(net, addr string, l log.Logger, engines ...Engine) (Manager, err
 	srv.SetHandler(em)
 	em.server = srv
 	em.log = l.WithFields(log.Fields{log.FieldKeyModule: "EEP"})
+	em.mtr = metric.NewEEProxyMetric()
 
 	for i := 0; i < len(em.executorStates); i++ {
 		em.executorStates[i].waiter = sync.NewCond(&em.lock)
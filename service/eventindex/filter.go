@@ -0,0 +1,220 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventindex
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type Filters []*Filter
+
+type Filter struct {
+	Addr       *common.Address `json:"addr,omitempty"`
+	Signature  string          `json:"event"`
+	Indexed    []*string       `json:"indexed,omitempty"`
+	Data       []*string       `json:"data,omitempty"`
+	indexedBSs [][]byte
+	dataBSs    [][]byte
+	numOfArgs  int
+	lb         module.LogsBloom
+}
+
+// FilteredByLogBloom returns applicable event filters.
+// If there is no event filters, then it returns false along with filters.
+func (fs Filters) FilteredByLogBloom(lb module.LogsBloom) (Filters, bool) {
+	filters := make([]*Filter, len(fs))
+	contained := false
+	for idx, filter := range fs {
+		if filter == nil {
+			continue
+		}
+		if lb.Contain(filter.lb) {
+			filters[idx] = filter
+			contained = true
+		}
+	}
+	return filters, contained
+}
+
+func (fs Filters) MatchEvents(r module.Receipt, includeLogs bool) ([]common.HexInt32, []module.EventLog, error) {
+	var indexes []common.HexInt32
+	var logs []module.EventLog
+	if err := fs.FilterEvents(r, func(fi, idx int, log module.EventLog) {
+		indexes = append(indexes, common.HexInt32{Value: int32(idx)})
+		if includeLogs {
+			logs = append(logs, log)
+		}
+	}); err != nil {
+		return nil, nil, err
+	} else {
+		return indexes, logs, nil
+	}
+}
+
+// FilterEvents calls v for each event log in the receipt matching one of
+// the filters. fi is the index of the first matching filter and idx is
+// the index of the event log in the receipt.
+func (fs Filters) FilterEvents(r module.Receipt, v func(fi, idx int, log module.EventLog)) error {
+	filters, contained := fs.FilteredByLogBloom(r.LogsBloom())
+	if !contained {
+		return nil
+	}
+	for it, idx := r.EventLogIterator(), 0; it.Has(); _, idx = it.Next(), idx+1 {
+		el, err := it.Get()
+		if err != nil {
+			return err
+		}
+		for fi, f := range filters {
+			if f == nil {
+				continue
+			}
+			if f.MatchLog(el) {
+				v(fi, idx, el)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Filter) Compile() error {
+	lb := txresult.NewLogsBloom(nil)
+	if f.Addr != nil {
+		lb.AddAddressOfLog(f.Addr)
+	}
+	f.numOfArgs = len(f.Indexed) + len(f.Data)
+	name, pts := txresult.DecomposeEventSignature(f.Signature)
+	if len(name) == 0 || pts == nil || len(pts) < f.numOfArgs {
+		return errors.NewBase(errors.IllegalArgumentError, "bad event signature")
+	}
+	for idx, pt := range pts {
+		dt := scoreapi.DataTypeOf(pt)
+		if !dt.UsableForEvent() {
+			return errors.IllegalArgumentError.Errorf("InvalidParameterType(idx=%d,type=%s)", idx, pt)
+		}
+	}
+	lb.AddIndexedOfLog(0, []byte(f.Signature))
+	idx := 0
+	f.indexedBSs = make([][]byte, len(f.Indexed))
+	for i, arg := range f.Indexed {
+		if arg != nil {
+			bs, err := txresult.EventDataStringToBytesByType(pts[idx], string(*arg))
+			if err != nil {
+				return errors.NewBase(errors.IllegalArgumentError, "bad event data")
+			}
+			lb.AddIndexedOfLog(i+1, bs)
+			f.indexedBSs[i] = bs
+		}
+		idx++
+	}
+	f.dataBSs = make([][]byte, len(f.Data))
+	for i, arg := range f.Data {
+		if arg != nil {
+			bs, err := txresult.EventDataStringToBytesByType(pts[idx], string(*arg))
+			if err != nil {
+				return errors.NewBase(errors.IllegalArgumentError, "bad event data")
+			}
+			f.dataBSs[i] = bs
+		}
+		idx++
+	}
+	f.lb = lb
+	return nil
+}
+
+// LogsBloom returns the logs bloom built by Compile.
+func (f *Filter) LogsBloom() module.LogsBloom {
+	return f.lb
+}
+
+// bytesEqual check equality of byte slice.
+// But it doesn't assume nil as empty bytes.
+func bytesEqual(b1 []byte, b2 []byte) bool {
+	if b1 == nil && b2 == nil {
+		return true
+	}
+	if b1 == nil || b2 == nil {
+		return false
+	}
+	return bytes.Equal(b1, b2)
+}
+
+func (f *Filter) MatchEvents(r module.Receipt, includeLogs bool) ([]common.HexInt32, []module.EventLog, error) {
+	var indexes []common.HexInt32
+	var logs []module.EventLog
+	if err := f.filterEvents(r, func(idx int, log module.EventLog) {
+		indexes = append(indexes, common.HexInt32{Value: int32(idx)})
+		if includeLogs {
+			logs = append(logs, log)
+		}
+	}); err != nil {
+		return nil, nil, err
+	}
+	return indexes, logs, nil
+}
+
+func (f *Filter) MatchLog(el module.EventLog) bool {
+	if bytes.Equal([]byte(f.Signature), el.Indexed()[0]) {
+		if f.Addr != nil && !el.Address().Equal(f.Addr) {
+			return false
+		}
+		if f.numOfArgs > 0 {
+			if len(el.Indexed()) <= len(f.indexedBSs) {
+				return false
+			}
+			if len(el.Data()) < len(f.dataBSs) {
+				return false
+			}
+
+			for i, arg := range f.indexedBSs {
+				if arg != nil && !bytesEqual(arg, el.Indexed()[i+1]) {
+					return false
+				}
+			}
+			for i, arg := range f.dataBSs {
+				if arg != nil && !bytesEqual(arg, el.Data()[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	} else {
+		return false
+	}
+}
+
+func (f *Filter) filterEvents(r module.Receipt, v func(idx int, log module.EventLog)) error {
+	if r.LogsBloom().Contain(f.lb) {
+		for it, idx := r.EventLogIterator(), 0; it.Has(); _, idx = it.Next(), idx+1 {
+			el, err := it.Get()
+			if err != nil {
+				return err
+			}
+
+			if f.MatchLog(el) {
+				v(idx, el)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventindex
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+// PostingChunkSize is the number of heights covered by one posting list
+// entry in the database.
+const PostingChunkSize = 1024
+
+const (
+	prefixLogsBloom = 'b'
+	prefixAddress   = 'a'
+	prefixSignature = 's'
+)
+
+var keyRange = []byte("range")
+
+// Range is the range of heights [First, Last] covered by the index.
+type Range struct {
+	First int64
+	Last  int64
+}
+
+func (r *Range) Contains(height int64) bool {
+	return r != nil && height >= r.First && height <= r.Last
+}
+
+// Index is the on-disk index of event logs in the finalized blocks.
+// It keeps logs bloom of each block and posting lists of heights for
+// score addresses and event signatures of the event logs.
+type Index struct {
+	bk *db.CodedBucket
+}
+
+func New(dbase db.Database) (*Index, error) {
+	bk, err := db.NewCodedBucket(dbase, db.EventIndex, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Index{bk: bk}, nil
+}
+
+// Range returns the range of indexed heights. It returns nil if nothing
+// has been indexed.
+func (idx *Index) Range() (*Range, error) {
+	r := new(Range)
+	if err := idx.bk.Get(db.Raw(keyRange), r); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+func heightKey(prefix byte, height int64) []byte {
+	key := make([]byte, 9)
+	key[0] = prefix
+	binary.BigEndian.PutUint64(key[1:], uint64(height))
+	return key
+}
+
+func postingKey(prefix byte, term []byte, chunk int64) []byte {
+	key := make([]byte, 1+len(term)+8)
+	key[0] = prefix
+	copy(key[1:], term)
+	binary.BigEndian.PutUint64(key[1+len(term):], uint64(chunk))
+	return key
+}
+
+func signatureTerm(sig []byte) []byte {
+	return crypto.SHA3Sum256(sig)
+}
+
+func (idx *Index) getPosting(key []byte) ([]int64, error) {
+	var heights []int64
+	if err := idx.bk.Get(db.Raw(key), &heights); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return heights, nil
+}
+
+func (idx *Index) addPosting(prefix byte, term []byte, height int64) error {
+	key := postingKey(prefix, term, height/PostingChunkSize)
+	heights, err := idx.getPosting(key)
+	if err != nil {
+		return err
+	}
+	if n := len(heights); n > 0 && heights[n-1] == height {
+		return nil
+	}
+	return idx.bk.Set(db.Raw(key), append(heights, height))
+}
+
+// Add indexes event logs of the block at the height. lb is the logs bloom
+// of the block and rl is the list of receipts of the block.
+// Heights should be added in order without gaps. Adding an already indexed
+// height does nothing, and adding a height which doesn't follow the last
+// indexed height returns InvalidStateError, so the caller should add the
+// missing heights first.
+func (idx *Index) Add(height int64, lb module.LogsBloom, rl module.ReceiptList) error {
+	r, err := idx.Range()
+	if err != nil {
+		return err
+	}
+	if r.Contains(height) {
+		return nil
+	}
+	if r != nil && r.Last+1 != height {
+		return errors.InvalidStateError.Errorf(
+			"NonContiguousHeight(height=%d,first=%d,last=%d)",
+			height, r.First, r.Last)
+	}
+	addrs := make(map[string]bool)
+	sigs := make(map[string]bool)
+	for it := rl.Iterator(); it.Has(); it.Next() {
+		r, err := it.Get()
+		if err != nil {
+			return err
+		}
+		for eit := r.EventLogIterator(); eit.Has(); eit.Next() {
+			el, err := eit.Get()
+			if err != nil {
+				return err
+			}
+			addrs[string(el.Address().Bytes())] = true
+			if indexed := el.Indexed(); len(indexed) > 0 {
+				sigs[string(indexed[0])] = true
+			}
+		}
+	}
+	for addr := range addrs {
+		if err := idx.addPosting(prefixAddress, []byte(addr), height); err != nil {
+			return err
+		}
+	}
+	for sig := range sigs {
+		if err := idx.addPosting(prefixSignature, signatureTerm([]byte(sig)), height); err != nil {
+			return err
+		}
+	}
+	if err := idx.bk.Set(db.Raw(heightKey(prefixLogsBloom, height)), db.Raw(lb.CompressedBytes())); err != nil {
+		return err
+	}
+
+	if r == nil {
+		r = &Range{First: height}
+	}
+	r.Last = height
+	return idx.bk.Set(db.Raw(keyRange), r)
+}
+
+// LogsBloomOf returns indexed logs bloom of the block at the height.
+func (idx *Index) LogsBloomOf(height int64) (module.LogsBloom, error) {
+	bs, err := idx.bk.GetBytes(db.Raw(heightKey(prefixLogsBloom, height)))
+	if err != nil {
+		return nil, err
+	}
+	return txresult.NewLogsBloomFromCompressed(bs), nil
+}
+
+func (idx *Index) postingOf(prefix byte, term []byte, from, to int64) ([]int64, error) {
+	var heights []int64
+	for chunk := from / PostingChunkSize; chunk <= to/PostingChunkSize; chunk++ {
+		p, err := idx.getPosting(postingKey(prefix, term, chunk))
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range p {
+			if h >= from && h <= to {
+				heights = append(heights, h)
+			}
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+	return heights, nil
+}
+
+func intersect(h1, h2 []int64) []int64 {
+	var res []int64
+	for i, j := 0, 0; i < len(h1) && j < len(h2); {
+		switch {
+		case h1[i] < h2[j]:
+			i++
+		case h1[i] > h2[j]:
+			j++
+		default:
+			if n := len(res); n == 0 || res[n-1] != h1[i] {
+				res = append(res, h1[i])
+			}
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+// Candidates returns heights in [from, to] which may have event logs
+// matching one of the compiled filters. Heights are checked against
+// postings first, then logs bloom of the block. The caller should check
+// the receipts of the returned heights with the filters.
+func (idx *Index) Candidates(fs Filters, from, to int64) ([]int64, error) {
+	hs := make(map[int64]bool)
+	for _, f := range fs {
+		if f == nil {
+			continue
+		}
+		heights, err := idx.postingOf(prefixSignature, signatureTerm([]byte(f.Signature)), from, to)
+		if err != nil {
+			return nil, err
+		}
+		if f.Addr != nil && len(heights) > 0 {
+			addrHeights, err := idx.postingOf(prefixAddress, f.Addr.Bytes(), from, to)
+			if err != nil {
+				return nil, err
+			}
+			heights = intersect(heights, addrHeights)
+		}
+		for _, h := range heights {
+			hs[h] = true
+		}
+	}
+	candidates := make([]int64, 0, len(hs))
+	for h := range hs {
+		lb, err := idx.LogsBloomOf(h)
+		if err != nil {
+			return nil, err
+		}
+		if _, contained := fs.FilteredByLogBloom(lb); contained {
+			candidates = append(candidates, h)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i] < candidates[j]
+	})
+	return candidates, nil
+}
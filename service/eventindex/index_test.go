@@ -0,0 +1,199 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type testEventLog struct {
+	addr    module.Address
+	indexed [][]byte
+	data    [][]byte
+}
+
+func (l *testEventLog) Address() module.Address { return l.addr }
+func (l *testEventLog) Indexed() [][]byte       { return l.indexed }
+func (l *testEventLog) Data() [][]byte          { return l.data }
+
+type testEventLogIterator struct {
+	events []module.EventLog
+	idx    int
+}
+
+func (it *testEventLogIterator) Has() bool { return it.idx < len(it.events) }
+func (it *testEventLogIterator) Next() error {
+	if it.idx >= len(it.events) {
+		return errors.ErrInvalidState
+	}
+	it.idx++
+	return nil
+}
+func (it *testEventLogIterator) Get() (module.EventLog, error) {
+	if it.idx >= len(it.events) {
+		return nil, errors.ErrInvalidState
+	}
+	return it.events[it.idx], nil
+}
+
+type testReceipt struct {
+	module.Receipt
+	events []module.EventLog
+	lb     *txresult.LogsBloom
+}
+
+func newTestReceipt(events ...module.EventLog) *testReceipt {
+	lb := txresult.NewLogsBloom(nil)
+	for _, el := range events {
+		lb.AddLog(el.Address(), el.Indexed())
+	}
+	return &testReceipt{events: events, lb: lb}
+}
+
+func (r *testReceipt) LogsBloom() module.LogsBloom { return r.lb }
+func (r *testReceipt) EventLogIterator() module.EventLogIterator {
+	return &testEventLogIterator{events: r.events}
+}
+
+type testReceiptIterator struct {
+	receipts []module.Receipt
+	idx      int
+}
+
+func (it *testReceiptIterator) Has() bool { return it.idx < len(it.receipts) }
+func (it *testReceiptIterator) Next() error {
+	if it.idx >= len(it.receipts) {
+		return errors.ErrInvalidState
+	}
+	it.idx++
+	return nil
+}
+func (it *testReceiptIterator) Get() (module.Receipt, error) {
+	if it.idx >= len(it.receipts) {
+		return nil, errors.ErrInvalidState
+	}
+	return it.receipts[it.idx], nil
+}
+
+type testReceiptList struct {
+	module.ReceiptList
+	receipts []module.Receipt
+}
+
+func (l *testReceiptList) Iterator() module.ReceiptIterator {
+	return &testReceiptIterator{receipts: l.receipts}
+}
+
+func addBlock(t *testing.T, idx *Index, height int64, rs ...*testReceipt) {
+	lb := txresult.NewLogsBloom(nil)
+	rl := &testReceiptList{}
+	for _, r := range rs {
+		lb.Merge(r.lb)
+		rl.receipts = append(rl.receipts, r)
+	}
+	assert.NoError(t, idx.Add(height, lb, rl))
+}
+
+func TestIndex_Candidates(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	sig1 := "Transfer(Address,Address,int)"
+	sig2 := "Approval(Address,int)"
+
+	idx, err := New(db.NewMapDB())
+	assert.NoError(t, err)
+
+	r, err := idx.Range()
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+
+	for h := int64(1); h <= 2*PostingChunkSize+10; h++ {
+		switch {
+		case h%100 == 0:
+			addBlock(t, idx, h, newTestReceipt(&testEventLog{
+				addr:    addr1,
+				indexed: [][]byte{[]byte(sig1), []byte{byte(h)}},
+			}))
+		case h%150 == 0:
+			addBlock(t, idx, h, newTestReceipt(&testEventLog{
+				addr:    addr2,
+				indexed: [][]byte{[]byte(sig1), []byte{byte(h)}},
+			}), newTestReceipt(&testEventLog{
+				addr:    addr2,
+				indexed: [][]byte{[]byte(sig2), []byte{byte(h)}},
+			}))
+		default:
+			addBlock(t, idx, h)
+		}
+	}
+
+	r, err = idx.Range()
+	assert.NoError(t, err)
+	assert.Equal(t, &Range{First: 1, Last: 2*PostingChunkSize + 10}, r)
+
+	f1 := &Filter{Addr: addr1, Signature: sig1}
+	assert.NoError(t, f1.Compile())
+	hs, err := idx.Candidates(Filters{f1}, 1, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}, hs)
+
+	f2 := &Filter{Signature: sig1}
+	assert.NoError(t, f2.Compile())
+	hs, err = idx.Candidates(Filters{f2}, 950, 1250)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1000, 1050, 1100, 1200}, hs)
+
+	f3 := &Filter{Addr: addr2, Signature: sig2}
+	assert.NoError(t, f3.Compile())
+	hs, err = idx.Candidates(Filters{f3}, 1, 2000)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{150, 450, 750, 1050, 1350, 1650, 1950}, hs)
+
+	hs, err = idx.Candidates(Filters{f1, f3}, 250, 450)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{300, 400, 450}, hs)
+
+	f4 := &Filter{Addr: addr1, Signature: sig2}
+	assert.NoError(t, f4.Compile())
+	hs, err = idx.Candidates(Filters{f4}, 1, 2000)
+	assert.NoError(t, err)
+	assert.Empty(t, hs)
+
+	// adding an indexed height does nothing
+	addBlock(t, idx, 100)
+	hs, err = idx.Candidates(Filters{f1}, 1, 1000)
+	assert.NoError(t, err)
+	assert.Len(t, hs, 10)
+
+	// a height after a gap is rejected without losing the indexed range
+	lb := txresult.NewLogsBloom(nil)
+	err = idx.Add(3*PostingChunkSize, lb, &testReceiptList{})
+	assert.True(t, errors.InvalidStateError.Equals(err))
+	r, err = idx.Range()
+	assert.NoError(t, err)
+	assert.Equal(t, &Range{First: 1, Last: 2*PostingChunkSize + 10}, r)
+	assert.True(t, r.Contains(100))
+	assert.False(t, r.Contains(3*PostingChunkSize))
+}
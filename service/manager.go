@@ -88,8 +88,10 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 	}
 	pTxPool := NewTransactionPool(module.TransactionGroupPatch, chain.PatchTxPoolSize(), tim, pMetric, logger)
 	nTxPool := NewTransactionPool(module.TransactionGroupNormal, chain.NormalTxPoolSize(), tim, nMetric, logger)
+	nTxPool.SetSenderLimit(chain.TxPoolSenderLimit())
 	tm := NewTransactionManager(chain.NID(), tsc, pTxPool, nTxPool, tim, logger)
 	syncm := ssync.NewSyncManager(chain.Database(), chain.NetworkManager(), plt, logger)
+	syncm.SetMetric(metric.NewSyncMetric(chain.MetricContext()))
 
 	mgr := &manager{
 		patchMetric:  pMetric,
@@ -421,6 +423,10 @@ func (m *manager) WaitTransactionResult(id []byte) (<-chan interface{}, error) {
 	return m.tm.WaitResult(id)
 }
 
+func (m *manager) WatchTransactionPool(w module.TransactionPoolWatcher) func() {
+	return m.tm.Watch(w)
+}
+
 type worldContextWrapper struct {
 	state.WorldContext
 	height int64
@@ -482,6 +488,12 @@ func (m *manager) Call(resultHash []byte,
 
 	var wc state.WorldContext
 	if wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash()); err == nil {
+		if wss, _, err = overlayWithStateOverrides(wss, js); err != nil {
+			if errors.IllegalArgumentError.Equals(err) {
+				return nil, InvalidQueryError.Wrap(err, "InvalidStateOverrides")
+			}
+			return nil, err
+		}
 		ws := state.NewReadOnlyWorldState(wss)
 		wc = state.NewWorldContext(ws, bi, nil, m.plt)
 	} else {
@@ -839,6 +851,18 @@ func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error
 }
 
 func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, vh)
+	if err != nil {
+		return nil, err
+	}
+	wss, js, err = overlayWithStateOverrides(wss, js)
+	if err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidStateOverrides")
+		}
+		return nil, err
+	}
+
 	tx, err := transaction.NewTransactionFromJSON(js)
 	if err != nil {
 		return nil, err
@@ -853,18 +877,96 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 	}
 	defer txh.Dispose()
 
-	var wc state.WorldContext
+	ctx, err := m.newContextForTransaction(wss, bi, tx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return txh.Execute(ctx, wss, true)
+}
+
+// simulationBlock is module.TraceBlock for the simulated transaction.
+// The receipt is set after the execution.
+type simulationBlock struct {
+	rct module.Receipt
+}
+
+func (b *simulationBlock) ID() []byte {
+	return nil
+}
+
+func (b *simulationBlock) GetReceipt(txIndex int) module.Receipt {
+	return b.rct
+}
+
+func (m *manager) SimulateTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo, ti *module.TraceInfo) (module.Receipt, error) {
+	tx, err := transaction.NewTransactionFromJSON(js)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Verify(); err != nil && !transaction.InvalidSignatureError.Equals(err) {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidTransaction")
+	}
+
+	var sb *simulationBlock
+	if ti != nil {
+		sb = new(simulationBlock)
+		tiCopy := *ti
+		tiCopy.TraceBlock = sb
+		tiCopy.Range = module.TraceRangeTransaction
+		tiCopy.Group = module.TransactionGroupNormal
+		tiCopy.Index = 0
+		ti = &tiCopy
+	}
 	wss, err := m.trc.GetWorldSnapshot(result, vh)
-	if err == nil {
-		ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err := m.newContextForTransaction(wss, bi, tx, ti)
+	if err != nil {
+		return nil, err
+	}
+
+	tlog := ctx.GetTraceLogger(module.EPhaseTransaction)
+	tlog.OnTransactionStart(0, tx.ID())
+	var rct txresult.Receipt
+	for retry := 0; ; retry++ {
+		txh, err := tx.GetHandler(m.cm)
 		if err != nil {
 			return nil, err
 		}
-		wc = state.NewWorldContext(ws, bi, nil, m.plt)
-	} else {
+		ctx.UpdateSystemInfo()
+		rct, err = txh.Execute(ctx, wss, false)
+		txh.Dispose()
+		if err == nil {
+			break
+		}
+		if !errors.ExecutionFailError.Equals(err) && !errors.CriticalRerunError.Equals(err) {
+			return nil, err
+		}
+		if retry >= RetryCount {
+			return nil, err
+		}
+		if err := ctx.Reset(wss); err != nil {
+			return nil, errors.CriticalUnknownError.Wrapf(err, "FailToResetForRetry")
+		}
+		tlog.OnTransactionReset()
+	}
+	if sb != nil {
+		sb.rct = rct
+	}
+	tlog.OnTransactionEnd(0, tx.ID(), tx.From(), ctx.Treasury(), ctx.Revision(), rct)
+	return rct, nil
+}
+
+func (m *manager) newContextForTransaction(
+	wss state.WorldSnapshot, bi module.BlockInfo, tx transaction.Transaction, ti *module.TraceInfo,
+) (contract.Context, error) {
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
 		return nil, err
 	}
-	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, ti, eeproxy.ForQuery)
 	ctx.SetTransactionInfo(&state.TransactionInfo{
 		Group:     module.TransactionGroupNormal,
 		Index:     0,
@@ -874,8 +976,7 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 		Nonce:     tx.Nonce(),
 	})
 	ctx.UpdateSystemInfo()
-
-	return txh.Execute(ctx, wss, true)
+	return ctx, nil
 }
 
 func (m *manager) AddSyncRequest(id db.BucketID, key []byte) error {
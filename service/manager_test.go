@@ -0,0 +1,139 @@
+/*
+ * Copyright 2025 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type simulationPlatform struct {
+	testPlatform
+}
+
+func (p *simulationPlatform) ToRevision(value int) module.Revision {
+	return module.LatestRevision
+}
+
+const (
+	simulationStepPrice   = 12_500_000_000
+	simulationDefaultStep = 100_000
+)
+
+var (
+	simulationSender   = common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	simulationReceiver = common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+	simulationBalance  = big.NewInt(10_000_000_000_000_000)
+)
+
+func newSimulationManager(t *testing.T) (*manager, []byte) {
+	dbase := db.NewMapDB()
+	logger := log.New()
+	plt := &simulationPlatform{}
+
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	as := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(as, state.VarStepPrice).Set(simulationStepPrice))
+	assert.NoError(t, scoredb.NewArrayDB(as, state.VarStepTypes).Put(string(state.StepTypeDefault)))
+	assert.NoError(t, scoredb.NewDictDB(as, state.VarStepCosts, 1).Set(string(state.StepTypeDefault), simulationDefaultStep))
+	assert.NoError(t, scoredb.NewArrayDB(as, state.VarStepLimitTypes).Put(state.StepLimitTypeInvoke))
+	assert.NoError(t, scoredb.NewDictDB(as, state.VarStepLimit, 1).Set(state.StepLimitTypeInvoke, 2_500_000_000))
+	ws.GetAccountState(simulationSender.ID()).SetBalance(simulationBalance)
+
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	tr := &transitionResult{StateHash: wss.StateHash()}
+
+	cm, err := contract.NewContractManager(dbase, t.TempDir(), logger)
+	assert.NoError(t, err)
+	m := &manager{
+		plt: plt,
+		db:  dbase,
+		cm:  cm,
+		trc: newTransitionResultCache(dbase, plt, 10, 10, logger),
+		log: logger,
+	}
+	return m, tr.Bytes()
+}
+
+func newSimulationTransactionJSON(value, stepLimit int64) []byte {
+	sig := base64.StdEncoding.EncodeToString(make([]byte, 65))
+	return []byte(fmt.Sprintf(`{
+		"version": "0x3",
+		"from": "%s",
+		"to": "%s",
+		"value": "%#x",
+		"stepLimit": "%#x",
+		"timestamp": "0x1",
+		"nid": "0x1",
+		"signature": "%s"
+	}`, simulationSender, simulationReceiver, value, stepLimit, sig))
+}
+
+func TestManager_SimulateTransaction(t *testing.T) {
+	m, result := newSimulationManager(t)
+	bi := common.NewBlockInfo(10, 1000)
+
+	rct, err := m.SimulateTransaction(result, nil, newSimulationTransactionJSON(100, simulationDefaultStep), bi, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, module.StatusSuccess, rct.Status())
+	assert.Equal(t, 0, rct.StepUsed().Cmp(big.NewInt(simulationDefaultStep)))
+	assert.Equal(t, 0, rct.StepPrice().Cmp(big.NewInt(simulationStepPrice)))
+
+	// a step limit below the default step is reported in the receipt
+	rct, err = m.SimulateTransaction(result, nil, newSimulationTransactionJSON(100, simulationDefaultStep-1), bi, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, module.StatusOutOfStep, rct.Status())
+}
+
+func TestManager_SimulateTransactionKeepsState(t *testing.T) {
+	m, result := newSimulationManager(t)
+	bi := common.NewBlockInfo(10, 1000)
+
+	before, err := m.trc.GetWorldSnapshot(result, nil)
+	assert.NoError(t, err)
+
+	js := newSimulationTransactionJSON(100, simulationDefaultStep)
+	for i := 0; i < 2; i++ {
+		rct, err := m.SimulateTransaction(result, nil, js, bi, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, module.StatusSuccess, rct.Status())
+	}
+
+	after, err := m.trc.GetWorldSnapshot(result, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, before.StateHash(), after.StateHash())
+	assert.Equal(t, 0, simulationBalance.Cmp(after.GetAccountSnapshot(simulationSender.ID()).GetBalance()))
+
+	fresh, err := newWorldSnapshot(m.db, m.plt, result, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, before.StateHash(), fresh.StateHash())
+	assert.Equal(t, 0, simulationBalance.Cmp(fresh.GetAccountSnapshot(simulationSender.ID()).GetBalance()))
+	assert.Nil(t, fresh.GetAccountSnapshot(simulationReceiver.ID()))
+}
@@ -1537,10 +1537,29 @@ func (s *ChainScore) Ex_getBTPPublicKey(address module.Address, name string) ([]
 	return s.newBTPContext().GetPublicKey(address, name), nil
 }
 
+// btpRevisions has revisions enabling network types and DSAs added after
+// Revision9.
+var btpRevisions = map[string]int{
+	"bls":           Revision10,
+	"bls/bls12-381": Revision10,
+	"ed25519":       Revision10,
+	"eddsa/ed25519": Revision10,
+}
+
+func (s *ChainScore) checkBTPRevision(name string) error {
+	if rev, ok := btpRevisions[name]; ok && s.cc.Revision().Value() < rev {
+		return scoreresult.InvalidParameterError.Errorf("NotSupported(%s)", name)
+	}
+	return nil
+}
+
 func (s *ChainScore) Ex_openBTPNetwork(networkTypeName string, name string, owner module.Address) (int64, error) {
 	if err := s.checkGovernance(true); err != nil {
 		return 0, err
 	}
+	if err := s.checkBTPRevision(networkTypeName); err != nil {
+		return 0, err
+	}
 	if bs, err := s.getBTPState(); err != nil {
 		return 0, err
 	} else {
@@ -1635,6 +1654,9 @@ func (s *ChainScore) Ex_setBTPPublicKey(name string, pubKey []byte) error {
 	if s.from.IsContract() {
 		return scoreresult.New(module.StatusAccessDenied, "NoPermission")
 	}
+	if err := s.checkBTPRevision(name); err != nil {
+		return err
+	}
 	if bs, err := s.getBTPState(); err != nil {
 		return err
 	} else {
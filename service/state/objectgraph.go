@@ -133,6 +133,13 @@ func (o *objectGraph) Get(withData bool) (int, []byte, []byte, error) {
 	}
 }
 
+func (o *objectGraph) graphHashOf() []byte {
+	if o == nil {
+		return nil
+	}
+	return o.graphHash
+}
+
 func (o *objectGraph) Resolve(bd merkle.Builder) error {
 	if len(o.graphHash) > 0 {
 		v, err := o.bk.Get(o.graphHash)
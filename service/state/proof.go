@@ -0,0 +1,104 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/module"
+)
+
+// GetProofForAccount returns the proof of the account in the world snapshot.
+// The proof can be verified by VerifyAccountProof with the state hash.
+func GetProofForAccount(wss WorldSnapshot, id []byte) ([][]byte, error) {
+	ws, ok := wss.(*worldSnapshotImpl)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("UnsupportedSnapshot(type=%T)", wss)
+	}
+	if ws.accounts.Empty() {
+		return nil, errors.NotFoundError.New("EmptyState")
+	}
+	proof := ws.accounts.GetProof(addressIDToKey(id))
+	if proof == nil {
+		return nil, errors.NotFoundError.Errorf("NoAccount(id=%#x)", id)
+	}
+	return proof, nil
+}
+
+// StorageHashOf returns the hash of the storage of the account.
+// It returns nil if the storage is empty.
+func StorageHashOf(ass AccountSnapshot) []byte {
+	if s, ok := ass.(*accountSnapshotImpl); ok {
+		if store, ok := s.store.(trie.Immutable); ok {
+			return store.Hash()
+		}
+	}
+	return nil
+}
+
+// GetProofForStorage returns the proof of the value for the key in the
+// storage of the account. The proof can be verified by VerifyStorageProof
+// with the account.
+func GetProofForStorage(ass AccountSnapshot, key []byte) ([][]byte, error) {
+	s, ok := ass.(*accountSnapshotImpl)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("UnsupportedSnapshot(type=%T)", ass)
+	}
+	store, ok := s.store.(trie.Immutable)
+	if !ok || store.Empty() {
+		return nil, errors.NotFoundError.New("EmptyStorage")
+	}
+	proof := store.GetProof(key)
+	if proof == nil {
+		return nil, errors.NotFoundError.Errorf("NoValue(key=%#x)", key)
+	}
+	return proof, nil
+}
+
+// VerifyAccountProof verifies the proof of the account for the address
+// against the state hash. It returns the account on success. Values in the
+// storage of the returned account can't be accessed, but the storage hash
+// can be used for VerifyStorageProof.
+func VerifyAccountProof(stateHash []byte, addr module.Address, proof [][]byte) (AccountSnapshot, error) {
+	accounts := trie_manager.NewImmutableForObject(db.NewNullDB(), stateHash, AccountType)
+	obj, err := accounts.Prove(addressIDToKey(addr.ID()), proof)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidAccountProof")
+	}
+	ass, ok := obj.(AccountSnapshot)
+	if !ok {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidAccount(type=%T)", obj)
+	}
+	return ass, nil
+}
+
+// VerifyStorageProof verifies the proof of the value for the key against
+// the storage hash of the account. It returns the value on success.
+func VerifyStorageProof(ass AccountSnapshot, key []byte, proof [][]byte) ([]byte, error) {
+	storageHash := StorageHashOf(ass)
+	if len(storageHash) == 0 {
+		return nil, errors.IllegalArgumentError.New("EmptyStorage")
+	}
+	store := trie_manager.NewImmutable(db.NewNullDB(), storageHash)
+	value, err := store.Prove(key, proof)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidStorageProof")
+	}
+	return value, nil
+}
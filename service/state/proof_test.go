@@ -0,0 +1,72 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestProof_AccountAndStorage(t *testing.T) {
+	dbase := db.NewMapDB()
+	ws := NewWorldState(dbase, nil, nil, nil, nil)
+
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	other := common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+
+	ws.GetAccountState(eoa.ID()).SetBalance(big.NewInt(100))
+	as := ws.GetAccountState(score.ID())
+	as.InitContractAccount(eoa)
+	as.SetBalance(big.NewInt(200))
+	_, err := as.SetValue([]byte("key1"), []byte("value1"))
+	assert.NoError(t, err)
+	_, err = as.SetValue([]byte("key2"), []byte("value2"))
+	assert.NoError(t, err)
+
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	stateHash := wss.StateHash()
+
+	// account proof
+	proof, err := GetProofForAccount(wss, eoa.ID())
+	assert.NoError(t, err)
+	ass, err := VerifyAccountProof(stateHash, eoa, proof)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), ass.GetBalance().Int64())
+
+	// proof for other account must fail
+	_, err = VerifyAccountProof(stateHash, score, proof)
+	assert.Error(t, err)
+
+	// no account
+	_, err = GetProofForAccount(wss, other.ID())
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	// storage proof
+	proof, err = GetProofForAccount(wss, score.ID())
+	assert.NoError(t, err)
+	ass, err = VerifyAccountProof(stateHash, score, proof)
+	assert.NoError(t, err)
+	assert.True(t, ass.IsContract())
+	assert.Equal(t, StorageHashOf(wss.GetAccountSnapshot(score.ID())), StorageHashOf(ass))
+
+	sproof, err := GetProofForStorage(wss.GetAccountSnapshot(score.ID()), []byte("key2"))
+	assert.NoError(t, err)
+	value, err := VerifyStorageProof(ass, []byte("key2"), sproof)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value2"), value)
+
+	_, err = VerifyStorageProof(ass, []byte("key2"), sproof[:len(sproof)-1])
+	assert.Error(t, err)
+
+	_, err = GetProofForStorage(wss.GetAccountSnapshot(score.ID()), []byte("key3"))
+	assert.True(t, errors.NotFoundError.Equals(err))
+
+	_, err = GetProofForStorage(wss.GetAccountSnapshot(eoa.ID()), []byte("key1"))
+	assert.True(t, errors.NotFoundError.Equals(err))
+}
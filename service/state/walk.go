@@ -0,0 +1,102 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+// refsOfAccountBytes returns the hash of the storage and hashes of objects
+// in BytesByHash bucket referenced by the account.
+func refsOfAccountBytes(bs []byte) ([]byte, [][]byte, error) {
+	if len(bs) == 0 {
+		return nil, nil, nil
+	}
+	ass := newAccountSnapshot(db.NewNullDB())
+	if err := ass.Reset(ass.database, bs); err != nil {
+		return nil, nil, err
+	}
+	var objs [][]byte
+	for _, h := range [][]byte{
+		ass.apiInfo.hash,
+		ass.curContract.CodeHash(),
+		ass.nextContract.CodeHash(),
+		ass.objGraph.graphHashOf(),
+	} {
+		if len(h) > 0 {
+			objs = append(objs, h)
+		}
+	}
+	return StorageHashOf(ass), objs, nil
+}
+
+// WalkDiff visits MPT nodes of the world state for stateHash, which don't
+// exist at the same position of the world state for base. It also visits
+// nodes of the storages of changed accounts in the same way. base can be nil
+// to visit all the nodes. onNode is called with the hash of each node.
+// onObject is called with the hash of each object in BytesByHash bucket
+// (codes, API information and object graphs) referenced by changed
+// accounts, which isn't referenced by the account of base. onObject can be
+// nil.
+func WalkDiff(dbase db.Database, stateHash, base []byte, onNode, onObject func(h []byte) error) error {
+	return walkDiff(dbase, trie_manager.WalkDiff, stateHash, base, onNode, onObject)
+}
+
+// WalkDiffPartial is same as WalkDiff except that it allows missing nodes of
+// the world states being pruned.
+func WalkDiffPartial(dbase db.Database, stateHash, base []byte, onNode, onObject func(h []byte) error) error {
+	return walkDiff(dbase, trie_manager.WalkDiffPartial, stateHash, base, onNode, onObject)
+}
+
+type walkDiffFunc func(bk db.Bucket, root, base []byte, onNode func(h []byte) error, onValue func(v, bv []byte) error) error
+
+func walkDiff(dbase db.Database, walk walkDiffFunc, stateHash, base []byte, onNode, onObject func(h []byte) error) error {
+	bk, err := dbase.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return err
+	}
+	return walk(bk, stateHash, base, onNode, func(v, bv []byte) error {
+		sh, objs, err := refsOfAccountBytes(v)
+		if err != nil {
+			return err
+		}
+		bsh, bobjs, err := refsOfAccountBytes(bv)
+		if err != nil {
+			return err
+		}
+		if onObject != nil {
+		objects:
+			for _, h := range objs {
+				for _, bh := range bobjs {
+					if bytes.Equal(h, bh) {
+						continue objects
+					}
+				}
+				if err := onObject(h); err != nil {
+					return err
+				}
+			}
+		}
+		if len(sh) == 0 || bytes.Equal(sh, bsh) {
+			return nil
+		}
+		return walk(bk, sh, bsh, onNode, nil)
+	})
+}
@@ -0,0 +1,106 @@
+package state
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestWalkDiff(t *testing.T) {
+	dbase := db.NewMapDB()
+	ws := NewWorldState(dbase, nil, nil, nil, nil)
+
+	owner := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	for i := 0; i < 100; i++ {
+		addr := common.MustNewAddressFromString(fmt.Sprintf("hx%040x", i+10))
+		ws.GetAccountState(addr.ID()).SetBalance(big.NewInt(int64(i + 1)))
+	}
+	as := ws.GetAccountState(score.ID())
+	as.InitContractAccount(owner)
+	for i := 0; i < 100; i++ {
+		_, err := as.SetValue([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		assert.NoError(t, err)
+	}
+	wss1 := ws.GetSnapshot()
+	assert.NoError(t, wss1.Flush())
+
+	_, err := as.SetValue([]byte("key1"), []byte("changed"))
+	assert.NoError(t, err)
+	ws.GetAccountState(owner.ID()).SetBalance(big.NewInt(100))
+	wss2 := ws.GetSnapshot()
+	assert.NoError(t, wss2.Flush())
+
+	collect := func(root, base []byte) map[string]bool {
+		nodes := make(map[string]bool)
+		err := WalkDiff(dbase, root, base, func(h []byte) error {
+			nodes[string(h)] = true
+			return nil
+		}, nil)
+		assert.NoError(t, err)
+		return nodes
+	}
+	all1 := collect(wss1.StateHash(), nil)
+	all2 := collect(wss2.StateHash(), nil)
+
+	// storage nodes are included
+	storage := StorageHashOf(NewReadOnlyWorldState(wss1).GetAccountSnapshot(score.ID()))
+	assert.True(t, all1[string(storage)])
+
+	diff := collect(wss2.StateHash(), wss1.StateHash())
+	for h := range all2 {
+		if !all1[h] {
+			assert.True(t, diff[h], "missing node %x", h)
+		}
+	}
+	for h := range diff {
+		assert.True(t, all2[h])
+	}
+	assert.Less(t, len(diff), len(all2))
+
+	diff = collect(wss1.StateHash(), wss1.StateHash())
+	assert.Empty(t, diff)
+}
+
+func TestWalkDiff_Objects(t *testing.T) {
+	dbase := db.NewMapDB()
+	ws := NewWorldState(dbase, nil, nil, nil, nil)
+	owner := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+
+	as := ws.GetAccountState(score.ID())
+	as.InitContractAccount(owner)
+	_, err := as.DeployContract([]byte("code1"), JavaEE, CTAppJava, nil, []byte("tx1"))
+	assert.NoError(t, err)
+	wss1 := ws.GetSnapshot()
+	assert.NoError(t, wss1.Flush())
+
+	_, err = as.DeployContract([]byte("code2"), JavaEE, CTAppJava, nil, []byte("tx2"))
+	assert.NoError(t, err)
+	wss2 := ws.GetSnapshot()
+	assert.NoError(t, wss2.Flush())
+
+	collect := func(root, base []byte) map[string]bool {
+		objs := make(map[string]bool)
+		err := WalkDiff(dbase, root, base, func(h []byte) error {
+			return nil
+		}, func(h []byte) error {
+			objs[string(h)] = true
+			return nil
+		})
+		assert.NoError(t, err)
+		return objs
+	}
+	code1 := crypto.SHA3Sum256([]byte("code1"))
+	code2 := crypto.SHA3Sum256([]byte("code2"))
+	assert.Equal(t, map[string]bool{string(code1): true}, collect(wss1.StateHash(), nil))
+	assert.Equal(t, map[string]bool{string(code2): true}, collect(wss2.StateHash(), wss1.StateHash()))
+	assert.Equal(t, map[string]bool{string(code1): true}, collect(wss1.StateHash(), wss2.StateHash()))
+	assert.Empty(t, collect(wss2.StateHash(), wss2.StateHash()))
+}
@@ -0,0 +1,355 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/containerdb"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	StorageTypeVar  = "var"
+	StorageTypeDict = "dict"
+)
+
+const (
+	ValueTypeInt     = "int"
+	ValueTypeStr     = "str"
+	ValueTypeBytes   = "bytes"
+	ValueTypeBool    = "bool"
+	ValueTypeAddress = "Address"
+)
+
+const (
+	// MaxAccountOverrides is the maximum number of accounts to override
+	// in a request.
+	MaxAccountOverrides = 16
+	// MaxStorageOverrides is the maximum number of storage entries to
+	// override in a request.
+	MaxStorageOverrides = 256
+	// MaxCodeOverrideSize is the maximum total size of the codes in
+	// a request.
+	MaxCodeOverrideSize = contract.ContentSizeLimit
+)
+
+// StorageKey specifies an entry of the storage of the account by the raw key
+// or by the containerdb path (Type, Name and Keys). KeyTypes has the value
+// type of each element of Keys.
+type StorageKey struct {
+	Key      common.HexBytes `json:"key,omitempty"`
+	Type     string          `json:"type,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Keys     []string        `json:"keys,omitempty"`
+	KeyTypes []string        `json:"keyTypes,omitempty"`
+}
+
+// StorageOverride replaces an entry of the storage of the account.
+// Value nil means deletion of the entry. ValueType is the type of the value
+// for the containerdb path.
+type StorageOverride struct {
+	StorageKey
+	Value     *string `json:"value"`
+	ValueType string  `json:"valueType,omitempty"`
+}
+
+// typedValueOf returns the value of the type for the string used in
+// containerdb paths.
+func typedValueOf(typ, s string) (interface{}, error) {
+	switch typ {
+	case ValueTypeInt:
+		v := new(common.HexInt)
+		if err := intconv.ParseBigInt(&v.Int, s); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case ValueTypeStr:
+		return s, nil
+	case ValueTypeBytes:
+		bs, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		return bs, nil
+	case ValueTypeBool:
+		switch s {
+		case "0x1":
+			return true, nil
+		case "0x0":
+			return false, nil
+		default:
+			return nil, errors.IllegalArgumentError.Errorf("InvalidBool(value=%s)", s)
+		}
+	case ValueTypeAddress:
+		addr := new(common.Address)
+		if err := addr.SetStringStrict(s); err != nil {
+			return nil, err
+		}
+		return addr, nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("InvalidValueType(type=%s)", typ)
+	}
+}
+
+// Bytes returns the raw key of the entry.
+func (o *StorageKey) Bytes() ([]byte, error) {
+	switch o.Type {
+	case "":
+		if len(o.Key) == 0 {
+			return nil, errors.IllegalArgumentError.New("EmptyStorageKey")
+		}
+		return o.Key, nil
+	case StorageTypeVar:
+		if len(o.Keys) != 0 {
+			return nil, errors.IllegalArgumentError.Errorf("KeysForVarDB(name=%s)", o.Name)
+		}
+		return containerdb.ToKey(containerdb.HashBuilder, scoredb.VarDBPrefix).Append(o.Name).Build(), nil
+	case StorageTypeDict:
+		if len(o.Keys) == 0 {
+			return nil, errors.IllegalArgumentError.Errorf("NoKeysForDictDB(name=%s)", o.Name)
+		}
+		if len(o.KeyTypes) != len(o.Keys) {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidKeyTypes(name=%s,keys=%d,types=%d)",
+				o.Name, len(o.Keys), len(o.KeyTypes))
+		}
+		keys := make([]interface{}, len(o.Keys))
+		for i, k := range o.Keys {
+			v, err := typedValueOf(o.KeyTypes[i], k)
+			if err != nil {
+				return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidKey(key=%s)", k)
+			}
+			keys[i] = v
+		}
+		return containerdb.ToKey(containerdb.HashBuilder, scoredb.DictDBPrefix, o.Name).Append(keys...).Build(), nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("InvalidStorageType(type=%s)", o.Type)
+	}
+}
+
+func (o *StorageOverride) valueBytes() ([]byte, error) {
+	if o.Value == nil {
+		return nil, nil
+	}
+	if o.Type == "" {
+		bs, err := hex.DecodeString(strings.TrimPrefix(*o.Value, "0x"))
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidValue(value=%s)", *o.Value)
+		}
+		return bs, nil
+	}
+	v, err := typedValueOf(o.ValueType, *o.Value)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidValue(value=%s)", *o.Value)
+	}
+	return containerdb.ToBytes(v), nil
+}
+
+func (o *StorageOverride) Apply(as state.AccountState) error {
+	key, err := o.StorageKey.Bytes()
+	if err != nil {
+		return err
+	}
+	value, err := o.valueBytes()
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		_, err = as.DeleteValue(key)
+	} else {
+		_, err = as.SetValue(key, value)
+	}
+	return err
+}
+
+// AccountOverride replaces the state of the account. Code replaces the code
+// of the current contract keeping its API. CodeFrom replaces the contract
+// with the current contract of the specified account.
+type AccountOverride struct {
+	Balance  *common.HexInt     `json:"balance,omitempty"`
+	Code     common.HexBytes    `json:"code,omitempty"`
+	CodeFrom *common.Address    `json:"codeFrom,omitempty"`
+	Storage  []*StorageOverride `json:"storage,omitempty"`
+}
+
+func copyContract(ws state.WorldState, as state.AccountState, addr, from *common.Address) error {
+	src := ws.GetAccountState(from.ID())
+	c := src.ActiveContract()
+	if c == nil {
+		return errors.IllegalArgumentError.Errorf("NoActiveContract(addr=%s)", from)
+	}
+	code, err := c.Code()
+	if err != nil {
+		return err
+	}
+	apiInfo, err := src.APIInfo()
+	if err != nil {
+		return err
+	}
+	if !as.IsContract() {
+		as.InitContractAccount(src.ContractOwner())
+	}
+	deployID := crypto.SHA3Sum256(append(addr.Bytes(), c.CodeHash()...))
+	if _, err := as.DeployContract(code, c.EEType(), c.ContentType(), c.Params(), deployID); err != nil {
+		return err
+	}
+	as.SetAPIInfo(apiInfo)
+	if err := as.ActivateNextContract(); err != nil {
+		return err
+	}
+	return as.AcceptContract(deployID, nil)
+}
+
+func (o *AccountOverride) Apply(ws state.WorldState, addr *common.Address) error {
+	as := ws.GetAccountState(addr.ID())
+	if o.Balance != nil {
+		if o.Balance.Sign() < 0 {
+			return errors.IllegalArgumentError.Errorf("NegativeBalance(addr=%s)", addr)
+		}
+		as.SetBalance(o.Balance.Value())
+	}
+	if o.CodeFrom != nil {
+		if !addr.IsContract() {
+			return errors.IllegalArgumentError.Errorf("NotContractAddress(addr=%s)", addr)
+		}
+		if err := copyContract(ws, as, addr, o.CodeFrom); err != nil {
+			return err
+		}
+	}
+	if len(o.Code) > 0 {
+		c := as.Contract()
+		if c == nil {
+			return errors.IllegalArgumentError.Errorf("NoContract(addr=%s)", addr)
+		}
+		if err := c.SetCode(o.Code); err != nil {
+			return err
+		}
+	}
+	for _, so := range o.Storage {
+		if so == nil {
+			continue
+		}
+		if err := so.Apply(as); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StateOverrides is the set of AccountOverride keyed by the address.
+type StateOverrides map[string]*AccountOverride
+
+// Verify checks the number of overrides and the size of the codes against
+// the limits.
+func (s StateOverrides) Verify() error {
+	if len(s) > MaxAccountOverrides {
+		return errors.IllegalArgumentError.Errorf(
+			"TooManyAccountOverrides(count=%d,max=%d)", len(s), MaxAccountOverrides)
+	}
+	var storages, codeSize int
+	for _, o := range s {
+		if o == nil {
+			continue
+		}
+		storages += len(o.Storage)
+		codeSize += len(o.Code)
+	}
+	if storages > MaxStorageOverrides {
+		return errors.IllegalArgumentError.Errorf(
+			"TooManyStorageOverrides(count=%d,max=%d)", storages, MaxStorageOverrides)
+	}
+	if codeSize > MaxCodeOverrideSize {
+		return errors.IllegalArgumentError.Errorf(
+			"TooLargeCodeOverrides(size=%d,max=%d)", codeSize, MaxCodeOverrideSize)
+	}
+	return nil
+}
+
+// Apply applies the overrides to the world state. Accounts are updated in the
+// order of the addresses for consistent results.
+func (s StateOverrides) Apply(ws state.WorldState) error {
+	if err := s.Verify(); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		o := s[k]
+		if o == nil {
+			continue
+		}
+		addr := new(common.Address)
+		if err := addr.SetStringStrict(k); err != nil {
+			return errors.IllegalArgumentError.Wrapf(err, "InvalidAddress(addr=%s)", k)
+		}
+		if err := o.Apply(ws, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overlayWithStateOverrides returns the snapshot of the world state
+// on which the overrides in the JSON object are applied. The changes are
+// kept in memory, so they are never written to the database. It returns
+// the JSON without the overrides for further parsing.
+func overlayWithStateOverrides(wss state.WorldSnapshot, js []byte) (state.WorldSnapshot, []byte, error) {
+	var jso map[string]json.RawMessage
+	if err := json.Unmarshal(js, &jso); err != nil {
+		return wss, js, nil
+	}
+	raw, ok := jso[keyStateOverrides]
+	if !ok {
+		return wss, js, nil
+	}
+	delete(jso, keyStateOverrides)
+	js, err := json.Marshal(jso)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var overrides StateOverrides
+	if err := json.Unmarshal(raw, &overrides); err != nil {
+		return nil, nil, errors.IllegalArgumentError.Wrap(err, "InvalidStateOverrides")
+	}
+	if len(overrides) == 0 {
+		return wss, js, nil
+	}
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := overrides.Apply(ws); err != nil {
+		return nil, nil, err
+	}
+	return ws.GetSnapshot(), js, nil
+}
+
+const keyStateOverrides = "stateOverrides"
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestOverlayWithStateOverrides(t *testing.T) {
+	dbase := db.NewMapDB()
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+
+	ws.GetAccountState(eoa.ID()).SetBalance(big.NewInt(100))
+	as := ws.GetAccountState(score.ID())
+	as.InitContractAccount(eoa)
+	_, err := as.SetValue([]byte{0x01}, []byte{0x02})
+	assert.NoError(t, err)
+	wss := ws.GetSnapshot()
+
+	js := []byte(`{
+		"to": "cx0000000000000000000000000000000000000002",
+		"stateOverrides": {
+			"hx0000000000000000000000000000000000000001": {
+				"balance": "0x3e8"
+			},
+			"cx0000000000000000000000000000000000000002": {
+				"storage": [
+					{ "key": "0x01", "value": null },
+					{ "key": "0x03", "value": "0x04" },
+					{ "type": "var", "name": "owner", "value": "hx0000000000000000000000000000000000000001", "valueType": "Address" },
+					{ "type": "var", "name": "symbol", "value": "0x10", "valueType": "str" },
+					{ "type": "dict", "name": "balances", "keys": ["hx0000000000000000000000000000000000000001"], "keyTypes": ["Address"], "value": "0x64", "valueType": "int" }
+				]
+			}
+		}
+	}`)
+	wss2, js2, err := overlayWithStateOverrides(wss, js)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"to": "cx0000000000000000000000000000000000000002"}`, string(js2))
+
+	ws2 := state.NewReadOnlyWorldState(wss2)
+	assert.Equal(t, int64(1000), ws2.GetAccountState(eoa.ID()).GetBalance().Int64())
+	as2 := ws2.GetAccountState(score.ID())
+	v, err := as2.GetValue([]byte{0x01})
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	v, err = as2.GetValue([]byte{0x03})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x04}, v)
+	assert.True(t, eoa.Equal(scoredb.NewVarDB(as2, "owner").Address()))
+	assert.Equal(t, "0x10", scoredb.NewVarDB(as2, "symbol").String())
+	assert.Equal(t, int64(100), scoredb.NewDictDB(as2, "balances", 1).Get(eoa).Int64())
+
+	// original state is not changed
+	ws1 := state.NewReadOnlyWorldState(wss)
+	assert.Equal(t, int64(100), ws1.GetAccountState(eoa.ID()).GetBalance().Int64())
+	v, err = ws1.GetAccountState(score.ID()).GetValue([]byte{0x01})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x02}, v)
+
+	// without overrides
+	js = []byte(`{"to": "cx0000000000000000000000000000000000000002"}`)
+	wss3, js3, err := overlayWithStateOverrides(wss, js)
+	assert.NoError(t, err)
+	assert.Equal(t, wss, wss3)
+	assert.Equal(t, js, js3)
+
+	// invalid overrides
+	for _, js := range []string{
+		`{"stateOverrides": {"hx01": {"balance": "0x1"}}}`,
+		`{"stateOverrides": {"hx0000000000000000000000000000000000000001": {"balance": "-0x1"}}}`,
+		`{"stateOverrides": {"cx0000000000000000000000000000000000000002": {"storage": [{"type": "array", "name": "a", "value": "0x1"}]}}}`,
+		`{"stateOverrides": {"hx0000000000000000000000000000000000000001": {"code": "0x01"}}}`,
+		`{"stateOverrides": {"cx0000000000000000000000000000000000000002": {"storage": [{"type": "var", "name": "a", "value": "0x1"}]}}}`,
+		`{"stateOverrides": {"cx0000000000000000000000000000000000000002": {"storage": [{"type": "var", "name": "a", "value": "0x1", "valueType": "float"}]}}}`,
+		`{"stateOverrides": {"cx0000000000000000000000000000000000000002": {"storage": [{"type": "dict", "name": "a", "keys": ["a"], "value": "0x1", "valueType": "int"}]}}}`,
+		`{"stateOverrides": {"cx0000000000000000000000000000000000000002": {"storage": [{"type": "var", "name": "a", "value": "hx01", "valueType": "Address"}]}}}`,
+	} {
+		_, _, err = overlayWithStateOverrides(wss, []byte(js))
+		assert.Error(t, err, js)
+	}
+}
+
+func TestStateOverrides_Verify(t *testing.T) {
+	overrides := make(StateOverrides)
+	for i := 0; i < MaxAccountOverrides; i++ {
+		addr := common.NewAccountAddress([]byte{byte(i)})
+		overrides[addr.String()] = &AccountOverride{}
+	}
+	assert.NoError(t, overrides.Verify())
+	overrides[common.NewAccountAddress([]byte{0xff}).String()] = &AccountOverride{}
+	assert.Error(t, overrides.Verify())
+
+	storages := make([]*StorageOverride, MaxStorageOverrides+1)
+	overrides = StateOverrides{
+		"cx0000000000000000000000000000000000000002": {Storage: storages[:MaxStorageOverrides]},
+	}
+	assert.NoError(t, overrides.Verify())
+	overrides["cx0000000000000000000000000000000000000003"] = &AccountOverride{Storage: storages[:1]}
+	assert.Error(t, overrides.Verify())
+
+	overrides = StateOverrides{
+		"cx0000000000000000000000000000000000000002": {Code: make([]byte, MaxCodeOverrideSize)},
+	}
+	assert.NoError(t, overrides.Verify())
+	overrides["cx0000000000000000000000000000000000000003"] = &AccountOverride{Code: []byte{0x01}}
+	assert.Error(t, overrides.Verify())
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type accountProof struct {
+	stateHash []byte
+	address   module.Address
+	account   []byte
+	proof     [][]byte
+}
+
+func (p *accountProof) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{
+		"stateHash":    common.HexBytes(p.stateHash),
+		"address":      p.address,
+		"account":      common.HexBytes(p.account),
+		"accountProof": common.SliceOfHexBytes(p.proof),
+	}, nil
+}
+
+type storageProof struct {
+	accountProof
+	storageHash []byte
+	key         []byte
+	value       []byte
+	proof       [][]byte
+}
+
+func (p *storageProof) ToJSON(version module.JSONVersion) (interface{}, error) {
+	jso, err := p.accountProof.ToJSON(version)
+	if err != nil {
+		return nil, err
+	}
+	m := jso.(map[string]interface{})
+	m["storageHash"] = common.HexBytes(p.storageHash)
+	m["key"] = common.HexBytes(p.key)
+	m["value"] = common.HexBytes(p.value)
+	m["storageProof"] = common.SliceOfHexBytes(p.proof)
+	return m, nil
+}
+
+func (m *manager) getAccountProof(result []byte, addr module.Address) (*accountProof, state.AccountSnapshot, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	ass := wss.GetAccountSnapshot(addr.ID())
+	if ass == nil {
+		return nil, nil, errors.NotFoundError.Errorf("NoAccount(addr=%s)", addr)
+	}
+	if ass.IsContract() != addr.IsContract() {
+		return nil, nil, errors.IllegalArgumentError.Errorf(
+			"InvalidAddressPrefix(valid=%s)",
+			common.NewAddressWithTypeAndID(!addr.IsContract(), addr.ID()))
+	}
+	proof, err := state.GetProofForAccount(wss, addr.ID())
+	if err != nil {
+		return nil, nil, err
+	}
+	return &accountProof{
+		stateHash: wss.StateHash(),
+		address:   addr,
+		account:   ass.Bytes(),
+		proof:     proof,
+	}, ass, nil
+}
+
+func (m *manager) GetProofForAccount(result []byte, addr module.Address) (module.StateProof, error) {
+	ap, _, err := m.getAccountProof(result, addr)
+	if err != nil {
+		return nil, err
+	}
+	return ap, nil
+}
+
+func (m *manager) GetProofForStorage(result []byte, addr module.Address, key []byte) (module.StateProof, error) {
+	if !addr.IsContract() {
+		return nil, NotContractAddressError.Errorf("Given Address(%s) isn't contract", addr)
+	}
+	ap, ass, err := m.getAccountProof(result, addr)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ass.GetValue(key)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := state.GetProofForStorage(ass, key)
+	if err != nil {
+		return nil, err
+	}
+	return &storageProof{
+		accountProof: *ap,
+		storageHash:  state.StorageHashOf(ass),
+		key:          key,
+		value:        value,
+		proof:        proof,
+	}, nil
+}
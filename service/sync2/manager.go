@@ -8,6 +8,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service/state"
 )
 
@@ -55,6 +56,7 @@ type Manager struct {
 	plt      Platform
 	ds       *dataSyncer
 	reactors []SyncReactor
+	mtr      *metric.SyncMetric
 }
 
 type Result struct {
@@ -66,7 +68,12 @@ type Result struct {
 
 func (m *Manager) NewSyncer(ah, prh, nrh, vh, ed, bh []byte, noBuffer bool) Syncer {
 	return newSyncerWithHashes(
-		m.db, m.reactors, m.plt, ah, prh, nrh, vh, ed, bh, m.logger, noBuffer)
+		m.db, m.reactors, m.plt, ah, prh, nrh, vh, ed, bh, m.logger, noBuffer, m.mtr)
+}
+
+// SetMetric sets the metric for recording progresses of following syncers.
+func (m *Manager) SetMetric(mtr *metric.SyncMetric) {
+	m.mtr = mtr
 }
 
 func (m *Manager) AddRequest(id db.BucketID, key []byte) error {
@@ -12,6 +12,7 @@ import (
 
 type DataSender interface {
 	RequestData(peer module.PeerID, reqID uint32, reqData []BucketIDAndBytes) error
+	ReportPeer(peer module.PeerID, reason module.PenaltyReason)
 }
 
 type DataHandler func(reqID uint32, sender *peer, data []BucketIDAndBytes)
@@ -91,3 +92,10 @@ func (p *peer) OnData(reqID uint32, status errCode, data []BucketIDAndBytes) err
 		return errors.NotFoundError.Errorf("UnknownRequestID(req=%d)", reqID)
 	}
 }
+
+// ReportInvalidData reports the peer sending invalid data.
+func (p *peer) ReportInvalidData() {
+	if p.sender != nil {
+		p.sender.ReportPeer(p.id, module.PenaltyInvalidSyncData)
+	}
+}
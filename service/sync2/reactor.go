@@ -69,6 +69,12 @@ func (r *ReactorCommon) OnLeave(id module.PeerID) {
 	}
 }
 
+func (r *ReactorCommon) ReportPeer(id module.PeerID, reason module.PenaltyReason) {
+	if r.ph != nil {
+		r.ph.ReportPeer(id, reason)
+	}
+}
+
 func (r *ReactorCommon) GetVersion() byte {
 	return r.version
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/merkle"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/service/state"
 	"github.com/icon-project/goloop/service/txresult"
 )
@@ -33,6 +34,7 @@ type syncer struct {
 	processors []SyncProcessor
 	noBuffer   bool
 	progressCB ProgressCallback
+	mtr        *metric.SyncMetric
 
 	ah  []byte // account hash
 	vlh []byte // validator list hash
@@ -108,7 +110,7 @@ func (s *syncer) newSyncProcessorWithBuilders(
 		return errors.InvalidStateError.Errorf("InvalidState(No Reactors)")
 	}
 
-	progress := newProgressSum(len(stateBuilders)+len(btpBuilders), s.progressCB)
+	progress := newProgressSum(len(stateBuilders)+len(btpBuilders), s.onProgress)
 
 	for _, builder := range stateBuilders {
 		// sync processor with v1,v2 protocol
@@ -205,6 +207,16 @@ func (s *syncer) SetProgressCallback(on ProgressCallback) {
 	s.progressCB = on
 }
 
+func (s *syncer) onProgress(r, u int) error {
+	if s.mtr != nil {
+		s.mtr.OnProgress(r, u)
+	}
+	if s.progressCB != nil {
+		return s.progressCB(r, u)
+	}
+	return nil
+}
+
 func (s *syncer) IsForceSyncing() bool {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -219,9 +231,11 @@ func (s *syncer) IsForceSyncing() bool {
 }
 
 func newSyncerWithHashes(database db.Database, reactors []SyncReactor, plt Platform,
-	ah, prh, nrh, vlh, ed, bh []byte, logger log.Logger, noBuffer bool) Syncer {
+	ah, prh, nrh, vlh, ed, bh []byte, logger log.Logger, noBuffer bool,
+	mtr *metric.SyncMetric) Syncer {
 	s := &syncer{
 		logger:   logger,
+		mtr:      mtr,
 		database: database,
 		noBuffer: noBuffer,
 		reactors: reactors,
@@ -388,6 +388,9 @@ func (s *syncProcessor) HandleData(reqID uint32, sender *peer, data []BucketIDAn
 	}
 
 	s.logger.Tracef("HandleData() reqID=%d data=%d received=%d hasError=%v", reqID, len(data), received, hasError)
+	if hasError {
+		sender.ReportInvalidData()
+	}
 	if len(data) > 0 && !hasError {
 		s.reportProgressInLock(false)
 		s.readyPool.push(p)
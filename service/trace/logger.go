@@ -230,6 +230,18 @@ func (l *Logger) OnBalanceChange(opType module.OpType, from, to module.Address,
 	}
 }
 
+func (l *Logger) OnStateChange(
+	ct module.StateChangeType, addr module.Address, key, oldValue, newValue []byte,
+) {
+	if l.TraceMode() != module.TraceModeStateDiff {
+		return
+	}
+	if err := l.cb.OnStateChange(ct, addr, key, oldValue, newValue); err != nil {
+		l.Warnf("OnStateChange() error: type=%d addr=%s key=%#x err=%#v",
+			ct, addr, key, err)
+	}
+}
+
 func NewLogger(l log.Logger, ti *module.TraceInfo) *Logger {
 	tlog := &Logger{
 		Logger: l,
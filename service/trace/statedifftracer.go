@@ -0,0 +1,235 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+var stateChangeTypeNames = []string{
+	"storage",
+	"balance",
+	"code",
+	"status",
+}
+
+func stateChangeTypeToString(ct module.StateChangeType) string {
+	return stateChangeTypeNames[ct]
+}
+
+type stateChange struct {
+	depth    int
+	ct       module.StateChangeType
+	addr     module.Address
+	key      []byte
+	oldValue []byte
+	newValue []byte
+}
+
+func (c *stateChange) valueToJSON(v []byte) interface{} {
+	switch c.ct {
+	case module.StateChangeBalance:
+		balance := new(common.HexInt)
+		balance.SetBytes(v)
+		return balance
+	case module.StateChangeStatus:
+		return string(v)
+	default:
+		return common.HexBytes(v)
+	}
+}
+
+func (c *stateChange) toJSON() map[string]interface{} {
+	jso := map[string]interface{}{
+		"depth":   fmt.Sprintf("%#x", c.depth),
+		"type":    stateChangeTypeToString(c.ct),
+		"address": c.addr,
+	}
+	if c.key != nil {
+		if c.ct == module.StateChangeStorage {
+			jso["key"] = common.HexBytes(c.key)
+		} else {
+			jso["key"] = string(c.key)
+		}
+	}
+	if c.oldValue != nil {
+		jso["old"] = c.valueToJSON(c.oldValue)
+	}
+	if c.newValue != nil {
+		jso["new"] = c.valueToJSON(c.newValue)
+	}
+	return jso
+}
+
+type diffFrame struct {
+	parent  *diffFrame
+	depth   int
+	changes []*stateChange
+}
+
+func (f *diffFrame) mergeChangesToParent() {
+	if f.parent == nil {
+		return
+	}
+	f.parent.changes = append(f.parent.changes, f.changes...)
+}
+
+type diffTransaction struct {
+	index     int
+	hash      []byte
+	isBlockTx bool
+	*diffFrame
+}
+
+func (t *diffTransaction) toJSON() map[string]interface{} {
+	if len(t.changes) == 0 {
+		return nil
+	}
+	changes := make([]interface{}, len(t.changes))
+	for i, c := range t.changes {
+		changes[i] = c.toJSON()
+	}
+	prefix := "0x"
+	if t.isBlockTx {
+		prefix = "bx"
+	}
+	return map[string]interface{}{
+		"txIndex": fmt.Sprintf("%#x", t.index),
+		"txHash":  prefix + hex.EncodeToString(t.hash),
+		"changes": changes,
+	}
+}
+
+// StateDiffTracer collects changes of the world state for each transaction.
+// Changes in the frames failed are dropped.
+type StateDiffTracer struct {
+	txs      []*diffTransaction
+	curFrame *diffFrame
+}
+
+func (st *StateDiffTracer) getCurrentTx() (*diffTransaction, error) {
+	txCount := len(st.txs)
+	if txCount == 0 {
+		return nil, errors.InvalidStateError.New("No transaction")
+	}
+	return st.txs[txCount-1], nil
+}
+
+func (st *StateDiffTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if st.curFrame != nil {
+		return errors.InvalidStateError.Errorf(
+			"Invalid curFrame: txIndex=%d txHash=%#x curFrame=%#v",
+			txIndex, txHash, st.curFrame)
+	}
+	frame := &diffFrame{}
+	tx := &diffTransaction{index: txIndex, hash: txHash, isBlockTx: isBlockTx, diffFrame: frame}
+	st.txs = append(st.txs, tx)
+	st.curFrame = frame
+	return nil
+}
+
+func (st *StateDiffTracer) OnTransactionReset() error {
+	curTx, err := st.getCurrentTx()
+	if err != nil {
+		return err
+	}
+	frame := &diffFrame{}
+	curTx.diffFrame = frame
+	st.curFrame = frame
+	return nil
+}
+
+func (st *StateDiffTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	curTx, err := st.getCurrentTx()
+	if err != nil {
+		return err
+	}
+	if curTx.index != txIndex {
+		return errors.InvalidStateError.Errorf(
+			"InvalidTxIndex(cur=%d,index=%d)", curTx.index, txIndex)
+	}
+	if st.curFrame == nil || st.curFrame.depth != 0 {
+		return errors.InvalidStateError.New("InvalidCallFrame")
+	}
+	st.curFrame = nil
+	return nil
+}
+
+func (st *StateDiffTracer) OnFrameEnter() error {
+	if st.curFrame == nil {
+		return errors.InvalidStateError.Errorf("StateDiffTracer Not Ready")
+	}
+	parent := st.curFrame
+	st.curFrame = &diffFrame{
+		parent: parent,
+		depth:  parent.depth + 1,
+	}
+	return nil
+}
+
+func (st *StateDiffTracer) OnFrameExit(success bool) error {
+	curFrame := st.curFrame
+	if curFrame == nil {
+		return errors.InvalidStateError.New("curFrame Not Ready")
+	}
+	if curFrame.depth <= 0 {
+		return errors.InvalidStateError.Errorf("Invalid frameDepth: %d", curFrame.depth)
+	}
+	if success {
+		curFrame.mergeChangesToParent()
+	}
+	st.curFrame = curFrame.parent
+	return nil
+}
+
+func (st *StateDiffTracer) OnStateChange(
+	ct module.StateChangeType, addr module.Address, key, oldValue, newValue []byte,
+) error {
+	if st.curFrame == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	st.curFrame.changes = append(st.curFrame.changes, &stateChange{
+		depth:    st.curFrame.depth,
+		ct:       ct,
+		addr:     addr,
+		key:      key,
+		oldValue: oldValue,
+		newValue: newValue,
+	})
+	return nil
+}
+
+func (st *StateDiffTracer) ToJSON() interface{} {
+	jso := make([]interface{}, 0, len(st.txs))
+	for _, tx := range st.txs {
+		if txJso := tx.toJSON(); txJso != nil {
+			jso = append(jso, txJso)
+		}
+	}
+	return jso
+}
+
+func NewStateDiffTracer(capacity int) *StateDiffTracer {
+	return &StateDiffTracer{
+		txs: make([]*diffTransaction, 0, capacity),
+	}
+}
@@ -0,0 +1,68 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestStateDiffTracer_Basic(t *testing.T) {
+	st := NewStateDiffTracer(1)
+	txHash := newRandomHash(32)
+	addr := common.MustNewAddressFromString("cx101")
+
+	assert.NoError(t, st.OnTransactionStart(0, txHash, false))
+	assert.NoError(t, st.OnStateChange(module.StateChangeBalance, addr, nil,
+		intconv.Int64ToBytes(10), intconv.Int64ToBytes(20)))
+
+	// changes in the successful frame are kept
+	assert.NoError(t, st.OnFrameEnter())
+	assert.NoError(t, st.OnStateChange(module.StateChangeStorage, addr,
+		[]byte{0x01}, nil, []byte{0x02}))
+	assert.NoError(t, st.OnFrameExit(true))
+
+	// changes in the failed frame are dropped
+	assert.NoError(t, st.OnFrameEnter())
+	assert.NoError(t, st.OnStateChange(module.StateChangeStorage, addr,
+		[]byte{0x03}, nil, []byte{0x04}))
+	assert.NoError(t, st.OnFrameExit(false))
+
+	assert.NoError(t, st.OnTransactionEnd(0, txHash))
+
+	jso, ok := st.ToJSON().([]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(jso))
+	txJso := jso[0].(map[string]interface{})
+	changes := txJso["changes"].([]interface{})
+	assert.Equal(t, 2, len(changes))
+
+	c0 := changes[0].(map[string]interface{})
+	assert.Equal(t, "balance", c0["type"])
+	assert.Equal(t, "0x14", c0["new"].(*common.HexInt).String())
+
+	c1 := changes[1].(map[string]interface{})
+	assert.Equal(t, "storage", c1["type"])
+	assert.Equal(t, "0x1", c1["depth"])
+	assert.Equal(t, common.HexBytes{0x01}, c1["key"])
+}
+
+func TestStateDiffTracer_Reset(t *testing.T) {
+	st := NewStateDiffTracer(1)
+	txHash := newRandomHash(32)
+	addr := common.MustNewAddressFromString("hx100")
+
+	assert.NoError(t, st.OnTransactionStart(0, txHash, false))
+	assert.NoError(t, st.OnStateChange(module.StateChangeBalance, addr, nil,
+		intconv.Int64ToBytes(10), intconv.Int64ToBytes(0)))
+	assert.NoError(t, st.OnTransactionReset())
+	assert.NoError(t, st.OnTransactionEnd(0, txHash))
+
+	jso := st.ToJSON().([]interface{})
+	assert.Equal(t, 0, len(jso))
+
+	assert.Error(t, st.OnFrameEnter())
+}
@@ -146,3 +146,12 @@ func Unwrap(t module.Transaction) module.Transaction {
 		return t
 	}
 }
+
+// StepLimitOf returns the step limit of the transaction. It returns nil if
+// the transaction doesn't have its own step limit (ex. v2 transactions).
+func StepLimitOf(t module.Transaction) *big.Int {
+	if tx, ok := Unwrap(t).(interface{ stepLimit() *big.Int }); ok {
+		return tx.stepLimit()
+	}
+	return nil
+}
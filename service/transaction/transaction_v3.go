@@ -338,6 +338,10 @@ func (tx *transactionV3) Nonce() *big.Int {
 	return nil
 }
 
+func (tx *transactionV3) stepLimit() *big.Int {
+	return &tx.StepLimit.Int
+}
+
 func (tx *transactionV3) To() module.Address {
 	return &tx.transactionV3Data.To
 }
@@ -20,14 +20,23 @@ type transactionList struct {
 	listFront *txElement
 	listBack  *txElement
 
-	idMap        []map[string]*txElement
-	srcMapToLast []map[string]*txElement
+	idMap   []map[string]*txElement
+	senders []map[string]*txSender
+}
+
+// txSender is the queue of transactions from a sender ordered by timestamp.
+// Transactions with nonce are also indexed by the nonce.
+type txSender struct {
+	last   *txElement
+	count  int
+	nonces map[string]*txElement
 }
 
 type txElement struct {
 	value transaction.Transaction
 	ts    int64
 	err   error
+	nonce string
 
 	list               *transactionList
 	listNext, listPrev *txElement
@@ -112,6 +121,45 @@ func (b *txBloomElement) GetBloom() *TxBloom {
 	return bloom
 }
 
+// nonceKeyOf returns the key for the nonce of the transaction. Only v3
+// transactions with nonce have the key.
+func nonceKeyOf(tx transaction.Transaction) string {
+	if tx.Version() != module.TransactionVersion3 {
+		return ""
+	}
+	if nonce := tx.Nonce(); nonce != nil {
+		return nonce.String()
+	}
+	return ""
+}
+
+func (l *transactionList) senderOf(from module.Address) *txSender {
+	uidBk, uidSlot := indexAndBucketKeyFromKey(string(from.ID()))
+	return l.senders[uidBk][uidSlot]
+}
+
+// CountOf returns the number of transactions from the sender.
+func (l *transactionList) CountOf(from module.Address) int {
+	if s := l.senderOf(from); s != nil {
+		return s.count
+	}
+	return 0
+}
+
+// GetByNonce returns the element of the transaction from the same sender
+// with the same nonce. It returns nil if there is no such transaction or
+// the transaction doesn't have nonce.
+func (l *transactionList) GetByNonce(tx transaction.Transaction) *txElement {
+	key := nonceKeyOf(tx)
+	if len(key) == 0 {
+		return nil
+	}
+	if s := l.senderOf(tx.From()); s != nil {
+		return s.nonces[key]
+	}
+	return nil
+}
+
 func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 	tidBk, tidSlot := indexAndBucketKeyFromKey(string(tx.ID()))
 	if _, ok := l.idMap[tidBk][tidSlot]; ok {
@@ -121,6 +169,7 @@ func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 	e := &txElement{
 		value: tx,
 		list:  l,
+		nonce: nonceKeyOf(tx),
 	}
 	if ts {
 		e.ts = time.Now().UnixNano()
@@ -129,10 +178,20 @@ func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 	l.idMap[tidBk][tidSlot] = e
 
 	uidBk, uidSlot := indexAndBucketKeyFromKey(string(tx.From().ID()))
-	t2, ok := l.srcMapToLast[uidBk][uidSlot]
+	sender, ok := l.senders[uidBk][uidSlot]
+	if !ok {
+		sender = &txSender{
+			nonces: make(map[string]*txElement),
+		}
+		l.senders[uidBk][uidSlot] = sender
+	}
+	sender.count += 1
+	if len(e.nonce) > 0 {
+		sender.nonces[e.nonce] = e
+	}
 
 	var insertPos *txElement
-	if ok {
+	if t2 := sender.last; t2 != nil {
 		ts := tx.Timestamp()
 		if t2.value.Timestamp() > ts {
 			insertPos = t2
@@ -150,12 +209,12 @@ func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 			e.srcNext = insertPos
 			insertPos.srcPrev = e
 		} else {
-			l.srcMapToLast[uidBk][uidSlot] = e
+			sender.last = e
 			e.srcPrev = t2
 			t2.srcNext = e
 		}
 	} else {
-		l.srcMapToLast[uidBk][uidSlot] = e
+		sender.last = e
 	}
 
 	if insertPos != nil {
@@ -210,12 +269,16 @@ func (l *transactionList) Remove(t *txElement) bool {
 	t.listPrev = nil
 
 	uidBk, uidSlot := indexAndBucketKeyFromKey(string(t.value.From().ID()))
-	t2 := l.srcMapToLast[uidBk][uidSlot]
-	if t2 == t {
-		if t.srcPrev != nil {
-			l.srcMapToLast[uidBk][uidSlot] = t.srcPrev
-		} else {
-			delete(l.srcMapToLast[uidBk], uidSlot)
+	if sender := l.senders[uidBk][uidSlot]; sender != nil {
+		sender.count -= 1
+		if len(t.nonce) > 0 && sender.nonces[t.nonce] == t {
+			delete(sender.nonces, t.nonce)
+		}
+		if sender.last == t {
+			sender.last = t.srcPrev
+		}
+		if sender.count == 0 {
+			delete(l.senders[uidBk], uidSlot)
 		}
 	}
 	if t.srcPrev != nil {
@@ -260,10 +323,10 @@ func newTransactionList() *transactionList {
 	l := new(transactionList)
 
 	l.idMap = make([]map[string]*txElement, txBucketCount)
-	l.srcMapToLast = make([]map[string]*txElement, txBucketCount)
+	l.senders = make([]map[string]*txSender, txBucketCount)
 	for i := 0; i < txBucketCount; i++ {
 		l.idMap[i] = make(map[string]*txElement)
-		l.srcMapToLast[i] = make(map[string]*txElement)
+		l.senders[i] = make(map[string]*txSender)
 	}
 	return l
 }
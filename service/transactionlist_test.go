@@ -16,6 +16,7 @@ type mockTransaction struct {
 	id        []byte
 	from      module.Address
 	timeStamp int64
+	nonce     *big.Int
 }
 
 func (*mockTransaction) Group() module.TransactionGroup {
@@ -43,7 +44,7 @@ func (*mockTransaction) Verify() error {
 }
 
 func (*mockTransaction) Version() int {
-	panic("implement me")
+	return module.TransactionVersion3
 }
 
 func (*mockTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
@@ -62,8 +63,8 @@ func (t *mockTransaction) Timestamp() int64 {
 	return t.timeStamp
 }
 
-func (*mockTransaction) Nonce() *big.Int {
-	panic("implement me")
+func (t *mockTransaction) Nonce() *big.Int {
+	return t.nonce
 }
 
 func (t *mockTransaction) To() module.Address {
@@ -212,3 +213,47 @@ func TestTransactionList_TestSort(t *testing.T) {
 		t.Errorf("First item should be tx4 but tx=%x", tx.ID())
 	}
 }
+
+func TestTransactionList_Nonce(t *testing.T) {
+	from1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	from2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	tx1 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x01}, from1, 1)
+	tx1.nonce = big.NewInt(1)
+	tx2 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x02}, from1, 2)
+	tx2.nonce = big.NewInt(1)
+	tx3 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x03}, from2, 1)
+	tx3.nonce = big.NewInt(1)
+	tx4 := newMockTransaction([]byte{0x00, 0x00, 0x00, 0x04}, from2, 2)
+
+	l := newTransactionList()
+	l.Add(tx1, false)
+	l.Add(tx4, false)
+	if e := l.GetByNonce(tx2); e == nil || e.Value() != tx1 {
+		t.Error("It should return tx1 for the same nonce")
+	}
+	if e := l.GetByNonce(tx3); e != nil {
+		t.Error("It should not return transaction of other sender")
+	}
+	if e := l.GetByNonce(tx4); e != nil {
+		t.Error("It should not return transaction without nonce")
+	}
+	if cnt := l.CountOf(from1); cnt != 1 {
+		t.Errorf("Invalid count for from1 cnt=%d", cnt)
+	}
+
+	l.Add(tx2, false)
+	if cnt := l.CountOf(from1); cnt != 2 {
+		t.Errorf("Invalid count for from1 cnt=%d", cnt)
+	}
+	l.RemoveTx(tx1)
+	if e := l.GetByNonce(tx1); e == nil || e.Value() != tx2 {
+		t.Error("It should keep the latest one for the nonce")
+	}
+	l.RemoveTx(tx2)
+	if e := l.GetByNonce(tx1); e != nil {
+		t.Error("It should return nil after removal")
+	}
+	if cnt := l.CountOf(from1); cnt != 0 {
+		t.Errorf("Invalid count for from1 cnt=%d", cnt)
+	}
+}
@@ -29,6 +29,11 @@ type TransactionManager struct {
 	callback func()
 
 	txWaiters map[hashValue][]chan<- interface{}
+	watchers  []*txPoolWatcher
+}
+
+type txPoolWatcher struct {
+	module.TransactionPoolWatcher
 }
 
 func (m *TransactionManager) getTxPool(g module.TransactionGroup) *TransactionPool {
@@ -125,13 +130,12 @@ func (m *TransactionManager) removeWaitersInLock(id []byte) []chan<- interface{}
 
 type TxDrop struct {
 	ID  []byte
+	Tx  transaction.Transaction
 	Err error
 }
 
 func (m *TransactionManager) OnTxDrops(drops []TxDrop) {
 	m.lock.Lock()
-	defer m.lock.Unlock()
-
 	for _, drop := range drops {
 		ws := m.removeWaitersInLock(drop.ID)
 		for _, c := range ws {
@@ -139,21 +143,79 @@ func (m *TransactionManager) OnTxDrops(drops []TxDrop) {
 			close(c)
 		}
 	}
+	watchers := m.watchersInLock()
+	m.lock.Unlock()
+
+	for _, drop := range drops {
+		for _, w := range watchers {
+			w.OnTransactionDrop(drop.Tx, drop.Err)
+		}
+	}
 }
 
-func (m *TransactionManager) AddAndWait(tx transaction.Transaction) (
-	<-chan interface{}, error,
-) {
+// watchersInLock returns a copy of the watchers, so that they can be
+// notified after releasing the lock.
+func (m *TransactionManager) watchersInLock() []*txPoolWatcher {
+	if len(m.watchers) == 0 {
+		return nil
+	}
+	return append([]*txPoolWatcher(nil), m.watchers...)
+}
+
+// onAdded notifies the watchers of the transaction added to the pool and
+// the transactions dropped by the transaction. It must be called without
+// holding the lock.
+func (m *TransactionManager) onAdded(watchers []*txPoolWatcher, tx transaction.Transaction, drops []TxDrop) {
+	if len(drops) > 0 {
+		m.OnTxDrops(drops)
+	}
+	for _, w := range watchers {
+		w.OnTransactionAdd(tx)
+	}
+}
+
+// Watch registers the watcher for the pools. It returns the function
+// to unregister the watcher.
+func (m *TransactionManager) Watch(w module.TransactionPoolWatcher) func() {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	if err := m.addInLock(tx, true); err != nil {
-		if err != ErrDuplicateTransaction {
-			return nil, err
+	tw := &txPoolWatcher{w}
+	m.watchers = append(m.watchers, tw)
+	return func() {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		for i, w := range m.watchers {
+			if w == tw {
+				last := len(m.watchers) - 1
+				m.watchers[i] = m.watchers[last]
+				m.watchers[last] = nil
+				m.watchers = m.watchers[:last]
+				return
+			}
 		}
 	}
+}
+
+func (m *TransactionManager) AddAndWait(tx transaction.Transaction) (
+	<-chan interface{}, error,
+) {
+	m.lock.Lock()
+	drops, err := m.addInLock(tx, true)
+	if err != nil && err != ErrDuplicateTransaction {
+		m.lock.Unlock()
+		return nil, err
+	}
 	rc := make(chan interface{}, 1)
 	m.addWaiterInLock(tx.ID(), rc)
+	var watchers []*txPoolWatcher
+	if err == nil {
+		watchers = m.watchersInLock()
+	}
+	m.lock.Unlock()
+
+	m.onAdded(watchers, tx, drops)
 	return rc, nil
 }
 
@@ -186,8 +248,16 @@ func (m *TransactionManager) Add(tx transaction.Transaction, direct bool, verifi
 		}
 	}
 	m.lock.Lock()
-	defer m.lock.Unlock()
-	return m.addInLock(tx, direct)
+	drops, err := m.addInLock(tx, direct)
+	if err != nil {
+		m.lock.Unlock()
+		return err
+	}
+	watchers := m.watchersInLock()
+	m.lock.Unlock()
+
+	m.onAdded(watchers, tx, drops)
+	return nil
 }
 
 func (m *TransactionManager) VerifyTx(tx transaction.Transaction) error {
@@ -204,21 +274,22 @@ func (m *TransactionManager) VerifyTx(tx transaction.Transaction) error {
 	}
 	return nil
 }
-func (m *TransactionManager) addInLock(tx transaction.Transaction, direct bool) error {
+func (m *TransactionManager) addInLock(tx transaction.Transaction, direct bool) ([]TxDrop, error) {
 	if err := m.tim.CheckTXForAdd(tx); err != nil {
-		return err
+		return nil, err
 	}
 
 	pool := m.getTxPool(tx.Group())
-	if err := pool.Add(tx, direct); err != nil {
-		return err
+	drops, err := pool.add(tx, direct)
+	if err != nil {
+		return nil, err
 	}
 	if m.callback != nil {
 		cb := m.callback
 		m.callback = nil
 		go cb()
 	}
-	return nil
+	return drops, nil
 }
 
 func (m *TransactionManager) Wait(wc state.WorldContext, cb func()) bool {
@@ -17,6 +17,7 @@
 package service
 
 import (
+	"math/big"
 	"sync"
 	"time"
 
@@ -32,6 +33,10 @@ const (
 	configDefaultMaxTxBytesInABlock = 1024 * 1024
 	configDefaultTxSliceCapacity    = 1024
 	configDefaultMaxTxCount         = 1500
+
+	// minimum increase of step limit in percent for replacing
+	// the transaction with the same nonce.
+	txReplacementBumpPercent = 10
 )
 
 type Monitor interface {
@@ -39,6 +44,7 @@ type Monitor interface {
 	OnAddTx(n int, user bool)
 	OnRemoveTx(n int, user bool)
 	OnCommit(id []byte, ts time.Time, d time.Duration)
+	OnPoolCapacityUpdated(size, used int)
 }
 
 type TxWaiterManager interface {
@@ -64,8 +70,9 @@ func (m dummyPoolCapacityMonitor) OnPoolCapacityUpdated(group module.Transaction
 type TransactionPool struct {
 	group module.TransactionGroup
 
-	size int
-	tim  TXIDManager
+	size        int
+	senderLimit int
+	tim         TXIDManager
 
 	list *transactionList
 
@@ -88,9 +95,16 @@ func NewTransactionPool(group module.TransactionGroup, size int, tim TXIDManager
 		pcm:     dummyPoolCapacityMonitor{},
 		log:     log,
 	}
+	m.OnPoolCapacityUpdated(size, 0)
 	return pool
 }
 
+func (tp *TransactionPool) onCapacityUpdatedInLock() {
+	used := tp.list.Len()
+	tp.monitor.OnPoolCapacityUpdated(tp.size, used)
+	tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, used)
+}
+
 func (tp *TransactionPool) DropOldTXs(bts int64) {
 	lock := common.LockForAutoCall(&tp.mutex)
 	defer lock.Unlock()
@@ -110,7 +124,7 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 					"ExpiredTransaction(diff=%s)", TimestampToDuration(bts-tx.Timestamp()))
 			}
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), iter.err)
-			drops = append(drops, TxDrop{tx.ID(), iter.err})
+			drops = append(drops, TxDrop{tx.ID(), tx, iter.err})
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
 		}
 		iter = next
@@ -119,7 +133,7 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 		tp.txm.OnTxDrops(drops)
 	})
 	// go tp.txm.OnTxDrops(drops)
-	tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+	tp.onCapacityUpdatedInLock()
 }
 
 // It returns all candidates for a negative integer n.
@@ -219,22 +233,95 @@ func (tp *TransactionPool) CheckTxs(wc state.WorldContext) bool {
 	return ErrTransactionPoolOverFlow if pool is full
 */
 func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
+	drops, err := tp.add(tx, direct)
+	if len(drops) > 0 {
+		tp.txm.OnTxDrops(drops)
+	}
+	return err
+}
+
+// minReplacementStepLimit returns the minimum step limit of the transaction
+// replacing the transaction with the step limit.
+func minReplacementStepLimit(limit *big.Int) *big.Int {
+	bump := new(big.Int).Mul(limit, big.NewInt(txReplacementBumpPercent))
+	bump.Div(bump, big.NewInt(100))
+	if bump.Sign() == 0 {
+		bump.SetInt64(1)
+	}
+	return bump.Add(bump, limit)
+}
+
+// add adds the transaction to the pool. It returns the transaction replaced
+// by the new one, and the caller should call OnTxDrops of TxWaiterManager
+// with them without holding any lock.
+func (tp *TransactionPool) add(tx transaction.Transaction, direct bool) ([]TxDrop, error) {
 	if tx == nil {
-		return nil
+		return nil, nil
 	}
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
 
-	if tp.list.Len() >= tp.size {
-		return ErrTransactionPoolOverFlow
+	if tp.list.HasTx(tx.ID()) {
+		return nil, ErrDuplicateTransaction
 	}
 
-	err := tp.list.Add(tx, direct)
-	if err == nil {
-		tp.monitor.OnAddTx(len(tx.Bytes()), direct)
-		tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+	// ICON transactions don't have their own step price, and the fee is
+	// stepUsed * stepPrice of the network, so the replacement doesn't make
+	// the sender pay more. A transaction with the same nonce may replace the
+	// old one only if its step limit is higher than the old one by
+	// txReplacementBumpPercent. It only makes the sender commit more
+	// balance for the transaction (stepLimit * stepPrice is checked against
+	// the balance on execution), so it shouldn't be taken as the cost of
+	// the replacement.
+	old := tp.list.GetByNonce(tx)
+	if old != nil {
+		oldLimit := transaction.StepLimitOf(old.Value())
+		newLimit := transaction.StepLimitOf(tx)
+		if oldLimit == nil || newLimit == nil {
+			return nil, ReplacementUnderpricedError.Errorf(
+				"ReplacementUnderpriced(old=%#x)", old.Value().ID())
+		}
+		if minLimit := minReplacementStepLimit(oldLimit); newLimit.Cmp(minLimit) < 0 {
+			return nil, ReplacementUnderpricedError.Errorf(
+				"ReplacementUnderpriced(old=%#x,min=%s)", old.Value().ID(), minLimit)
+		}
+	} else {
+		if tp.list.Len() >= tp.size {
+			return nil, ErrTransactionPoolOverFlow
+		}
+		if tp.senderLimit > 0 && tp.list.CountOf(tx.From()) >= tp.senderLimit {
+			return nil, TransactionPoolOverflowError.Errorf(
+				"TooManyTransactions(from=%s,limit=%d)", tx.From(), tp.senderLimit)
+		}
 	}
-	return err
+
+	if err := tp.list.Add(tx, direct); err != nil {
+		return nil, err
+	}
+	tp.monitor.OnAddTx(len(tx.Bytes()), direct)
+	var drops []TxDrop
+	if old != nil {
+		drops = tp.replaceInLock(old, tx)
+	}
+	tp.onCapacityUpdatedInLock()
+	return drops, nil
+}
+
+// replaceInLock drops the old transaction replaced by the new one.
+// Nonce isn't checked by the chain, so the replacement is only effective
+// in the pool of this node. The old one may still be included in a block
+// by other nodes.
+func (tp *TransactionPool) replaceInLock(old *txElement, tx transaction.Transaction) []TxDrop {
+	if !tp.list.Remove(old) {
+		return nil
+	}
+	otx := old.Value()
+	old.err = ReplacedTransactionError.Errorf(
+		"ReplacedTransaction(by=%#x)", tx.ID())
+	tp.log.Debugf("DROP TX: id=0x%x reason=%v", otx.ID(), old.err)
+	tp.monitor.OnDropTx(len(otx.Bytes()), old.ts != 0)
+	tp.tim.AddDroppedTX(otx.ID(), otx.Timestamp())
+	return []TxDrop{{otx.ID(), otx, old.err}}
 }
 
 // removeList remove transactions when transactions are finalized.
@@ -267,7 +354,7 @@ func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 	}
 
 	if count > 0 {
-		tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+		tp.onCapacityUpdatedInLock()
 		tp.monitor.OnCommit(txs.Hash(), now, duration/time.Duration(count))
 	} else {
 		tp.monitor.OnCommit(txs.Hash(), now, 0)
@@ -292,6 +379,15 @@ func (tp *TransactionPool) Used() int {
 	return tp.list.Len()
 }
 
+// SetSenderLimit sets the maximum number of transactions from a sender.
+// Zero or negative value means no limit.
+func (tp *TransactionPool) SetSenderLimit(limit int) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	tp.senderLimit = limit
+}
+
 func (tp *TransactionPool) SetTxManager(txm TxWaiterManager) {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
@@ -327,7 +423,7 @@ func (tp *TransactionPool) dropTransactions(txs []*txElement) {
 				tp.log.Panicf("No reason to drop the tx=<%#x>", tx.ID())
 			}
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), e.err)
-			drops = append(drops, TxDrop{tx.ID(), e.err})
+			drops = append(drops, TxDrop{tx.ID(), tx, e.err})
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
 		}
 	}
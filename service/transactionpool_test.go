@@ -1,6 +1,8 @@
 package service
 
 import (
+	"encoding/base64"
+	"fmt"
 	"testing"
 	"time"
 
@@ -8,6 +10,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
 )
 
 type mockMonitor struct {
@@ -29,6 +32,10 @@ func (m *mockMonitor) OnCommit(id []byte, ts time.Time, d time.Duration) {
 	// do nothing
 }
 
+func (m *mockMonitor) OnPoolCapacityUpdated(size, used int) {
+	// do nothing
+}
+
 func TestTransactionPool_Add(t *testing.T) {
 	dbase := db.NewMapDB()
 	tsc := NewTimestampChecker()
@@ -43,3 +50,163 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+func newTestTransactionV3(t *testing.T, from string, ts, nonce, stepLimit int64) transaction.Transaction {
+	sig := base64.StdEncoding.EncodeToString(make([]byte, 65))
+	js := fmt.Sprintf(`{
+		"version": "0x3",
+		"from": "%s",
+		"to": "hx0000000000000000000000000000000000000000",
+		"stepLimit": "%#x",
+		"timestamp": "%#x",
+		"nid": "0x1",
+		"nonce": "%#x",
+		"signature": "%s"
+	}`, from, stepLimit, ts, nonce, sig)
+	tx, err := transaction.NewTransactionFromJSON([]byte(js))
+	if err != nil {
+		t.Fatalf("Fail to make transaction err=%+v", err)
+	}
+	return tx
+}
+
+func TestTransactionPool_Replace(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc, nil)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, log.New())
+
+	from := "hx1111111111111111111111111111111111111111"
+	tx1 := newTestTransactionV3(t, from, 1, 1, 1000)
+	if err := pool.Add(tx1, true); err != nil {
+		t.Fatalf("Fail to add transaction err=%+v", err)
+	}
+
+	tx2 := newTestTransactionV3(t, from, 2, 1, 1000)
+	if err := pool.Add(tx2, true); !ReplacementUnderpricedError.Equals(err) {
+		t.Errorf("It should return ReplacementUnderpriced err=%+v", err)
+	}
+
+	tx2 = newTestTransactionV3(t, from, 2, 1, 1001)
+	if err := pool.Add(tx2, true); !ReplacementUnderpricedError.Equals(err) {
+		t.Errorf("It should return ReplacementUnderpriced for small bump err=%+v", err)
+	}
+
+	tx3 := newTestTransactionV3(t, from, 3, 1, 1100)
+	if err := pool.Add(tx3, true); err != nil {
+		t.Fatalf("Fail to replace transaction err=%+v", err)
+	}
+	if pool.HasTx(tx1.ID()) || !pool.HasTx(tx3.ID()) {
+		t.Error("The old transaction should be replaced by the new one")
+	}
+	if used := pool.Used(); used != 1 {
+		t.Errorf("Invalid pool usage used=%d", used)
+	}
+
+	tx4 := newTestTransactionV3(t, from, 4, 2, 1000)
+	if err := pool.Add(tx4, true); err != nil {
+		t.Fatalf("Fail to add transaction with other nonce err=%+v", err)
+	}
+	if used := pool.Used(); used != 2 {
+		t.Errorf("Invalid pool usage used=%d", used)
+	}
+}
+
+func TestTransactionPool_SenderLimit(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc, nil)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, log.New())
+	pool.SetSenderLimit(2)
+
+	from1 := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	from2 := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+	for i := 0; i < 2; i++ {
+		tx := newMockTransaction([]byte{0x01, byte(i)}, from1, int64(i))
+		if err := pool.Add(tx, true); err != nil {
+			t.Fatalf("Fail to add transaction err=%+v", err)
+		}
+	}
+	tx := newMockTransaction([]byte{0x01, 0x02}, from1, 2)
+	if err := pool.Add(tx, true); !TransactionPoolOverflowError.Equals(err) {
+		t.Errorf("It should return TransactionPoolOverflow err=%+v", err)
+	}
+	tx = newMockTransaction([]byte{0x02, 0x00}, from2, 0)
+	if err := pool.Add(tx, true); err != nil {
+		t.Errorf("Fail to add transaction from other sender err=%+v", err)
+	}
+}
+
+type reentrantWatcher struct {
+	tm    *TransactionManager
+	added chan error
+}
+
+func (w *reentrantWatcher) OnTransactionAdd(tx module.Transaction) {
+	_, err := w.tm.WaitResult(tx.ID())
+	w.added <- err
+}
+
+func (w *reentrantWatcher) OnTransactionDrop(tx module.Transaction, reason error) {
+}
+
+func TestTransactionManager_WatcherOutsideLock(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc, nil)
+	ptp := NewTransactionPool(module.TransactionGroupPatch, 5000, tim, &mockMonitor{}, log.New())
+	ntp := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, log.New())
+	tm := NewTransactionManager(1, tsc, ptp, ntp, tim, log.New())
+
+	w := &reentrantWatcher{tm: tm, added: make(chan error, 1)}
+	cancel := tm.Watch(w)
+	defer cancel()
+
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	tx := newMockTransaction([]byte("tx1"), addr, 1)
+	tx.NID = 1
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tm.Add(tx, true, true)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Fail to add transaction err=%+v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watcher is called while the manager is locked")
+	}
+	if err := <-w.added; err != nil {
+		t.Errorf("Fail to wait result in the watcher err=%+v", err)
+	}
+}
+
+func TestTransactionManager_ReplaceNotifiesDrop(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	tim, _ := NewTXIDManager(dbase, tsc, nil)
+	ptp := NewTransactionPool(module.TransactionGroupPatch, 5000, tim, &mockMonitor{}, log.New())
+	ntp := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, log.New())
+	tm := NewTransactionManager(1, tsc, ptp, ntp, tim, log.New())
+
+	from := "hx1111111111111111111111111111111111111111"
+	tx1 := newTestTransactionV3(t, from, 1, 1, 1000)
+	rc, err := tm.AddAndWait(tx1)
+	if err != nil {
+		t.Fatalf("Fail to add transaction err=%+v", err)
+	}
+	tx2 := newTestTransactionV3(t, from, 2, 1, 1100)
+	if err := tm.Add(tx2, true, true); err != nil {
+		t.Fatalf("Fail to replace transaction err=%+v", err)
+	}
+	select {
+	case r := <-rc:
+		if err, ok := r.(error); !ok || !ReplacedTransactionError.Equals(err) {
+			t.Errorf("It should be notified with ReplacedTransaction r=%+v", r)
+		}
+	default:
+		t.Error("Drop of the replaced transaction isn't notified on replacement")
+	}
+}
@@ -41,6 +41,7 @@ func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []by
 		if err != nil {
 			r.log.Warnf("InvalidPacket(PropagateTransaction) from=%s", peerId.String())
 			r.log.Debugf("Failed to unmarshal transaction. buf=%x, err=%+v", buf, err)
+			r.membership.ReportPeer(peerId, module.PenaltyInvalidTransaction)
 			return false, err
 		}
 
@@ -53,6 +54,7 @@ func (r *TransactionReactor) OnReceive(subProtocol module.ProtocolInfo, buf []by
 		if err != nil {
 			r.log.Warnf("InvalidPacket(ResponseTransaction) from=%s", peerId.String())
 			r.log.Debugf("Failed to unmarshal transaction. buf=%x, err=%+v", buf, err)
+			r.membership.ReportPeer(peerId, module.PenaltyInvalidTransaction)
 			return false, err
 		}
 
@@ -51,6 +51,16 @@ func newTransitionResultFromBytes(bs []byte) (*transitionResult, error) {
 	return tresult, nil
 }
 
+// StateHashFromResult returns the hash of the world state in the result of
+// the block.
+func StateHashFromResult(result []byte) ([]byte, error) {
+	tr, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return nil, err
+	}
+	return tr.StateHash, nil
+}
+
 func (tr *transitionResult) getExtensionFlags() int64 {
 	var flags int64 = 0
 	if len(tr.BTPData) > 0 {
@@ -88,6 +88,14 @@ func (c *Chain) PatchTxPoolSize() int {
 	return 2
 }
 
+func (c *Chain) TxPoolSenderLimit() int {
+	return 0
+}
+
+func (c *Chain) FastSyncWindow() int {
+	return 0
+}
+
 func (c *Chain) MaxBlockTxBytes() int {
 	return 2 * 1024 * 1024
 }
@@ -116,6 +124,10 @@ func (c *Chain) ValidateTxOnSend() bool {
 	panic("implement me")
 }
 
+func (c *Chain) EventIndex() bool {
+	return false
+}
+
 var defaultGenesis = "{\n  \"accounts\": [\n    {\n      \"name\": \"god\",\n      \"address\": \"hx54f7853dc6481b670caf69c5a27c7c8fe5be8269\",\n      \"balance\": \"0x2961fff8ca4a62327800000\"\n    },\n    {\n      \"name\": \"treasury\",\n      \"address\": \"hx1000000000000000000000000000000000000000\",\n      \"balance\": \"0x0\"\n    }\n  ],\n  \"message\": \"A rhizome has no beginning or end; it is always in the middle, between things, interbeing, intermezzo. The tree is filiation, but the rhizome is alliance, uniquely alliance. The tree imposes the verb \\\"to be\\\" but the fabric of the rhizome is the conjunction, \\\"and ... and ...and...\\\"This conjunction carries enough force to shake and uproot the verb \\\"to be.\\\" Where are you going? Where are you coming from? What are you heading for? These are totally useless questions.\\n\\n - Mille Plateaux, Gilles Deleuze & Felix Guattari\\n\\n\\\"Hyperconnect the world\\\"\"\n}\n"
 
 func (c *Chain) Genesis() []byte {
@@ -178,6 +190,18 @@ func (c *Chain) Backup(file string, extra []string) error {
 	panic("implement me")
 }
 
+func (c *Chain) BackupIncremental(file string, extra []string) error {
+	panic("implement me")
+}
+
+func (c *Chain) Export(file string, from, to int64, flags int) error {
+	panic("implement me")
+}
+
+func (c *Chain) ImportArchive(file string) error {
+	panic("implement me")
+}
+
 func (c *Chain) RunTask(task string, params json.RawMessage) error {
 	panic("implement me")
 }
@@ -202,6 +226,10 @@ func (c *Chain) Reset(gs string, height int64, blockHash []byte) error {
 	panic("implement me")
 }
 
+func (c *Chain) Bootstrap(gs string, height int64, blockHash []byte) error {
+	panic("implement me")
+}
+
 func (c *Chain) Verify() error {
 	panic("implement me")
 }
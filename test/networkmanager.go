@@ -300,3 +300,6 @@ func (h *nmHandler) Unicast(pi module.ProtocolInfo, b []byte, id module.PeerID)
 func (h *nmHandler) GetPeers() []module.PeerID {
 	return h.n.GetPeers()
 }
+
+func (h *nmHandler) ReportPeer(id module.PeerID, reason module.PenaltyReason) {
+}